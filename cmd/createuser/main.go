@@ -0,0 +1,68 @@
+// Command createuser membuat akun pengguna langsung di database, dipakai untuk
+// membuat owner pertama sebelum ada yang bisa login ke API.
+//
+// Penggunaan:
+//
+//	go run ./cmd/createuser -username admin [-role owner]
+//
+// Password dibaca dari baris pertama stdin agar tidak tersimpan di riwayat shell.
+// Koneksi database dibaca lewat config.LoadConfig, sama seperti server.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"kasir-api/config"
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+func main() {
+	username := flag.String("username", "", "nama login pengguna baru")
+	role := flag.String("role", models.RoleOwner, "role pengguna (owner, manager, cashier, stock_clerk)")
+	flag.Parse()
+
+	if *username == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("[createuser] Gagal membaca password: %v", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	cfg := config.LoadConfig()
+	db, err := sql.Open("postgres", cfg.GetDBConnectionString())
+	if err != nil {
+		log.Fatalf("[createuser] Gagal membuka koneksi database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatalf("[createuser] Gagal ping database: %v", err)
+	}
+
+	user, err := store.NewPostgresStore(db).CreateUser(ctx, models.CreateUserRequest{
+		Username: *username,
+		Password: password,
+		Role:     *role,
+	})
+	if err != nil {
+		log.Fatalf("[createuser] Gagal membuat pengguna: %v", err)
+	}
+
+	log.Printf("[createuser] Pengguna dibuat id=%d username=%s role=%s", user.ID, user.Username, user.Role)
+}
@@ -1,47 +1,127 @@
+// Command migrate menjalankan migrasi skema database dari folder migrations.
+//
+// Penggunaan:
+//
+//	go run ./cmd/migrate [-dir migrations] up
+//	go run ./cmd/migrate [-dir migrations] down [N]
+//	go run ./cmd/migrate [-dir migrations] goto V
+//	go run ./cmd/migrate [-dir migrations] status
+//	go run ./cmd/migrate [-dir migrations] force V
+//
+// Koneksi database dibaca lewat config.LoadConfig, sama seperti server.
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 
 	_ "github.com/lib/pq"
+
+	"kasir-api/config"
 )
 
 func main() {
-	// Connection string dari .env
-	connStr := "host=ep-lucky-mud-a1sn3mmx-pooler.ap-southeast-1.aws.neon.tech port=5432 user=neondb_owner password=npg_IuEdFJ37pvWj dbname=neondb sslmode=verify-full"
+	dir := flag.String("dir", "migrations", "folder berisi file *.up.sql dan *.down.sql")
+	flag.Usage = usage
+	flag.Parse()
 
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
 	}
-	defer db.Close()
+	command := flag.Arg(0)
 
-	// Test koneksi
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	migrations, err := loadMigrations(*dir)
+	if err != nil {
+		log.Fatalf("[migrate] %v", err)
 	}
 
-	fmt.Println("Connected to database. Running migration...")
+	cfg := config.LoadConfig()
+	db, err := sql.Open("postgres", cfg.GetDBConnectionString())
+	if err != nil {
+		log.Fatalf("[migrate] Gagal membuka koneksi database: %v", err)
+	}
+	defer db.Close()
 
-	// Baca file migration
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run migrate.go <migration-file>")
+	ctx := context.Background()
+	if err := db.PingContext(ctx); err != nil {
+		log.Fatalf("[migrate] Gagal ping database: %v", err)
 	}
 
-	migrationFile := os.Args[1]
-	content, err := os.ReadFile(migrationFile)
+	m, err := NewMigrator(ctx, db, migrations)
 	if err != nil {
-		log.Fatalf("Failed to read migration file: %v", err)
+		log.Fatalf("[migrate] %v", err)
 	}
 
-	// Jalankan migration
-	_, err = db.Exec(string(content))
+	err = run(ctx, m, command, flag.Args()[1:])
+	m.Close()
 	if err != nil {
-		log.Fatalf("Failed to execute migration: %v", err)
+		log.Fatalf("[migrate] %s gagal: %v", command, err)
+	}
+}
+
+// run menjalankan satu perintah migrasi berdasarkan argumen CLI.
+func run(ctx context.Context, m *Migrator, command string, args []string) error {
+	switch command {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		n := 1
+		if len(args) > 0 {
+			parsed, err := strconv.Atoi(args[0])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("jumlah langkah down tidak valid: %q", args[0])
+			}
+			n = parsed
+		}
+		return m.Down(ctx, n)
+	case "goto":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		return m.Goto(ctx, version)
+	case "force":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		return m.Force(ctx, version)
+	case "status":
+		return m.Status(ctx)
+	default:
+		usage()
+		return fmt.Errorf("perintah tidak dikenal: %s", command)
+	}
+}
+
+// versionArg membaca argumen versi untuk perintah goto dan force.
+func versionArg(args []string) (int, error) {
+	if len(args) < 1 {
+		return 0, fmt.Errorf("versi wajib diisi")
+	}
+	version, err := strconv.Atoi(args[0])
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("versi tidak valid: %q", args[0])
 	}
+	return version, nil
+}
+
+// usage mencetak cara pemakaian tool migrate.
+func usage() {
+	fmt.Fprintf(os.Stderr, `Penggunaan: migrate [-dir migrations] <perintah>
 
-	fmt.Println("Migration completed successfully!")
+Perintah:
+  up          terapkan semua migrasi yang belum diterapkan
+  down [N]    rollback N migrasi terakhir (default 1)
+  goto V      pindah ke versi V (naik atau turun), 0 berarti rollback semua
+  status      tampilkan status setiap migrasi
+  force V     tandai migrasi sampai versi V sudah diterapkan tanpa menjalankannya
+`)
+	flag.PrintDefaults()
 }
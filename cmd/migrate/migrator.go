@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationLockKey adalah key advisory lock PostgreSQL yang dipakai selama migrasi
+// berjalan, sehingga dua proses deploy tidak bisa migrasi bersamaan.
+const migrationLockKey int64 = 7203194401
+
+// migrationFilePattern mencocokkan nama file seperti 000004_create_transactions_tables.up.sql.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migration merepresentasikan satu pasang file up/down dengan nomor versi yang sama.
+type migration struct {
+	Version  int
+	Name     string
+	UpPath   string
+	DownPath string
+}
+
+// appliedMigration merepresentasikan satu baris di tabel schema_migrations.
+type appliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator menjalankan migrasi dari folder migrations di atas satu koneksi
+// yang memegang advisory lock.
+type Migrator struct {
+	conn       *sql.Conn
+	migrations []migration
+}
+
+// loadMigrations membaca semua pasangan file migrasi dari dir, urut berdasarkan versi.
+func loadMigrations(dir string) ([]migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca folder migrasi %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("versi migrasi tidak valid pada %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("versi %d dipakai oleh dua nama migrasi: %s dan %s", version, m.Name, match[2])
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if match[3] == "up" {
+			m.UpPath = path
+		} else {
+			m.DownPath = path
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpPath == "" || m.DownPath == "" {
+			return nil, fmt.Errorf("migrasi %06d_%s harus punya file .up.sql dan .down.sql", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// NewMigrator mengambil satu koneksi dari pool, mengambil advisory lock, dan
+// memastikan tabel schema_migrations ada. Panggil Close untuk melepas lock.
+func NewMigrator(ctx context.Context, db *sql.DB, migrations []migration) (*Migrator, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil koneksi: %w", err)
+	}
+
+	log.Printf("[migrate] Menunggu advisory lock key=%d", migrationLockKey)
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("gagal mengambil advisory lock: %w", err)
+	}
+
+	m := &Migrator{conn: conn, migrations: migrations}
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		m.Close()
+		return nil, fmt.Errorf("gagal membuat tabel schema_migrations: %w", err)
+	}
+
+	return m, nil
+}
+
+// Close melepas advisory lock dan mengembalikan koneksi ke pool.
+func (m *Migrator) Close() error {
+	if _, err := m.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+		log.Printf("[migrate] Warning: gagal melepas advisory lock: %v", err)
+	}
+	return m.conn.Close()
+}
+
+// applied mengembalikan migrasi yang sudah tercatat, dipetakan berdasarkan versi.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	rows, err := m.conn.QueryContext(ctx, "SELECT version, name, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("gagal membaca baris schema_migrations: %w", err)
+		}
+		applied[a.Version] = a
+	}
+
+	return applied, rows.Err()
+}
+
+// currentVersion mengembalikan versi tertinggi yang sudah diterapkan, atau 0.
+func currentVersion(applied map[int]appliedMigration) int {
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan, urut dari versi terkecil.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.upTo(ctx, -1)
+}
+
+// Down membatalkan n migrasi terakhir yang sudah diterapkan.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	versions := appliedVersionsDesc(applied)
+	if n > len(versions) {
+		n = len(versions)
+	}
+	for _, version := range versions[:n] {
+		if err := m.rollback(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	if n == 0 {
+		log.Printf("[migrate] Tidak ada migrasi untuk di-rollback")
+	}
+	return nil
+}
+
+// Goto memindahkan skema ke versi target: menerapkan migrasi yang kurang atau
+// membatalkan migrasi di atas target. Target 0 berarti rollback semua.
+func (m *Migrator) Goto(ctx context.Context, target int) error {
+	if target != 0 {
+		if _, ok := m.find(target); !ok {
+			return fmt.Errorf("versi %d tidak ditemukan di folder migrasi", target)
+		}
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range appliedVersionsDesc(applied) {
+		if version <= target {
+			break
+		}
+		if err := m.rollback(ctx, version); err != nil {
+			return err
+		}
+	}
+
+	return m.upTo(ctx, target)
+}
+
+// Force mencatat semua migrasi sampai versi target sebagai sudah diterapkan tanpa
+// menjalankan SQL-nya. Dipakai sekali untuk database lama yang dimigrasi manual.
+func (m *Migrator) Force(ctx context.Context, target int) error {
+	if _, ok := m.find(target); !ok {
+		return fmt.Errorf("versi %d tidak ditemukan di folder migrasi", target)
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version > target {
+			break
+		}
+		_, err := m.conn.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING",
+			mig.Version, mig.Name)
+		if err != nil {
+			return fmt.Errorf("gagal mencatat versi %d: %w", mig.Version, err)
+		}
+	}
+
+	log.Printf("[migrate] Versi sampai %d ditandai sudah diterapkan", target)
+	return nil
+}
+
+// Status mencetak daftar migrasi beserta status penerapannya.
+func (m *Migrator) Status(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Versi saat ini: %d\n\n", currentVersion(applied))
+	fmt.Printf("%-8s %-45s %-10s %s\n", "VERSI", "NAMA", "STATUS", "DITERAPKAN")
+	for _, mig := range m.migrations {
+		status, appliedAt := "pending", "-"
+		if a, ok := applied[mig.Version]; ok {
+			status, appliedAt = "applied", a.AppliedAt.Format(time.DateTime)
+		}
+		fmt.Printf("%06d   %-45s %-10s %s\n", mig.Version, mig.Name, status, appliedAt)
+	}
+
+	// Versi yang tercatat di database tapi filenya tidak ada perlu diperiksa manual.
+	for _, version := range appliedVersionsDesc(applied) {
+		if _, ok := m.find(version); !ok {
+			a := applied[version]
+			fmt.Printf("%06d   %-45s %-10s %s\n", a.Version, a.Name, "missing", a.AppliedAt.Format(time.DateTime))
+		}
+	}
+
+	return nil
+}
+
+// upTo menerapkan migrasi yang belum diterapkan sampai versi target (inklusif).
+// Target negatif berarti semua migrasi.
+func (m *Migrator) upTo(ctx context.Context, target int) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, mig := range m.migrations {
+		if target >= 0 && mig.Version > target {
+			break
+		}
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, mig); err != nil {
+			return err
+		}
+		count++
+	}
+
+	if count == 0 {
+		log.Printf("[migrate] Skema sudah up to date")
+	}
+	return nil
+}
+
+// apply menjalankan file up dan mencatat versinya dalam satu database transaction.
+func (m *Migrator) apply(ctx context.Context, mig migration) error {
+	log.Printf("[migrate] Up %06d_%s", mig.Version, mig.Name)
+	return m.runStep(ctx, mig.UpPath, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+		return err
+	})
+}
+
+// rollback menjalankan file down dan menghapus catatan versinya dalam satu database transaction.
+func (m *Migrator) rollback(ctx context.Context, version int) error {
+	mig, ok := m.find(version)
+	if !ok {
+		return fmt.Errorf("file down untuk versi %d tidak ditemukan", version)
+	}
+
+	log.Printf("[migrate] Down %06d_%s", mig.Version, mig.Name)
+	return m.runStep(ctx, mig.DownPath, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+		return err
+	})
+}
+
+// runStep membaca file SQL lalu menjalankannya bersama record di schema_migrations
+// dalam satu database transaction, sehingga langkah yang gagal tidak tercatat.
+func (m *Migrator) runStep(ctx context.Context, path string, record func(tx *sql.Tx) error) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %w", path, err)
+	}
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("gagal memulai transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+		return fmt.Errorf("gagal menjalankan %s: %w", filepath.Base(path), err)
+	}
+	if err := record(tx); err != nil {
+		return fmt.Errorf("gagal mencatat schema_migrations untuk %s: %w", filepath.Base(path), err)
+	}
+
+	return tx.Commit()
+}
+
+// find mencari migrasi berdasarkan versi.
+func (m *Migrator) find(version int) (migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return migration{}, false
+}
+
+// appliedVersionsDesc mengembalikan versi yang sudah diterapkan, urut dari terbesar.
+func appliedVersionsDesc(applied map[int]appliedMigration) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	return versions
+}
@@ -1,9 +1,11 @@
-package main
+// Package config memuat konfigurasi aplikasi untuk server dan tool CLI.
+package config
 
 import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -20,6 +22,17 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	DBSSLMode  string
+
+	// Lama berlaku token sesi hasil login
+	SessionTTL time.Duration
+
+	// Lama berlaku token persetujuan manajer
+	ApprovalTokenTTL time.Duration
+	// Batas potongan manual kasir (persen subtotal) tanpa persetujuan manajer
+	DiscountApprovalPercent int
+
+	// Jumlah maksimum satu produk dalam satu checkout
+	MaxLineQuantity int
 }
 
 // GetDBConnectionString mengembalikan connection string untuk PostgreSQL
@@ -49,6 +62,14 @@ func LoadConfig() *Config {
 	v.SetDefault("DB_NAME", "kasir_db")
 	v.SetDefault("DB_SSLMODE", "disable")
 
+	// Auth default values
+	v.SetDefault("SESSION_TTL", "12h")
+	v.SetDefault("APPROVAL_TOKEN_TTL", "5m")
+	v.SetDefault("DISCOUNT_APPROVAL_PERCENT", 10)
+
+	// Checkout default values
+	v.SetDefault("MAX_LINE_QUANTITY", 1000)
+
 	// Konfigurasi untuk membaca file .env
 	v.SetConfigName(".env")
 	v.SetConfigType("env")
@@ -79,6 +100,10 @@ func LoadConfig() *Config {
 	v.BindEnv("DB_PASSWORD")
 	v.BindEnv("DB_NAME")
 	v.BindEnv("DB_SSLMODE")
+	v.BindEnv("SESSION_TTL")
+	v.BindEnv("APPROVAL_TOKEN_TTL")
+	v.BindEnv("DISCOUNT_APPROVAL_PERCENT")
+	v.BindEnv("MAX_LINE_QUANTITY")
 
 	// Membaca konfigurasi
 	config := &Config{
@@ -90,6 +115,27 @@ func LoadConfig() *Config {
 		DBPassword: v.GetString("DB_PASSWORD"),
 		DBName:     v.GetString("DB_NAME"),
 		DBSSLMode:  v.GetString("DB_SSLMODE"),
+		SessionTTL: v.GetDuration("SESSION_TTL"),
+
+		ApprovalTokenTTL:        v.GetDuration("APPROVAL_TOKEN_TTL"),
+		DiscountApprovalPercent: v.GetInt("DISCOUNT_APPROVAL_PERCENT"),
+		MaxLineQuantity:         v.GetInt("MAX_LINE_QUANTITY"),
+	}
+	if config.SessionTTL <= 0 {
+		log.Printf("[config] Warning: SESSION_TTL tidak valid, memakai 12h")
+		config.SessionTTL = 12 * time.Hour
+	}
+	if config.ApprovalTokenTTL <= 0 {
+		log.Printf("[config] Warning: APPROVAL_TOKEN_TTL tidak valid, memakai 5m")
+		config.ApprovalTokenTTL = 5 * time.Minute
+	}
+	if config.DiscountApprovalPercent < 0 || config.DiscountApprovalPercent > 100 {
+		log.Printf("[config] Warning: DISCOUNT_APPROVAL_PERCENT tidak valid, memakai 10")
+		config.DiscountApprovalPercent = 10
+	}
+	if config.MaxLineQuantity <= 0 {
+		log.Printf("[config] Warning: MAX_LINE_QUANTITY tidak valid, memakai 1000")
+		config.MaxLineQuantity = 1000
 	}
 
 	log.Printf("[config] Konfigurasi dimuat - Host: %s, Port: %s", config.Host, config.Port)
@@ -0,0 +1,112 @@
+// Package handlers menyimpan HTTP handler untuk kunci API.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// APIKeyHandler menangani HTTP request untuk kunci API terminal POS dan integrasi.
+type APIKeyHandler struct {
+	store store.APIKeyStore
+}
+
+// NewAPIKeyHandler membuat APIKeyHandler dengan store yang diberikan.
+func NewAPIKeyHandler(s store.APIKeyStore) *APIKeyHandler {
+	return &APIKeyHandler{store: s}
+}
+
+// ListAPIKeys menangani GET /api/api-key.
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListAPIKeys start method=%s path=%s", r.Method, r.URL.Path)
+
+	keys, err := h.store.GetAllAPIKeys(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListAPIKeys failed err=%v", err)
+		http.Error(w, "Failed to get api keys", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListAPIKeys success count=%d", len(keys))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// CreateAPIKey menangani POST /api/api-key. Kunci utuh hanya dikirim di response ini.
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateAPIKey start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] CreateAPIKey decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	user, _ := CurrentUser(r.Context())
+	req.CreatedBy = user.Username
+	log.Printf("[flow-2] CreateAPIKey name=%q scopes=%v role=%q outlet=%q", req.Name, req.Scopes, req.Role, req.Outlet)
+
+	key, err := h.store.CreateAPIKey(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] CreateAPIKey failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateAPIKey success id=%d prefix=%s", key.ID, key.Prefix)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+// RevokeAPIKey menangani POST /api/api-key/{id}/revoke.
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RevokeAPIKey start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/api-key/", "/revoke")
+	if !ok {
+		return
+	}
+
+	key, err := h.store.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] RevokeAPIKey failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-3] RevokeAPIKey success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
+
+// RotateAPIKey menangani POST /api/api-key/{id}/rotate. Kunci baru hanya
+// dikirim di response ini dan kunci lama langsung tidak berlaku.
+func (h *APIKeyHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RotateAPIKey start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/api-key/", "/rotate")
+	if !ok {
+		return
+	}
+
+	key, err := h.store.RotateAPIKey(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] RotateAPIKey failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-3] RotateAPIKey success id=%d prefix=%s", id, key.Prefix)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
@@ -0,0 +1,197 @@
+// Package handlers menyimpan HTTP handler untuk persetujuan manajer.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// Header yang dipakai kasir untuk membawa persetujuan manajer.
+const (
+	headerManagerUsername = "X-Manager-Username"
+	headerManagerPIN      = "X-Manager-PIN"
+	headerApprovalToken   = "X-Approval-Token"
+)
+
+// ApprovalHandler menangani persetujuan manajer untuk aksi kasir.
+type ApprovalHandler struct {
+	store store.ApprovalStore
+	ttl   time.Duration
+}
+
+// NewApprovalHandler membuat ApprovalHandler dengan store dan lama berlaku token persetujuan.
+func NewApprovalHandler(s store.ApprovalStore, ttl time.Duration) *ApprovalHandler {
+	return &ApprovalHandler{store: s, ttl: ttl}
+}
+
+// approvalResolverKey adalah key context untuk persetujuan yang belum diperiksa.
+type approvalResolverKey struct{}
+
+// approvalResolver memeriksa persetujuan manajer yang dibawa request lalu
+// mengembalikan context yang membawa persetujuan tersebut.
+type approvalResolver func(ctx context.Context) (context.Context, error)
+
+// Approve membungkus handler yang sudah dilindungi Protect agar request membawa
+// persetujuan manajer untuk action. Manajer dan owner menyetujui aksinya
+// sendiri; pengguna lain mengirim header X-Manager-Username dan X-Manager-PIN,
+// atau X-Approval-Token. Persetujuan disimpan di context dan dicatat store
+// bersama aksinya. Persetujuan yang tidak valid menghasilkan 403 dan PIN yang
+// dikunci karena terlalu sering salah menghasilkan 429; jika required,
+// request tanpa persetujuan juga 403. Jika tidak, store yang memutuskan apakah
+// aksi tersebut butuh persetujuan, dan PIN/token baru diperiksa lewat
+// resolveApproval setelah store memintanya sehingga token tidak terpakai untuk
+// aksi yang tidak membutuhkannya.
+func (h *ApprovalHandler) Approve(action string, required bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, _ := CurrentUser(r.Context())
+
+		if !required && !slices.Contains(RolesManagers, user.Role) &&
+			(r.Header.Get(headerApprovalToken) != "" || r.Header.Get(headerManagerPIN) != "") {
+			log.Printf("[flow-0] Approval deferred user=%s action=%s", user.Username, action)
+			resolve := approvalResolver(func(ctx context.Context) (context.Context, error) {
+				ctx, _, err := h.approve(ctx, r.Header, user, action)
+				return ctx, err
+			})
+			next(w, r.WithContext(context.WithValue(r.Context(), approvalResolverKey{}, resolve)))
+			return
+		}
+
+		ctx, ok, err := h.approve(r.Context(), r.Header, user, action)
+		if err != nil {
+			msg, status := approvalError(err)
+			http.Error(w, msg, status)
+			return
+		}
+		if !ok && required {
+			log.Printf("[flow-0] Approval required user=%s action=%s path=%s", user.Username, action, r.URL.Path)
+			http.Error(w, "Manager approval required", http.StatusForbidden)
+			return
+		}
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// approve memeriksa persetujuan manajer dari header request lalu menyimpannya
+// di context. ok bernilai false jika request tidak membawa persetujuan.
+func (h *ApprovalHandler) approve(ctx context.Context, header http.Header, user *models.User, action string) (context.Context, bool, error) {
+	var approver *models.User
+	var method string
+	var err error
+	switch {
+	case slices.Contains(RolesManagers, user.Role):
+		approver, method = user, models.ApprovalMethodRole
+	case header.Get(headerApprovalToken) != "":
+		method = models.ApprovalMethodToken
+		approver, err = h.store.RedeemApprovalToken(ctx, header.Get(headerApprovalToken), action)
+	case header.Get(headerManagerPIN) != "":
+		method = models.ApprovalMethodPIN
+		approver, err = h.store.VerifyManagerPIN(ctx, header.Get(headerManagerUsername), header.Get(headerManagerPIN))
+	}
+	if err != nil {
+		log.Printf("[flow-0] Approval rejected user=%s action=%s method=%s err=%v", user.Username, action, method, err)
+		return ctx, false, err
+	}
+	if approver == nil {
+		return ctx, false, nil
+	}
+
+	log.Printf("[flow-0] Approval attached user=%s action=%s approver=%s method=%s", user.Username, action, approver.Username, method)
+	return store.WithApproval(ctx, models.Approval{
+		Action:       action,
+		RequestedBy:  user.Username,
+		ApprovedBy:   approver.ID,
+		ApproverName: approver.Username,
+		Method:       method,
+	}), true, nil
+}
+
+// resolveApproval memeriksa persetujuan yang ditunda Approve. ok bernilai
+// false jika request tidak membawa persetujuan.
+func resolveApproval(ctx context.Context) (context.Context, bool, error) {
+	resolve, ok := ctx.Value(approvalResolverKey{}).(approvalResolver)
+	if !ok {
+		return ctx, false, nil
+	}
+	ctx, err := resolve(ctx)
+	return ctx, true, err
+}
+
+// approvalError memilih pesan dan status HTTP untuk persetujuan yang gagal diperiksa.
+func approvalError(err error) (string, int) {
+	switch storeErrorStatus(err) {
+	case http.StatusUnauthorized:
+		return "Invalid manager approval", http.StatusForbidden
+	case http.StatusTooManyRequests:
+		return "Too many failed manager PIN attempts, try again later", http.StatusTooManyRequests
+	default:
+		return "Failed to verify approval", http.StatusInternalServerError
+	}
+}
+
+// CreateToken menangani POST /api/approval/token. Manajer membuat token sekali
+// pakai untuk satu aksi yang bisa diberikan ke kasir.
+func (h *ApprovalHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateApprovalToken start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.ApprovalTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] CreateApprovalToken decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, _ := CurrentUser(r.Context())
+	log.Printf("[flow-2] CreateApprovalToken manager_id=%d action=%q", user.ID, req.Action)
+
+	token, err := h.store.CreateApprovalToken(r.Context(), user.ID, req.Action, h.ttl)
+	if err != nil {
+		log.Printf("[flow-3] CreateApprovalToken failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateApprovalToken success action=%s expires_at=%s", token.Action, token.ExpiresAt.Format(time.RFC3339))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// ListApprovals menangani GET /api/approval dengan filter opsional ?action= dan ?entity_id=.
+func (h *ApprovalHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListApprovals start method=%s path=%s", r.Method, r.URL.Path)
+
+	filter := store.ApprovalFilter{Action: strings.TrimSpace(r.URL.Query().Get("action"))}
+	if raw := r.URL.Query().Get("entity_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Printf("[flow-2] ListApprovals invalid entity_id raw=%q", raw)
+			http.Error(w, "Invalid entity_id", http.StatusBadRequest)
+			return
+		}
+		filter.EntityID = id
+	}
+	log.Printf("[flow-2] ListApprovals action=%q entity_id=%d", filter.Action, filter.EntityID)
+
+	approvals, err := h.store.GetAllApprovals(r.Context(), filter)
+	if err != nil {
+		log.Printf("[flow-3] ListApprovals failed err=%v", err)
+		http.Error(w, "Failed to get approvals", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-3] ListApprovals success count=%d", len(approvals))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approvals)
+}
@@ -0,0 +1,143 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kasir-api/handlers"
+	"kasir-api/models"
+)
+
+// approvalFixture menyiapkan checkout yang dilindungi Protect dan Approve
+// seperti di main.go, dengan satu kasir dan satu manajer.
+type approvalFixture struct {
+	*checkoutFixture
+	checkout http.HandlerFunc
+	session  string
+	manager  models.User
+}
+
+func newApprovalFixture(t *testing.T) *approvalFixture {
+	t.Helper()
+	f := &approvalFixture{checkoutFixture: newCheckoutFixture()}
+	ctx := context.Background()
+
+	if _, err := f.store.CreateUser(ctx, models.CreateUserRequest{Username: "ani", Password: "rahasia123", Role: models.RoleCashier}); err != nil {
+		t.Fatalf("create cashier: %v", err)
+	}
+	manager, err := f.store.CreateUser(ctx, models.CreateUserRequest{Username: "budi", Password: "rahasia123", Role: models.RoleManager})
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	f.manager = manager
+	session, err := f.store.Login(ctx, models.LoginRequest{Username: "ani", Password: "rahasia123"}, time.Hour)
+	if err != nil {
+		t.Fatalf("login cashier: %v", err)
+	}
+	f.session = session.Token
+
+	auth := handlers.NewAuthHandler(f.store, f.store, time.Hour)
+	approval := handlers.NewApprovalHandler(f.store, time.Minute)
+	f.checkout = auth.Protect(nil, handlers.RolesSales,
+		approval.Approve(models.ApprovalActionCheckout, false, f.transaction.HandleCheckout))
+	return f
+}
+
+// token membuat token persetujuan checkout atas nama manajer.
+func (f *approvalFixture) token(t *testing.T) string {
+	t.Helper()
+	token, err := f.store.CreateApprovalToken(context.Background(), f.manager.ID, models.ApprovalActionCheckout, time.Minute)
+	if err != nil {
+		t.Fatalf("create approval token: %v", err)
+	}
+	return token.Token
+}
+
+// post mengirim checkout sebagai kasir dengan header tambahan.
+func (f *approvalFixture) post(body string, header map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/checkout", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+f.session)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	f.checkout(w, r)
+	return w
+}
+
+func overrideBody(productID, price int) string {
+	return fmt.Sprintf(`{"register":"R1","items":[{"product_id":%d,"quantity":1,"price_override":%d}],"payments":[{"method":"cash","amount":50000}]}`,
+		productID, price)
+}
+
+func TestCheckoutKeepsApprovalTokenWhenNotNeeded(t *testing.T) {
+	f := newApprovalFixture(t)
+	p := f.setup(t, 5)
+	token := f.token(t)
+
+	// Checkout tanpa ubah harga tidak memakai token.
+	w := f.post(checkoutBody(p.ID, 1, 50000), map[string]string{"X-Approval-Token": token})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout without override: status %d body %s", w.Code, w.Body.String())
+	}
+
+	w = f.post(overrideBody(p.ID, 10000), map[string]string{"X-Approval-Token": token})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout with override: status %d body %s", w.Code, w.Body.String())
+	}
+
+	// Token sekali pakai sudah terpakai oleh ubah harga di atas.
+	w = f.post(overrideBody(p.ID, 9000), map[string]string{"X-Approval-Token": token})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("reused token: status %d body %s, want %d", w.Code, w.Body.String(), http.StatusForbidden)
+	}
+}
+
+func TestCheckoutReplayWithApprovalToken(t *testing.T) {
+	f := newApprovalFixture(t)
+	p := f.setup(t, 5)
+	header := map[string]string{"X-Approval-Token": f.token(t), "Idempotency-Key": "trx-1"}
+
+	w := f.post(overrideBody(p.ID, 10000), header)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout: status %d body %s", w.Code, w.Body.String())
+	}
+	var first models.Transaction
+	decode(t, w, &first)
+
+	// Request ulang dengan token yang sama mendapat transaksi yang sama, bukan 403.
+	w = f.post(overrideBody(p.ID, 10000), header)
+	if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("replay: status %d replayed %q body %s", w.Code, w.Header().Get("Idempotent-Replayed"), w.Body.String())
+	}
+	var replayed models.Transaction
+	decode(t, w, &replayed)
+	if replayed.ID != first.ID {
+		t.Errorf("replayed transaction id = %d, want %d", replayed.ID, first.ID)
+	}
+	if got := f.stok(t, p.ID); got != 4 {
+		t.Errorf("stok after replay = %d, want 4", got)
+	}
+}
+
+func TestCheckoutOverrideWithoutApproval(t *testing.T) {
+	f := newApprovalFixture(t)
+	p := f.setup(t, 5)
+
+	w := f.post(overrideBody(p.ID, 10000), nil)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("override without approval: status %d body %s, want %d", w.Code, w.Body.String(), http.StatusForbidden)
+	}
+	w = f.post(overrideBody(p.ID, 10000), map[string]string{"X-Approval-Token": "bukan-token"})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("override with invalid token: status %d body %s, want %d", w.Code, w.Body.String(), http.StatusForbidden)
+	}
+	if got := f.stok(t, p.ID); got != 5 {
+		t.Errorf("stok after rejected checkouts = %d, want 5", got)
+	}
+}
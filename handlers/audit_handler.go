@@ -0,0 +1,55 @@
+// Package handlers menyimpan HTTP handler untuk audit log.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"kasir-api/store"
+)
+
+// AuditHandler menangani HTTP request untuk audit log perubahan master data.
+type AuditHandler struct {
+	store store.AuditStore
+}
+
+// NewAuditHandler membuat AuditHandler dengan store yang diberikan.
+func NewAuditHandler(s store.AuditStore) *AuditHandler {
+	return &AuditHandler{store: s}
+}
+
+// ListAudit menangani GET /api/audit?entity=&actor=&start=YYYY-MM-DD&end=YYYY-MM-DD.
+// Rentang tanggal default hari ini.
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListAudit start method=%s path=%s", r.Method, r.URL.Path)
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		log.Printf("[flow-2] ListAudit invalid date range err=%v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter := store.AuditFilter{
+		Entity: strings.TrimSpace(r.URL.Query().Get("entity")),
+		Actor:  strings.TrimSpace(r.URL.Query().Get("actor")),
+		Start:  start,
+		End:    end,
+	}
+	log.Printf("[flow-2] ListAudit entity=%q actor=%q start=%s end=%s",
+		filter.Entity, filter.Actor, start.Format(time.DateOnly), end.Format(time.DateOnly))
+
+	entries, err := h.store.GetAuditLog(r.Context(), filter)
+	if err != nil {
+		log.Printf("[flow-3] ListAudit failed err=%v", err)
+		http.Error(w, "Failed to get audit log", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-3] ListAudit success count=%d", len(entries))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,262 @@
+// Package handlers menyimpan HTTP handler untuk login dan middleware hak akses.
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// Kelompok role yang dipakai main.go saat membungkus route dengan Protect.
+var (
+	RolesAll      = []string{models.RoleOwner, models.RoleManager, models.RoleCashier, models.RoleStockClerk}
+	RolesSales    = []string{models.RoleOwner, models.RoleManager, models.RoleCashier}
+	RolesStock    = []string{models.RoleOwner, models.RoleManager, models.RoleStockClerk}
+	RolesManagers = []string{models.RoleOwner, models.RoleManager}
+	RolesOwner    = []string{models.RoleOwner}
+)
+
+// Header untuk kunci API terminal POS dan integrasi, dan untuk ID request yang
+// dicatat di audit log.
+const (
+	headerAPIKey    = "X-API-Key"
+	headerRequestID = "X-Request-ID"
+)
+
+// maxRequestIDLength adalah panjang maksimal X-Request-ID dari client, sesuai
+// kolom audit_log.request_id.
+const maxRequestIDLength = 64
+
+// userContextKey adalah key context untuk pengguna yang sedang login.
+type userContextKey struct{}
+
+// apiKeyContextKey adalah key context untuk kunci API yang dipakai request.
+type apiKeyContextKey struct{}
+
+// CurrentUser mengembalikan pengguna yang sudah diautentikasi oleh Protect.
+// Untuk request dengan kunci API, pengguna ini tidak punya role dan username-nya
+// berisi nama kunci.
+func CurrentUser(ctx context.Context) (*models.User, bool) {
+	u, ok := ctx.Value(userContextKey{}).(*models.User)
+	return u, ok
+}
+
+// CurrentAPIKey mengembalikan kunci API yang sudah diautentikasi oleh Protect.
+func CurrentAPIKey(ctx context.Context) (*models.APIKey, bool) {
+	k, ok := ctx.Value(apiKeyContextKey{}).(*models.APIKey)
+	return k, ok
+}
+
+// AuthHandler menangani login/logout dan memeriksa token sesi atau kunci API untuk route lain.
+type AuthHandler struct {
+	store store.UserStore
+	keys  store.APIKeyStore
+	ttl   time.Duration
+}
+
+// NewAuthHandler membuat AuthHandler dengan store pengguna, store kunci API, dan
+// lama berlaku sesi yang diberikan.
+func NewAuthHandler(s store.UserStore, keys store.APIKeyStore, ttl time.Duration) *AuthHandler {
+	return &AuthHandler{store: s, keys: keys, ttl: ttl}
+}
+
+// Protect membungkus handler agar hanya bisa diakses pengguna yang login dengan
+// token Bearer atau dengan kunci API di header X-API-Key. Request GET/HEAD dicek
+// terhadap read, method lain terhadap write; nil berarti route tidak menerima
+// method jenis itu (405). Kunci API dicek terhadap role kunci seperti role
+// pengguna, lalu terhadap scope <resource>:<read|write> dengan resource dari
+// segmen pertama path setelah /api/. Token atau kunci tidak valid menghasilkan
+// 401, role atau scope yang tidak diizinkan 403.
+func (h *AuthHandler) Protect(read, write []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerAPIKey) != "" {
+			h.protectAPIKey(w, r, read, write, next)
+			return
+		}
+
+		token := bearerToken(r)
+		if token == "" {
+			log.Printf("[flow-0] Auth missing token method=%s path=%s", r.Method, r.URL.Path)
+			unauthorized(w)
+			return
+		}
+
+		user, err := h.store.Authenticate(r.Context(), token)
+		if err != nil {
+			log.Printf("[flow-0] Auth rejected method=%s path=%s err=%v", r.Method, r.URL.Path, err)
+			if storeErrorStatus(err) == http.StatusUnauthorized {
+				unauthorized(w)
+				return
+			}
+			http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+			return
+		}
+
+		allowed := write
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			allowed = read
+		}
+		if allowed == nil {
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !slices.Contains(allowed, user.Role) {
+			log.Printf("[flow-0] Auth forbidden user=%s role=%s method=%s path=%s", user.Username, user.Role, r.Method, r.URL.Path)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next(w, r.WithContext(withUser(w, r, user)))
+	}
+}
+
+// protectAPIKey adalah bagian Protect untuk request yang membawa kunci API.
+func (h *AuthHandler) protectAPIKey(w http.ResponseWriter, r *http.Request, read, write []string, next http.HandlerFunc) {
+	key, err := h.keys.AuthenticateAPIKey(r.Context(), r.Header.Get(headerAPIKey))
+	if err != nil {
+		log.Printf("[flow-0] API key rejected method=%s path=%s err=%v", r.Method, r.URL.Path, err)
+		if storeErrorStatus(err) == http.StatusUnauthorized {
+			unauthorized(w)
+			return
+		}
+		http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+
+	allowed, access := write, "write"
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		allowed, access = read, "read"
+	}
+	if allowed == nil {
+		log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !slices.Contains(allowed, key.Role) {
+		log.Printf("[flow-0] API key forbidden key_id=%d role=%s method=%s path=%s", key.ID, key.Role, r.Method, r.URL.Path)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	resource, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	scope := resource + ":" + access
+	if !slices.Contains(key.Scopes, scope) {
+		log.Printf("[flow-0] API key forbidden key_id=%d scope=%s method=%s path=%s", key.ID, scope, r.Method, r.URL.Path)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	user := &models.User{Username: "api-key:" + key.Name, Active: true}
+	ctx := withUser(w, r, user)
+	next(w, r.WithContext(context.WithValue(ctx, apiKeyContextKey{}, key)))
+}
+
+// withUser menyimpan pengguna yang sudah diautentikasi di context, sekaligus
+// pelaku dan ID request untuk audit log. ID request diambil dari header
+// X-Request-ID atau dibuat baru, lalu dikirim balik di header response.
+func withUser(w http.ResponseWriter, r *http.Request, user *models.User) context.Context {
+	requestID := strings.TrimSpace(r.Header.Get(headerRequestID))
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		requestID = rand.Text()
+	}
+	w.Header().Set(headerRequestID, requestID)
+
+	ctx := context.WithValue(r.Context(), userContextKey{}, user)
+	return store.WithActor(ctx, user.Username, requestID)
+}
+
+// bindRegister menerapkan register terikat milik kunci API ke register request.
+// Register kosong diisi dari kunci; register lain ditolak dengan 403 dan
+// hasilnya false.
+func bindRegister(w http.ResponseWriter, r *http.Request, register *string) bool {
+	key, ok := CurrentAPIKey(r.Context())
+	if !ok || key.Outlet == "" {
+		return true
+	}
+	if strings.TrimSpace(*register) == "" {
+		*register = key.Outlet
+		return true
+	}
+	if strings.TrimSpace(*register) != key.Outlet {
+		log.Printf("[flow-0] API key register mismatch key_id=%d outlet=%s register=%q", key.ID, key.Outlet, *register)
+		http.Error(w, "API key is bound to register "+key.Outlet, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Login menangani POST /api/auth/login.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] Login start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] Login decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] Login username=%q", req.Username)
+
+	session, err := h.store.Login(r.Context(), req, h.ttl)
+	if err != nil {
+		log.Printf("[flow-3] Login failed username=%q err=%v", req.Username, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] Login success user_id=%d role=%s", session.User.ID, session.User.Role)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// Logout menangani POST /api/auth/logout dan mencabut token yang dipakai.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] Logout start method=%s path=%s", r.Method, r.URL.Path)
+
+	if err := h.store.Logout(r.Context(), bearerToken(r)); err != nil {
+		log.Printf("[flow-2] Logout failed err=%v", err)
+		http.Error(w, "Failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] Logout success")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Logout berhasil"})
+}
+
+// Me menangani GET /api/auth/me dan mengembalikan pengguna yang sedang login.
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] Me start method=%s path=%s", r.Method, r.URL.Path)
+
+	user, _ := CurrentUser(r.Context())
+	log.Printf("[flow-2] Me success user_id=%d", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// bearerToken membaca token dari header Authorization: Bearer <token>.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// unauthorized menulis response 401 beserta skema autentikasi yang diharapkan.
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="kasir-api"`)
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
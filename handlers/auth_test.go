@@ -0,0 +1,147 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kasir-api/handlers"
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// apiKey membuat kunci API di MemoryStore dan mengembalikan kunci utuhnya.
+func apiKey(t *testing.T, s *store.MemoryStore, req models.CreateAPIKeyRequest) string {
+	t.Helper()
+	req.Name, req.CreatedBy = "tablet-kasir-1", "owner"
+	key, err := s.CreateAPIKey(context.Background(), req)
+	if err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	return key.Key
+}
+
+// login membuat pengguna dengan role di MemoryStore lalu mengembalikan token sesinya.
+func login(t *testing.T, s *store.MemoryStore, username, role string) string {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := s.CreateUser(ctx, models.CreateUserRequest{Username: username, Password: "rahasia123", Role: role}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	session, err := s.Login(ctx, models.LoginRequest{Username: username, Password: "rahasia123"}, time.Hour)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return session.Token
+}
+
+func TestProtectRoles(t *testing.T) {
+	s := store.NewMemoryStore()
+	auth := handlers.NewAuthHandler(s, s, time.Hour)
+	// Laporan hanya bisa dibaca manajer dan tidak menerima method tulis.
+	report := auth.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	cashier := login(t, s, "ani", models.RoleCashier)
+	manager := login(t, s, "budi", models.RoleManager)
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   int
+	}{
+		{"manager", http.MethodGet, manager, http.StatusNoContent},
+		{"cashier", http.MethodGet, cashier, http.StatusForbidden},
+		{"write not allowed", http.MethodPost, manager, http.StatusMethodNotAllowed},
+		{"missing token", http.MethodGet, "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "bukan-token", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/api/report/sales", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			report(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyRoleChecked(t *testing.T) {
+	s := store.NewMemoryStore()
+	auth := handlers.NewAuthHandler(s, s, time.Hour)
+	// Laporan hanya untuk manajer, seperti di main.go.
+	report := auth.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name string
+		req  models.CreateAPIKeyRequest
+		want int
+	}{
+		{"default cashier role", models.CreateAPIKeyRequest{Scopes: []string{"report:read"}}, http.StatusForbidden},
+		{"cashier role", models.CreateAPIKeyRequest{Scopes: []string{"report:read"}, Role: models.RoleCashier}, http.StatusForbidden},
+		{"manager role", models.CreateAPIKeyRequest{Scopes: []string{"report:read"}, Role: models.RoleManager}, http.StatusNoContent},
+		{"manager role without scope", models.CreateAPIKeyRequest{Scopes: []string{"produk:read"}, Role: models.RoleManager}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/report/sales", nil)
+			r.Header.Set("X-API-Key", apiKey(t, s, tt.req))
+			report(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyRegisterBinding(t *testing.T) {
+	f := newCheckoutFixture()
+	p := f.setup(t, 10)
+	auth := handlers.NewAuthHandler(f.store, f.store, time.Hour)
+	checkout := auth.Protect(nil, handlers.RolesSales, f.transaction.Checkout)
+	bound := apiKey(t, f.store, models.CreateAPIKeyRequest{Scopes: []string{"checkout:write"}, Outlet: "R1"})
+	readOnly := apiKey(t, f.store, models.CreateAPIKeyRequest{Scopes: []string{"produk:read"}, Outlet: "R1"})
+
+	tests := []struct {
+		name     string
+		key      string
+		register string
+		want     int
+	}{
+		{"bound register", bound, "R1", http.StatusCreated},
+		// Register kosong diisi dari kunci.
+		{"empty register", bound, "", http.StatusCreated},
+		{"other register", bound, "R2", http.StatusForbidden},
+		{"scope not granted", readOnly, "R1", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"register":%q,"items":[{"product_id":%d,"quantity":1}],"payments":[{"method":"cash","amount":20000}]}`,
+				tt.register, p.ID)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/checkout", strings.NewReader(body))
+			r.Header.Set("X-API-Key", tt.key)
+			checkout(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+	// Hanya dua checkout yang diterima.
+	if got := f.stok(t, p.ID); got != 8 {
+		t.Errorf("stok = %d, want 8", got)
+	}
+}
@@ -0,0 +1,156 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kasir-api/handlers"
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// checkoutFixture menyambungkan handler produk, shift, dan checkout ke satu MemoryStore.
+type checkoutFixture struct {
+	store       *store.MemoryStore
+	produk      *handlers.ProdukHandler
+	shift       *handlers.ShiftHandler
+	transaction *handlers.TransactionHandler
+}
+
+func newCheckoutFixture() *checkoutFixture {
+	s := store.NewMemoryStore()
+	return &checkoutFixture{
+		store:       s,
+		produk:      handlers.NewProdukHandler(s),
+		shift:       handlers.NewShiftHandler(s),
+		transaction: handlers.NewTransactionHandler(s, 10, 1000),
+	}
+}
+
+// do menjalankan handler dengan body JSON dan mengembalikan response-nya.
+func do(h http.HandlerFunc, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+// decode membaca body JSON response ke v.
+func decode(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+}
+
+// setup membuat satu produk dengan stok awal dan membuka shift di register R1.
+func (f *checkoutFixture) setup(t *testing.T, stok int) models.Produk {
+	t.Helper()
+	w := do(f.produk.CreateProduk, http.MethodPost, "/api/produk",
+		fmt.Sprintf(`{"nama":"Kopi Susu","harga":15000,"harga_beli":9000,"stok":%d}`, stok))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create produk: status %d body %s", w.Code, w.Body.String())
+	}
+	var p models.Produk
+	decode(t, w, &p)
+
+	w = do(f.shift.OpenShift, http.MethodPost, "/api/shift", `{"register":"R1","cashier":"ani","opening_float":100000}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("open shift: status %d body %s", w.Code, w.Body.String())
+	}
+	return p
+}
+
+// stok membaca stok produk lewat GET /api/produk/{id}.
+func (f *checkoutFixture) stok(t *testing.T, id int) int {
+	t.Helper()
+	w := do(f.produk.GetProdukByID, http.MethodGet, fmt.Sprintf("/api/produk/%d", id), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get produk: status %d body %s", w.Code, w.Body.String())
+	}
+	var p models.Produk
+	decode(t, w, &p)
+	return p.Stok
+}
+
+func checkoutBody(productID, quantity, cash int) string {
+	return fmt.Sprintf(`{"register":"R1","items":[{"product_id":%d,"quantity":%d}],"payments":[{"method":"cash","amount":%d}]}`,
+		productID, quantity, cash)
+}
+
+func TestCheckoutMemoryStore(t *testing.T) {
+	f := newCheckoutFixture()
+	p := f.setup(t, 5)
+
+	w := do(f.transaction.Checkout, http.MethodPost, "/api/checkout", checkoutBody(p.ID, 2, 50000))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout: status %d body %s", w.Code, w.Body.String())
+	}
+	var trx models.Transaction
+	decode(t, w, &trx)
+
+	if trx.Status != models.TransactionStatusCompleted {
+		t.Errorf("status = %q, want %q", trx.Status, models.TransactionStatusCompleted)
+	}
+	if trx.TotalAmount != 30000 || trx.PaidAmount != 50000 || trx.ChangeAmount != 20000 {
+		t.Errorf("total/paid/change = %d/%d/%d, want 30000/50000/20000", trx.TotalAmount, trx.PaidAmount, trx.ChangeAmount)
+	}
+	if len(trx.Details) != 1 || trx.Details[0].ProductID != p.ID || trx.Details[0].Quantity != 2 {
+		t.Errorf("details = %+v, want 1 line of product %d quantity 2", trx.Details, p.ID)
+	}
+	if got := f.stok(t, p.ID); got != 3 {
+		t.Errorf("stok after checkout = %d, want 3", got)
+	}
+}
+
+func TestCheckoutMemoryStoreInsufficientStock(t *testing.T) {
+	f := newCheckoutFixture()
+	p := f.setup(t, 2)
+
+	w := do(f.transaction.Checkout, http.MethodPost, "/api/checkout", checkoutBody(p.ID, 3, 50000))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("checkout: status %d body %s, want %d", w.Code, w.Body.String(), http.StatusConflict)
+	}
+	var resp models.CheckoutErrorResponse
+	decode(t, w, &resp)
+	if len(resp.Lines) != 1 || resp.Lines[0].Line != 1 || resp.Lines[0].ProductID != p.ID || resp.Lines[0].Field != "quantity" {
+		t.Errorf("lines = %+v, want one quantity error on line 1", resp.Lines)
+	}
+
+	if got := f.stok(t, p.ID); got != 2 {
+		t.Errorf("stok after rejected checkout = %d, want 2", got)
+	}
+}
+
+func TestCheckoutIdempotencyKeyPerUser(t *testing.T) {
+	f := newApprovalFixture(t)
+	p := f.setup(t, 5)
+	if _, err := f.store.CreateUser(context.Background(), models.CreateUserRequest{Username: "citra", Password: "rahasia123", Role: models.RoleCashier}); err != nil {
+		t.Fatalf("create cashier: %v", err)
+	}
+	other, err := f.store.Login(context.Background(), models.LoginRequest{Username: "citra", Password: "rahasia123"}, time.Hour)
+	if err != nil {
+		t.Fatalf("login cashier: %v", err)
+	}
+
+	header := map[string]string{"Idempotency-Key": "trx-1"}
+	w := f.post(checkoutBody(p.ID, 1, 50000), header)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("first cashier checkout: status %d body %s", w.Code, w.Body.String())
+	}
+
+	// Key yang sama dari kasir lain adalah checkout baru, bukan replay transaksi kasir pertama.
+	f.session = other.Token
+	w = f.post(checkoutBody(p.ID, 1, 50000), header)
+	if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("second cashier checkout: status %d replayed %q body %s", w.Code, w.Header().Get("Idempotent-Replayed"), w.Body.String())
+	}
+	if got := f.stok(t, p.ID); got != 3 {
+		t.Errorf("stok after two checkouts = %d, want 3", got)
+	}
+}
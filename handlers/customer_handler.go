@@ -0,0 +1,164 @@
+// Package handlers menyimpan HTTP handler untuk pelanggan.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// CustomerHandler menangani HTTP request untuk data pelanggan.
+type CustomerHandler struct {
+	store store.CustomerStore
+}
+
+// NewCustomerHandler membuat CustomerHandler dengan store yang diberikan.
+func NewCustomerHandler(s store.CustomerStore) *CustomerHandler {
+	return &CustomerHandler{store: s}
+}
+
+// ListCustomers menangani GET /api/customer dengan pencarian nomor telepon opsional ?phone=.
+func (h *CustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListCustomers start method=%s path=%s", r.Method, r.URL.Path)
+
+	phone := r.URL.Query().Get("phone")
+	log.Printf("[flow-2] ListCustomers phone filter=%q", phone)
+
+	customers, err := h.store.GetAllCustomers(r.Context(), phone)
+	if err != nil {
+		log.Printf("[flow-3] ListCustomers failed err=%v", err)
+		http.Error(w, "Failed to get customers", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-4] ListCustomers success count=%d", len(customers))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+// CreateCustomer menangani POST /api/customer.
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateCustomer start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		log.Printf("[flow-2] CreateCustomer decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreateCustomer nama=%q telepon=%q", customer.Nama, customer.Telepon)
+
+	created, err := h.store.AddCustomer(r.Context(), customer)
+	if err != nil {
+		log.Printf("[flow-3] CreateCustomer failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateCustomer success id=%d", created.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetCustomer menangani GET /api/customer/{id}.
+func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetCustomer start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/customer/", "")
+	if !ok {
+		return
+	}
+
+	customer, err := h.store.GetCustomerByID(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetCustomer failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetCustomer success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// UpdateCustomer menangani PUT /api/customer/{id}.
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdateCustomer start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/customer/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var customer models.Customer
+	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+		log.Printf("[flow-3] UpdateCustomer decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] UpdateCustomer id=%d nama=%q telepon=%q", id, customer.Nama, customer.Telepon)
+
+	updated, err := h.store.UpdateCustomer(r.Context(), id, customer)
+	if err != nil {
+		log.Printf("[flow-4] UpdateCustomer failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] UpdateCustomer success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteCustomer menangani DELETE /api/customer/{id}.
+func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] DeleteCustomer start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/customer/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteCustomer(r.Context(), id); err != nil {
+		log.Printf("[flow-3] DeleteCustomer failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] DeleteCustomer success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pelanggan berhasil dihapus"})
+}
+
+// CustomerTransactions menangani GET /api/customer/{id}/transactions.
+func (h *CustomerHandler) CustomerTransactions(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CustomerTransactions start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/customer/", "/transactions")
+	if !ok {
+		return
+	}
+
+	transactions, err := h.store.GetCustomerTransactions(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] CustomerTransactions failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CustomerTransactions success id=%d count=%d", id, len(transactions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
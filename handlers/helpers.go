@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/store"
+)
+
+// storeErrorStatus memilih status HTTP untuk error umum dari store.
+func storeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, store.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, store.ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	case errors.Is(err, store.ErrApprovalRequired):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parsePathID membaca ID dari path {prefix}{id}{suffix}. Jika gagal, response
+// 400 sudah ditulis dan ok bernilai false.
+func parsePathID(w http.ResponseWriter, r *http.Request, prefix, suffix string) (int, bool) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), suffix)
+	log.Printf("[flow-2] parse id path=%s raw=%q", r.URL.Path, idStr)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Printf("[flow-2] parse id failed err=%v", err)
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
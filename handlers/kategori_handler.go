@@ -11,14 +11,24 @@ import (
 	"kasir-api/store"
 )
 
+// KategoriHandler menangani HTTP request untuk kategori.
+type KategoriHandler struct {
+	store store.KategoriStore
+}
+
+// NewKategoriHandler membuat KategoriHandler dengan store yang diberikan.
+func NewKategoriHandler(s store.KategoriStore) *KategoriHandler {
+	return &KategoriHandler{store: s}
+}
+
 // GetKategoriHandler mengambil semua kategori
-func GetKategoriHandler(w http.ResponseWriter, r *http.Request) {
+func (h *KategoriHandler) GetKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("[flow-1] Masuk ke GetKategoriHandler")
 
 	w.Header().Set("Content-Type", "application/json")
 
 	log.Println("[flow-2] Mengambil data dari store")
-	kategori := store.GetAllKategori()
+	kategori := h.store.GetAllKategori(r.Context())
 
 	log.Println("[flow-3] Mengencode data ke JSON dan mengirim response")
 	json.NewEncoder(w).Encode(kategori)
@@ -26,7 +36,7 @@ func GetKategoriHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetKategoriByIDHandler mengambil kategori berdasarkan ID
-func GetKategoriByIDHandler(w http.ResponseWriter, r *http.Request) {
+func (h *KategoriHandler) GetKategoriByIDHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("[flow-1] Masuk ke GetKategoriByIDHandler")
 
 	w.Header().Set("Content-Type", "application/json")
@@ -46,7 +56,7 @@ func GetKategoriByIDHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("[flow-4] Mencari kategori dengan ID: %d", id)
-	kategori, found := store.GetKategoriByID(id)
+	kategori, found := h.store.GetKategoriByID(r.Context(), id)
 
 	if !found {
 		log.Printf("[flow-5] Kategori dengan ID %d tidak ditemukan", id)
@@ -63,7 +73,7 @@ func GetKategoriByIDHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // CreateKategoriHandler menambahkan kategori baru
-func CreateKategoriHandler(w http.ResponseWriter, r *http.Request) {
+func (h *KategoriHandler) CreateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("[flow-1] Masuk ke CreateKategoriHandler")
 
 	// Pastikan method adalah POST
@@ -102,7 +112,7 @@ func CreateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("[flow-6] Menambahkan kategori baru: %s", kategori.Nama)
-	createdKategori, err := store.AddKategori(kategori)
+	createdKategori, err := h.store.AddKategori(r.Context(), kategori)
 	if err != nil {
 		log.Printf("[flow-7] Error: Gagal menambahkan kategori - %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -118,7 +128,7 @@ func CreateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateKategoriHandler mengupdate kategori yang sudah ada
-func UpdateKategoriHandler(w http.ResponseWriter, r *http.Request) {
+func (h *KategoriHandler) UpdateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("[flow-1] Masuk ke UpdateKategoriHandler")
 
 	// Pastikan method adalah PUT
@@ -171,7 +181,7 @@ func UpdateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("[flow-8] Mengupdate kategori dengan ID: %d", id)
-	success := store.UpdateKategori(id, kategori)
+	success := h.store.UpdateKategori(r.Context(), id, kategori)
 
 	if !success {
 		log.Printf("[flow-9] Kategori dengan ID %d tidak ditemukan", id)
@@ -189,7 +199,7 @@ func UpdateKategoriHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // DeleteKategoriHandler menghapus kategori
-func DeleteKategoriHandler(w http.ResponseWriter, r *http.Request) {
+func (h *KategoriHandler) DeleteKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	log.Println("[flow-1] Masuk ke DeleteKategoriHandler")
 
 	// Pastikan method adalah DELETE
@@ -220,7 +230,7 @@ func DeleteKategoriHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("[flow-5] Menghapus kategori dengan ID: %d", id)
-	success := store.DeleteKategori(id)
+	success := h.store.DeleteKategori(r.Context(), id)
 
 	if !success {
 		log.Printf("[flow-6] Kategori dengan ID %d tidak ditemukan", id)
@@ -0,0 +1,157 @@
+// Package handlers menyimpan HTTP handler untuk program poin pelanggan.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// LoyaltyHandler menangani HTTP request untuk pengaturan poin dan saldo poin pelanggan.
+type LoyaltyHandler struct {
+	store store.LoyaltyStore
+}
+
+// NewLoyaltyHandler membuat LoyaltyHandler dengan store yang diberikan.
+func NewLoyaltyHandler(s store.LoyaltyStore) *LoyaltyHandler {
+	return &LoyaltyHandler{store: s}
+}
+
+// GetSettings menangani GET /api/loyalty/settings.
+func (h *LoyaltyHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetLoyaltySettings start method=%s path=%s", r.Method, r.URL.Path)
+
+	settings, err := h.store.GetLoyaltySettings(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] GetLoyaltySettings failed err=%v", err)
+		http.Error(w, "Failed to get loyalty settings", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] GetLoyaltySettings success spend_per_point=%d point_value=%d", settings.SpendPerPoint, settings.PointValue)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings menangani PUT /api/loyalty/settings.
+func (h *LoyaltyHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdateLoyaltySettings start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var settings models.LoyaltySettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		log.Printf("[flow-2] UpdateLoyaltySettings decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] UpdateLoyaltySettings spend_per_point=%d point_value=%d expiry_days=%d",
+		settings.SpendPerPoint, settings.PointValue, settings.ExpiryDays)
+
+	saved, err := h.store.UpdateLoyaltySettings(r.Context(), settings)
+	if err != nil {
+		log.Printf("[flow-3] UpdateLoyaltySettings failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] UpdateLoyaltySettings success")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// ListMultipliers menangani GET /api/loyalty/multiplier.
+func (h *LoyaltyHandler) ListMultipliers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListLoyaltyMultipliers start method=%s path=%s", r.Method, r.URL.Path)
+
+	multipliers, err := h.store.GetAllLoyaltyMultipliers(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListLoyaltyMultipliers failed err=%v", err)
+		http.Error(w, "Failed to get loyalty multipliers", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListLoyaltyMultipliers success count=%d", len(multipliers))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(multipliers)
+}
+
+// SetMultiplier menangani PUT /api/loyalty/multiplier/{kategori_id}.
+func (h *LoyaltyHandler) SetMultiplier(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] SetLoyaltyMultiplier start method=%s path=%s", r.Method, r.URL.Path)
+
+	kategoriID, ok := parsePathID(w, r, "/api/loyalty/multiplier/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var multiplier models.LoyaltyMultiplier
+	if err := json.NewDecoder(r.Body).Decode(&multiplier); err != nil {
+		log.Printf("[flow-3] SetLoyaltyMultiplier decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	multiplier.KategoriID = kategoriID
+	log.Printf("[flow-3] SetLoyaltyMultiplier kategori_id=%d multiplier=%.2f", kategoriID, multiplier.Multiplier)
+
+	saved, err := h.store.SetLoyaltyMultiplier(r.Context(), multiplier)
+	if err != nil {
+		log.Printf("[flow-4] SetLoyaltyMultiplier failed kategori_id=%d err=%v", kategoriID, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] SetLoyaltyMultiplier success kategori_id=%d", kategoriID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// DeleteMultiplier menangani DELETE /api/loyalty/multiplier/{kategori_id}.
+func (h *LoyaltyHandler) DeleteMultiplier(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] DeleteLoyaltyMultiplier start method=%s path=%s", r.Method, r.URL.Path)
+
+	kategoriID, ok := parsePathID(w, r, "/api/loyalty/multiplier/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteLoyaltyMultiplier(r.Context(), kategoriID); err != nil {
+		log.Printf("[flow-3] DeleteLoyaltyMultiplier failed kategori_id=%d err=%v", kategoriID, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] DeleteLoyaltyMultiplier success kategori_id=%d", kategoriID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Pengali poin berhasil dihapus"})
+}
+
+// CustomerPoints menangani GET /api/customer/{id}/points.
+func (h *LoyaltyHandler) CustomerPoints(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CustomerPoints start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/customer/", "/points")
+	if !ok {
+		return
+	}
+
+	account, err := h.store.GetPointsAccount(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] CustomerPoints failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CustomerPoints success id=%d balance=%d entries=%d", id, account.Balance, len(account.Entries))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
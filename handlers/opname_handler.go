@@ -0,0 +1,180 @@
+// Package handlers menyimpan HTTP handler untuk stock opname.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// OpnameHandler menangani HTTP request untuk sesi stock opname.
+type OpnameHandler struct {
+	store store.OpnameStore
+}
+
+// NewOpnameHandler membuat OpnameHandler dengan store yang diberikan.
+func NewOpnameHandler(s store.OpnameStore) *OpnameHandler {
+	return &OpnameHandler{store: s}
+}
+
+// ListOpname menangani GET /api/opname.
+func (h *OpnameHandler) ListOpname(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListOpname start method=%s path=%s", r.Method, r.URL.Path)
+
+	sessions, err := h.store.ListOpname(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListOpname failed err=%v", err)
+		http.Error(w, "Failed to get stock opname sessions", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListOpname success count=%d", len(sessions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// OpenOpname menangani POST /api/opname.
+func (h *OpnameHandler) OpenOpname(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] OpenOpname start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.OpenOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] OpenOpname decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] OpenOpname operator=%q", req.Operator)
+
+	opname, err := h.store.OpenOpname(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] OpenOpname failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] OpenOpname success id=%d products=%d", opname.ID, len(opname.Lines))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(opname)
+}
+
+// GetOpname menangani GET /api/opname/{id} beserta laporan selisihnya.
+func (h *OpnameHandler) GetOpname(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetOpname start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/opname/", "")
+	if !ok {
+		return
+	}
+
+	opname, err := h.store.GetOpname(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetOpname failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetOpname success id=%d status=%s variance=%d", id, opname.Status, opname.TotalVariance)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
+
+// RecordCounts menangani POST /api/opname/{id}/counts.
+func (h *OpnameHandler) RecordCounts(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RecordCounts start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/opname/", "/counts")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.OpnameCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] RecordCounts decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] RecordCounts id=%d counter=%q items=%d", id, req.Counter, len(req.Items))
+
+	opname, err := h.store.RecordOpnameCounts(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] RecordCounts failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] RecordCounts success id=%d variance=%d", id, opname.TotalVariance)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
+
+// FinalizeOpname menangani POST /api/opname/{id}/finalize.
+func (h *OpnameHandler) FinalizeOpname(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] FinalizeOpname start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/opname/", "/finalize")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.CloseOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] FinalizeOpname decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] FinalizeOpname id=%d operator=%q", id, req.Operator)
+
+	opname, err := h.store.FinalizeOpname(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] FinalizeOpname failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] FinalizeOpname success id=%d variance=%d", id, opname.TotalVariance)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
+
+// CancelOpname menangani POST /api/opname/{id}/cancel.
+func (h *OpnameHandler) CancelOpname(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CancelOpname start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/opname/", "/cancel")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.CloseOpnameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] CancelOpname decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] CancelOpname id=%d operator=%q", id, req.Operator)
+
+	opname, err := h.store.CancelOpname(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] CancelOpname failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] CancelOpname success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
@@ -3,6 +3,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -12,8 +13,18 @@ import (
 	"kasir-api/store"
 )
 
+// ProdukHandler menangani HTTP request untuk produk.
+type ProdukHandler struct {
+	store store.ProdukStore
+}
+
+// NewProdukHandler membuat ProdukHandler dengan store yang diberikan.
+func NewProdukHandler(s store.ProdukStore) *ProdukHandler {
+	return &ProdukHandler{store: s}
+}
+
 // GetProdukByID menangani GET /api/produk/{id}.
-func GetProdukByID(w http.ResponseWriter, r *http.Request) {
+func (h *ProdukHandler) GetProdukByID(w http.ResponseWriter, r *http.Request) {
 	// Log langkah alur data untuk request ini.
 	log.Printf("[flow-1] GetProdukByID start method=%s path=%s", r.Method, r.URL.Path)
 
@@ -30,7 +41,7 @@ func GetProdukByID(w http.ResponseWriter, r *http.Request) {
 
 	// Ambil data dari store dan kirim jika ditemukan.
 	log.Printf("[flow-4] GetProdukByID call store.GetByID id=%d", id)
-	p, ok := store.GetByID(id)
+	p, ok := h.store.GetByID(r.Context(), id)
 	if ok {
 		log.Printf("[flow-5] GetProdukByID found id=%d", p.ID)
 		w.Header().Set("Content-Type", "application/json")
@@ -43,7 +54,7 @@ func GetProdukByID(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateProduk menangani PUT /api/produk/{id}.
-func UpdateProduk(w http.ResponseWriter, r *http.Request) {
+func (h *ProdukHandler) UpdateProduk(w http.ResponseWriter, r *http.Request) {
 	// Log langkah alur data untuk request ini.
 	log.Printf("[flow-1] UpdateProduk start method=%s path=%s", r.Method, r.URL.Path)
 
@@ -71,20 +82,20 @@ func UpdateProduk(w http.ResponseWriter, r *http.Request) {
 
 	// Update data di store dan kirim hasilnya.
 	log.Printf("[flow-6] UpdateProduk call store.Update id=%d", id)
-	updated, ok := store.Update(id, produkUpdate)
-	if ok {
-		log.Printf("[flow-7] UpdateProduk updated id=%d", updated.ID)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(updated)
+	updated, err := h.store.Update(r.Context(), id, produkUpdate)
+	if err != nil {
+		log.Printf("[flow-7] UpdateProduk failed id=%d err=%v", id, err)
+		writeProdukError(w, err)
 		return
 	}
 
-	log.Printf("[flow-7] UpdateProduk not found id=%d", id)
-	http.Error(w, "Produk belum ada", http.StatusNotFound)
+	log.Printf("[flow-7] UpdateProduk updated id=%d", updated.ID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
 }
 
 // DeleteProduk menangani DELETE /api/produk/{id}.
-func DeleteProduk(w http.ResponseWriter, r *http.Request) {
+func (h *ProdukHandler) DeleteProduk(w http.ResponseWriter, r *http.Request) {
 	// Log langkah alur data untuk request ini.
 	log.Printf("[flow-1] DeleteProduk start method=%s path=%s", r.Method, r.URL.Path)
 
@@ -101,22 +112,31 @@ func DeleteProduk(w http.ResponseWriter, r *http.Request) {
 
 	// Hapus data di store lalu kirim status.
 	log.Printf("[flow-4] DeleteProduk call store.Delete id=%d", id)
-	ok := store.Delete(id)
-	if ok {
-		log.Printf("[flow-5] DeleteProduk deleted id=%d", id)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
-			"message": "sukses delete",
-		})
+	if err := h.store.Delete(r.Context(), id); err != nil {
+		log.Printf("[flow-5] DeleteProduk failed id=%d err=%v", id, err)
+		writeProdukError(w, err)
 		return
 	}
 
-	log.Printf("[flow-5] DeleteProduk not found id=%d", id)
-	http.Error(w, "Produk belum ada", http.StatusNotFound)
+	log.Printf("[flow-5] DeleteProduk deleted id=%d", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "sukses delete",
+	})
+}
+
+// writeProdukError menulis error dari store produk; produk yang tidak ada tetap
+// mendapat pesan "Produk belum ada" seperti sebelumnya.
+func writeProdukError(w http.ResponseWriter, err error) {
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "Produk belum ada", http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), storeErrorStatus(err))
 }
 
 // ListProduk menangani GET /api/produk.
-func ListProduk(w http.ResponseWriter, r *http.Request) {
+func (h *ProdukHandler) ListProduk(w http.ResponseWriter, r *http.Request) {
 	// Log langkah alur data untuk request ini.
 	log.Printf("[flow-1] ListProduk start method=%s path=%s", r.Method, r.URL.Path)
 
@@ -126,14 +146,14 @@ func ListProduk(w http.ResponseWriter, r *http.Request) {
 
 	// Ambil data produk dengan filter nama lalu kirim sebagai JSON.
 	log.Printf("[flow-3] ListProduk call store.GetAll")
-	data := store.GetAll(name)
+	data := h.store.GetAll(r.Context(), name)
 	log.Printf("[flow-4] ListProduk total=%d", len(data))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
 
 // CreateProduk menangani POST /api/produk.
-func CreateProduk(w http.ResponseWriter, r *http.Request) {
+func (h *ProdukHandler) CreateProduk(w http.ResponseWriter, r *http.Request) {
 	// Log langkah alur data untuk request ini.
 	log.Printf("[flow-1] CreateProduk start method=%s path=%s", r.Method, r.URL.Path)
 
@@ -150,7 +170,7 @@ func CreateProduk(w http.ResponseWriter, r *http.Request) {
 
 	// Simpan ke store dan dapatkan ID dari database.
 	log.Printf("[flow-4] CreateProduk call store.Add")
-	created, err := store.Add(produkBaru)
+	created, err := h.store.Add(r.Context(), produkBaru)
 	if err != nil {
 		log.Printf("[flow-5] CreateProduk add failed err=%v", err)
 		http.Error(w, "Gagal menambahkan produk", http.StatusInternalServerError)
@@ -164,3 +184,40 @@ func CreateProduk(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(created)
 }
+
+// GetStockHistory menangani GET /api/produk/{id}/stock-history.
+func (h *ProdukHandler) GetStockHistory(w http.ResponseWriter, r *http.Request) {
+	// Log langkah alur data untuk request ini.
+	log.Printf("[flow-1] GetStockHistory start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Ambil ID dari path URL dan ubah ke integer.
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/stock-history")
+	log.Printf("[flow-2] GetStockHistory parse id raw=%q", idStr)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Printf("[flow-3] GetStockHistory parse id failed err=%v", err)
+		http.Error(w, "Invalid Produk ID", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] GetStockHistory parsed id=%d", id)
+
+	// Pastikan produk ada agar produk tanpa riwayat bisa dibedakan dari ID yang salah.
+	if _, ok := h.store.GetByID(r.Context(), id); !ok {
+		log.Printf("[flow-4] GetStockHistory not found id=%d", id)
+		http.Error(w, "Produk belum ada", http.StatusNotFound)
+		return
+	}
+
+	// Ambil riwayat pergerakan stok dari store.
+	log.Printf("[flow-4] GetStockHistory call store.GetStockHistory id=%d", id)
+	movements, err := h.store.GetStockHistory(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-5] GetStockHistory failed err=%v", err)
+		http.Error(w, "Gagal mengambil riwayat stok", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-5] GetStockHistory total=%d", len(movements))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
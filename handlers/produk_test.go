@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+func TestUpdateProdukKeepsStok(t *testing.T) {
+	f := newCheckoutFixture()
+	p := f.setup(t, 5)
+
+	w := do(f.transaction.Checkout, http.MethodPost, "/api/checkout", checkoutBody(p.ID, 2, 50000))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout: status %d body %s", w.Code, w.Body.String())
+	}
+
+	// Klien dengan data lama mengirim stok 5 sambil mengubah harga.
+	w = do(f.produk.UpdateProduk, http.MethodPut, fmt.Sprintf("/api/produk/%d", p.ID),
+		`{"nama":"Kopi Susu","harga":17000,"harga_beli":9000,"stok":5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update produk: status %d body %s", w.Code, w.Body.String())
+	}
+	var updated models.Produk
+	decode(t, w, &updated)
+	if updated.Harga != 17000 || updated.Stok != 3 {
+		t.Errorf("harga/stok = %d/%d, want 17000/3", updated.Harga, updated.Stok)
+	}
+	if got := f.stok(t, p.ID); got != 3 {
+		t.Errorf("stok after update = %d, want 3", got)
+	}
+}
+
+func TestUpdateProdukNotFound(t *testing.T) {
+	f := newCheckoutFixture()
+
+	w := do(f.produk.UpdateProduk, http.MethodPut, "/api/produk/99", `{"nama":"X","harga":1}`)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("update missing produk: status %d, want %d", w.Code, http.StatusNotFound)
+	}
+	w = do(f.produk.DeleteProduk, http.MethodDelete, "/api/produk/99", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("delete missing produk: status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestStockHistoryRecordsActor(t *testing.T) {
+	f := newCheckoutFixture()
+	p := f.setup(t, 5)
+
+	// Protect menyimpan user login di context lewat store.WithActor.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/checkout", strings.NewReader(checkoutBody(p.ID, 1, 50000)))
+	f.transaction.Checkout(w, r.WithContext(store.WithActor(r.Context(), "ani", "req-1")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("checkout: status %d body %s", w.Code, w.Body.String())
+	}
+
+	w = do(f.produk.GetStockHistory, http.MethodGet, fmt.Sprintf("/api/produk/%d/stock-history", p.ID), "")
+	var movements []models.StockMovement
+	decode(t, w, &movements)
+	if len(movements) != 2 {
+		t.Fatalf("movements = %+v, want initial stock and sale", movements)
+	}
+	if sale := movements[0]; sale.Reason != models.StockReasonSale || sale.Operator != "ani" {
+		t.Errorf("sale movement reason/operator = %q/%q, want %q/%q", sale.Reason, sale.Operator, models.StockReasonSale, "ani")
+	}
+	if initial := movements[1]; initial.Operator != "system" {
+		t.Errorf("initial movement operator = %q, want %q", initial.Operator, "system")
+	}
+}
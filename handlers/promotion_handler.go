@@ -0,0 +1,139 @@
+// Package handlers menyimpan HTTP handler untuk promosi.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// PromotionHandler menangani HTTP request untuk aturan promosi.
+type PromotionHandler struct {
+	store store.PromotionStore
+}
+
+// NewPromotionHandler membuat PromotionHandler dengan store yang diberikan.
+func NewPromotionHandler(s store.PromotionStore) *PromotionHandler {
+	return &PromotionHandler{store: s}
+}
+
+// ListPromotions menangani GET /api/promotion.
+func (h *PromotionHandler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListPromotions start method=%s path=%s", r.Method, r.URL.Path)
+
+	promotions, err := h.store.GetAllPromotions(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListPromotions failed err=%v", err)
+		http.Error(w, "Failed to get promotions", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListPromotions success count=%d", len(promotions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// CreatePromotion menangani POST /api/promotion.
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreatePromotion start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body. Promosi baru aktif kecuali dikirim "active": false.
+	promotion := models.Promotion{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		log.Printf("[flow-2] CreatePromotion decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreatePromotion name=%q type=%s", promotion.Name, promotion.Type)
+
+	created, err := h.store.AddPromotion(r.Context(), promotion)
+	if err != nil {
+		log.Printf("[flow-3] CreatePromotion failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreatePromotion success id=%d", created.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetPromotion menangani GET /api/promotion/{id}.
+func (h *PromotionHandler) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetPromotion start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/promotion/", "")
+	if !ok {
+		return
+	}
+
+	promotion, err := h.store.GetPromotionByID(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetPromotion failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetPromotion success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// UpdatePromotion menangani PUT /api/promotion/{id}.
+func (h *PromotionHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdatePromotion start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/promotion/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	promotion := models.Promotion{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		log.Printf("[flow-3] UpdatePromotion decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] UpdatePromotion id=%d name=%q active=%t", id, promotion.Name, promotion.Active)
+
+	updated, err := h.store.UpdatePromotion(r.Context(), id, promotion)
+	if err != nil {
+		log.Printf("[flow-4] UpdatePromotion failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] UpdatePromotion success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeletePromotion menangani DELETE /api/promotion/{id}.
+func (h *PromotionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] DeletePromotion start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/promotion/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeletePromotion(r.Context(), id); err != nil {
+		log.Printf("[flow-3] DeletePromotion failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] DeletePromotion success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Promosi berhasil dihapus"})
+}
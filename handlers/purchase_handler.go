@@ -0,0 +1,249 @@
+// Package handlers menyimpan HTTP handler untuk supplier dan purchase order.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// PurchaseHandler menangani HTTP request untuk supplier dan purchase order.
+type PurchaseHandler struct {
+	store store.PurchaseStore
+}
+
+// NewPurchaseHandler membuat PurchaseHandler dengan store yang diberikan.
+func NewPurchaseHandler(s store.PurchaseStore) *PurchaseHandler {
+	return &PurchaseHandler{store: s}
+}
+
+// ListSuppliers menangani GET /api/supplier.
+func (h *PurchaseHandler) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListSuppliers start method=%s path=%s", r.Method, r.URL.Path)
+
+	suppliers, err := h.store.GetAllSuppliers(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListSuppliers failed err=%v", err)
+		http.Error(w, "Failed to get suppliers", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListSuppliers success count=%d", len(suppliers))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suppliers)
+}
+
+// CreateSupplier menangani POST /api/supplier.
+func (h *PurchaseHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateSupplier start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		log.Printf("[flow-2] CreateSupplier decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreateSupplier nama=%q", supplier.Nama)
+
+	created, err := h.store.AddSupplier(r.Context(), supplier)
+	if err != nil {
+		log.Printf("[flow-3] CreateSupplier failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateSupplier success id=%d", created.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// ListPurchaseOrders menangani GET /api/purchase-order dengan filter opsional
+// ?status=, ?supplier_id= dan ?outstanding=true.
+func (h *PurchaseHandler) ListPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListPurchaseOrders start method=%s path=%s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	filter := store.PurchaseOrderFilter{Status: query.Get("status")}
+	if raw := query.Get("supplier_id"); raw != "" {
+		supplierID, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Printf("[flow-2] ListPurchaseOrders invalid supplier_id raw=%q", raw)
+			http.Error(w, "Invalid supplier_id", http.StatusBadRequest)
+			return
+		}
+		filter.SupplierID = supplierID
+	}
+	if raw := query.Get("outstanding"); raw != "" {
+		outstanding, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Printf("[flow-2] ListPurchaseOrders invalid outstanding raw=%q", raw)
+			http.Error(w, "Invalid outstanding, use true or false", http.StatusBadRequest)
+			return
+		}
+		filter.Outstanding = outstanding
+	}
+	log.Printf("[flow-2] ListPurchaseOrders filter=%+v", filter)
+
+	orders, err := h.store.GetAllPurchaseOrders(r.Context(), filter)
+	if err != nil {
+		log.Printf("[flow-3] ListPurchaseOrders failed err=%v", err)
+		http.Error(w, "Failed to get purchase orders", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-4] ListPurchaseOrders success count=%d", len(orders))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// CreatePurchaseOrder menangani POST /api/purchase-order.
+func (h *PurchaseHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreatePurchaseOrder start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] CreatePurchaseOrder decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreatePurchaseOrder supplier_id=%d items=%d operator=%q", req.SupplierID, len(req.Items), req.Operator)
+
+	order, err := h.store.CreatePurchaseOrder(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] CreatePurchaseOrder failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreatePurchaseOrder success id=%d total_cost=%d", order.ID, order.TotalCost)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+// GetPurchaseOrder menangani GET /api/purchase-order/{id}.
+func (h *PurchaseHandler) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetPurchaseOrder start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/purchase-order/", "")
+	if !ok {
+		return
+	}
+
+	order, err := h.store.GetPurchaseOrder(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetPurchaseOrder failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetPurchaseOrder success id=%d status=%s", id, order.Status)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// ApprovePurchaseOrder menangani POST /api/purchase-order/{id}/approve.
+func (h *PurchaseHandler) ApprovePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ApprovePurchaseOrder start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/purchase-order/", "/approve")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.PurchaseOrderActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] ApprovePurchaseOrder decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] ApprovePurchaseOrder id=%d operator=%q", id, req.Operator)
+
+	order, err := h.store.ApprovePurchaseOrder(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] ApprovePurchaseOrder failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] ApprovePurchaseOrder success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// ReceivePurchaseOrder menangani POST /api/purchase-order/{id}/receive.
+func (h *PurchaseHandler) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ReceivePurchaseOrder start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/purchase-order/", "/receive")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.ReceivePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] ReceivePurchaseOrder decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] ReceivePurchaseOrder id=%d items=%d operator=%q", id, len(req.Items), req.Operator)
+
+	receipt, err := h.store.ReceivePurchaseOrder(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] ReceivePurchaseOrder failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] ReceivePurchaseOrder success id=%d receipt_id=%d", id, receipt.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// CancelPurchaseOrder menangani POST /api/purchase-order/{id}/cancel.
+func (h *PurchaseHandler) CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CancelPurchaseOrder start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/purchase-order/", "/cancel")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.PurchaseOrderActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] CancelPurchaseOrder decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] CancelPurchaseOrder id=%d operator=%q", id, req.Operator)
+
+	order, err := h.store.CancelPurchaseOrder(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] CancelPurchaseOrder failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] CancelPurchaseOrder success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
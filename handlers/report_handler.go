@@ -0,0 +1,141 @@
+// Package handlers menyimpan HTTP handler untuk laporan.
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// ReportHandler menangani HTTP request untuk laporan.
+type ReportHandler struct {
+	store store.ReportStore
+}
+
+// NewReportHandler membuat ReportHandler dengan store yang diberikan.
+func NewReportHandler(s store.ReportStore) *ReportHandler {
+	return &ReportHandler{store: s}
+}
+
+// SalesSummary menangani GET /api/report/sales?start=YYYY-MM-DD&end=YYYY-MM-DD.
+func (h *ReportHandler) SalesSummary(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] SalesSummary start method=%s path=%s", r.Method, r.URL.Path)
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		log.Printf("[flow-2] SalesSummary invalid date range err=%v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] SalesSummary range start=%s end=%s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+
+	summary, err := h.store.GetSalesSummary(r.Context(), start, end)
+	if err != nil {
+		log.Printf("[flow-3] SalesSummary failed err=%v", err)
+		http.Error(w, "Failed to get sales summary", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-4] SalesSummary gross=%d net=%d", summary.GrossSales, summary.NetSales)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// MarginReport menangani GET /api/report/margin?start=YYYY-MM-DD&end=YYYY-MM-DD&group_by=product|kategori|day.
+func (h *ReportHandler) MarginReport(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] MarginReport start method=%s path=%s", r.Method, r.URL.Path)
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		log.Printf("[flow-2] MarginReport invalid date range err=%v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = models.MarginGroupProduct
+	}
+	if !store.IsValidMarginGroup(groupBy) {
+		log.Printf("[flow-2] MarginReport invalid group_by=%q", groupBy)
+		http.Error(w, "Invalid group_by, use product, kategori or day", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] MarginReport range start=%s end=%s group_by=%s",
+		start.Format(time.DateOnly), end.Format(time.DateOnly), groupBy)
+
+	report, err := h.store.GetMarginReport(r.Context(), start, end, groupBy)
+	if err != nil {
+		log.Printf("[flow-3] MarginReport failed err=%v", err)
+		http.Error(w, "Failed to get margin report", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-4] MarginReport lines=%d revenue=%d margin=%d",
+		len(report.Lines), report.Total.Revenue, report.Total.GrossMargin)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// TaxReport menangani GET /api/report/tax?start=YYYY-MM-DD&end=YYYY-MM-DD.
+func (h *ReportHandler) TaxReport(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] TaxReport start method=%s path=%s", r.Method, r.URL.Path)
+
+	start, end, err := parseDateRange(r)
+	if err != nil {
+		log.Printf("[flow-2] TaxReport invalid date range err=%v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] TaxReport range start=%s end=%s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+
+	report, err := h.store.GetTaxReport(r.Context(), start, end)
+	if err != nil {
+		log.Printf("[flow-3] TaxReport failed err=%v", err)
+		http.Error(w, "Failed to get tax report", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-4] TaxReport lines=%d tax=%d service=%d", len(report.Lines), report.TotalTax, report.TotalService)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseDateRange membaca query parameter start dan end (YYYY-MM-DD, inklusif).
+// Default keduanya hari ini. Nilai end yang dikembalikan eksklusif (end + 1 hari).
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	start := today
+	if raw := r.URL.Query().Get("start"); raw != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, raw, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid start date, use YYYY-MM-DD")
+		}
+		start = parsed
+	}
+
+	end := start
+	if raw := r.URL.Query().Get("end"); raw != "" {
+		parsed, err := time.ParseInLocation(time.DateOnly, raw, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid end date, use YYYY-MM-DD")
+		}
+		end = parsed
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("End date must not be before start date")
+	}
+
+	return start, end.AddDate(0, 0, 1), nil
+}
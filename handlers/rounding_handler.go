@@ -0,0 +1,90 @@
+// Package handlers menyimpan HTTP handler untuk kebijakan pembulatan.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// RoundingHandler menangani HTTP request untuk kebijakan pembulatan per metode pembayaran.
+type RoundingHandler struct {
+	store store.RoundingStore
+}
+
+// NewRoundingHandler membuat RoundingHandler dengan store yang diberikan.
+func NewRoundingHandler(s store.RoundingStore) *RoundingHandler {
+	return &RoundingHandler{store: s}
+}
+
+// ListRoundingPolicies menangani GET /api/rounding-policy.
+func (h *RoundingHandler) ListRoundingPolicies(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListRoundingPolicies start method=%s path=%s", r.Method, r.URL.Path)
+
+	policies, err := h.store.GetAllRoundingPolicies(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListRoundingPolicies failed err=%v", err)
+		http.Error(w, "Failed to get rounding policies", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListRoundingPolicies success count=%d", len(policies))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+// SetRoundingPolicy menangani PUT /api/rounding-policy/{method}.
+func (h *RoundingHandler) SetRoundingPolicy(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] SetRoundingPolicy start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Ambil metode pembayaran dari path URL.
+	method := strings.TrimPrefix(r.URL.Path, "/api/rounding-policy/")
+	log.Printf("[flow-2] SetRoundingPolicy payment_method=%q", method)
+
+	// Decode request body.
+	var policy models.RoundingPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		log.Printf("[flow-3] SetRoundingPolicy decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	policy.Method = method
+	log.Printf("[flow-3] SetRoundingPolicy mode=%s unit=%d", policy.Mode, policy.Unit)
+
+	saved, err := h.store.SetRoundingPolicy(r.Context(), policy)
+	if err != nil {
+		log.Printf("[flow-4] SetRoundingPolicy failed payment_method=%q err=%v", method, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] SetRoundingPolicy success payment_method=%s", saved.Method)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// DeleteRoundingPolicy menangani DELETE /api/rounding-policy/{method}.
+func (h *RoundingHandler) DeleteRoundingPolicy(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] DeleteRoundingPolicy start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Ambil metode pembayaran dari path URL.
+	method := strings.TrimPrefix(r.URL.Path, "/api/rounding-policy/")
+	log.Printf("[flow-2] DeleteRoundingPolicy payment_method=%q", method)
+
+	if err := h.store.DeleteRoundingPolicy(r.Context(), method); err != nil {
+		log.Printf("[flow-3] DeleteRoundingPolicy failed payment_method=%q err=%v", method, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] DeleteRoundingPolicy success payment_method=%s", method)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Kebijakan pembulatan berhasil dihapus"})
+}
@@ -0,0 +1,186 @@
+// Package handlers menyimpan HTTP handler untuk shift kasir.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// ShiftHandler menangani HTTP request untuk shift kasir dan rekonsiliasi laci.
+type ShiftHandler struct {
+	store store.ShiftStore
+}
+
+// NewShiftHandler membuat ShiftHandler dengan store yang diberikan.
+func NewShiftHandler(s store.ShiftStore) *ShiftHandler {
+	return &ShiftHandler{store: s}
+}
+
+// ListShifts menangani GET /api/shift dengan filter opsional ?register= dan ?status=.
+func (h *ShiftHandler) ListShifts(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListShifts start method=%s path=%s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	filter := store.ShiftFilter{Register: query.Get("register"), Status: query.Get("status")}
+	log.Printf("[flow-2] ListShifts register=%q status=%q", filter.Register, filter.Status)
+
+	shifts, err := h.store.GetAllShifts(r.Context(), filter)
+	if err != nil {
+		log.Printf("[flow-3] ListShifts failed err=%v", err)
+		http.Error(w, "Failed to get shifts", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-3] ListShifts success count=%d", len(shifts))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shifts)
+}
+
+// OpenShift menangani POST /api/shift.
+func (h *ShiftHandler) OpenShift(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] OpenShift start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] OpenShift decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !bindRegister(w, r, &req.Register) {
+		return
+	}
+	log.Printf("[flow-2] OpenShift register=%q cashier=%q opening_float=%d", req.Register, req.Cashier, req.OpeningFloat)
+
+	shift, err := h.store.OpenShift(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] OpenShift failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] OpenShift success id=%d register=%s", shift.ID, shift.Register)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(shift)
+}
+
+// GetShift menangani GET /api/shift/{id} beserta laporan per metode pembayaran.
+func (h *ShiftHandler) GetShift(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetShift start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/shift/", "")
+	if !ok {
+		return
+	}
+
+	shift, err := h.store.GetShift(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetShift failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetShift success id=%d status=%s expected_cash=%d", id, shift.Status, shift.ExpectedCash)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// CloseShift menangani POST /api/shift/{id}/close.
+func (h *ShiftHandler) CloseShift(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CloseShift start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/shift/", "/close")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] CloseShift decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] CloseShift id=%d counted_cash=%d counts=%d operator=%q", id, req.CountedCash, len(req.Counts), req.Operator)
+
+	shift, err := h.store.CloseShift(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] CloseShift failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] CloseShift success id=%d expected_cash=%d counted_cash=%d difference=%d",
+		id, shift.ExpectedCash, shift.CountedCash, shift.CashDifference)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(shift)
+}
+
+// RecordCashMovement menangani POST /api/cash-movement untuk pay-in/pay-out laci.
+func (h *ShiftHandler) RecordCashMovement(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RecordCashMovement start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.CashMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] RecordCashMovement decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !bindRegister(w, r, &req.Register) {
+		return
+	}
+	log.Printf("[flow-2] RecordCashMovement register=%q type=%q amount=%d operator=%q", req.Register, req.Type, req.Amount, req.Operator)
+
+	movement, err := h.store.RecordCashMovement(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] RecordCashMovement failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] RecordCashMovement success id=%d shift_id=%d", movement.ID, movement.ShiftID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
+
+// RecordNoSale menangani POST /api/no-sale untuk membuka laci tanpa penjualan.
+func (h *ShiftHandler) RecordNoSale(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RecordNoSale start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.NoSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] RecordNoSale decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !bindRegister(w, r, &req.Register) {
+		return
+	}
+	log.Printf("[flow-2] RecordNoSale register=%q operator=%q", req.Register, req.Operator)
+
+	noSale, err := h.store.RecordNoSale(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] RecordNoSale failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] RecordNoSale success id=%d shift_id=%d", noSale.ID, noSale.ShiftID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(noSale)
+}
@@ -0,0 +1,139 @@
+// Package handlers menyimpan HTTP handler untuk aturan pajak.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// TaxHandler menangani HTTP request untuk aturan pajak dan service charge.
+type TaxHandler struct {
+	store store.TaxStore
+}
+
+// NewTaxHandler membuat TaxHandler dengan store yang diberikan.
+func NewTaxHandler(s store.TaxStore) *TaxHandler {
+	return &TaxHandler{store: s}
+}
+
+// ListTaxRules menangani GET /api/tax-rule.
+func (h *TaxHandler) ListTaxRules(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListTaxRules start method=%s path=%s", r.Method, r.URL.Path)
+
+	rules, err := h.store.GetAllTaxRules(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListTaxRules failed err=%v", err)
+		http.Error(w, "Failed to get tax rules", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListTaxRules success count=%d", len(rules))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreateTaxRule menangani POST /api/tax-rule.
+func (h *TaxHandler) CreateTaxRule(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateTaxRule start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body. Aturan pajak baru aktif kecuali dikirim "active": false.
+	rule := models.TaxRule{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		log.Printf("[flow-2] CreateTaxRule decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreateTaxRule name=%q type=%s rate=%.2f inclusive=%t", rule.Name, rule.Type, rule.Rate, rule.Inclusive)
+
+	created, err := h.store.AddTaxRule(r.Context(), rule)
+	if err != nil {
+		log.Printf("[flow-3] CreateTaxRule failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateTaxRule success id=%d", created.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetTaxRule menangani GET /api/tax-rule/{id}.
+func (h *TaxHandler) GetTaxRule(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetTaxRule start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/tax-rule/", "")
+	if !ok {
+		return
+	}
+
+	rule, err := h.store.GetTaxRuleByID(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetTaxRule failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetTaxRule success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateTaxRule menangani PUT /api/tax-rule/{id}.
+func (h *TaxHandler) UpdateTaxRule(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdateTaxRule start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/tax-rule/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	rule := models.TaxRule{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		log.Printf("[flow-3] UpdateTaxRule decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] UpdateTaxRule id=%d name=%q rate=%.2f active=%t", id, rule.Name, rule.Rate, rule.Active)
+
+	updated, err := h.store.UpdateTaxRule(r.Context(), id, rule)
+	if err != nil {
+		log.Printf("[flow-4] UpdateTaxRule failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] UpdateTaxRule success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteTaxRule menangani DELETE /api/tax-rule/{id}.
+func (h *TaxHandler) DeleteTaxRule(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] DeleteTaxRule start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/tax-rule/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteTaxRule(r.Context(), id); err != nil {
+		log.Printf("[flow-3] DeleteTaxRule failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] DeleteTaxRule success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Aturan pajak berhasil dihapus"})
+}
@@ -2,7 +2,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -12,21 +14,52 @@ import (
 	"kasir-api/store"
 )
 
+// Header Idempotency-Key checkout dan penanda response hasil replay.
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLength sesuai kolom checkout_idempotency_keys.key.
+const maxIdempotencyKeyLength = 255
+
+// TransactionHandler menangani HTTP request untuk transaksi.
+type TransactionHandler struct {
+	store                   store.TransactionStore
+	discountApprovalPercent int
+	maxLineQuantity         int
+}
+
+// NewTransactionHandler membuat TransactionHandler dengan store, batas potongan
+// manual (persen subtotal) yang boleh diberikan tanpa persetujuan manajer, dan
+// jumlah maksimum satu produk dalam satu checkout.
+func NewTransactionHandler(s store.TransactionStore, discountApprovalPercent, maxLineQuantity int) *TransactionHandler {
+	return &TransactionHandler{store: s, discountApprovalPercent: discountApprovalPercent, maxLineQuantity: maxLineQuantity}
+}
+
 // HandleCheckout menangani /api/checkout (POST).
-func HandleCheckout(w http.ResponseWriter, r *http.Request) {
+func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[flow-1] HandleCheckout start method=%s path=%s", r.Method, r.URL.Path)
 
 	switch r.Method {
 	case http.MethodPost:
-		Checkout(w, r)
+		h.Checkout(w, r)
 	default:
 		log.Printf("[flow-2] HandleCheckout method not allowed method=%s", r.Method)
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-// Checkout menangani POST /api/checkout.
-func Checkout(w http.ResponseWriter, r *http.Request) {
+// Checkout menangani POST /api/checkout. Dengan header Idempotency-Key, hasil
+// checkout pertama disimpan per pengguna atau kunci API: request ulang dengan
+// key dan body yang sama mendapat transaksi yang sama tanpa checkout baru, key
+// dengan body berbeda mendapat 422, dan key yang checkout-nya masih berjalan
+// mendapat 409.
+// Baris items dengan produk yang sama digabung; error dikirim sebagai JSON
+// dengan kesalahan per baris di field lines. PIN atau token persetujuan hanya
+// diperiksa jika checkout membutuhkannya, jadi request ulang dengan
+// Idempotency-Key yang sama tidak memakai token lagi.
+func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[flow-1] Checkout start method=%s path=%s", r.Method, r.URL.Path)
 
 	// Decode request body.
@@ -35,28 +68,70 @@ func Checkout(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		log.Printf("[flow-3] Checkout decode failed err=%v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeCheckoutError(w, errors.New("Invalid request body"), http.StatusBadRequest)
+		return
+	}
+	if !bindRegister(w, r, &req.Register) {
 		return
 	}
 
 	// Validasi request.
 	if len(req.Items) == 0 {
 		log.Printf("[flow-3] Checkout empty items")
-		http.Error(w, "Items cannot be empty", http.StatusBadRequest)
+		writeCheckoutError(w, errors.New("Items cannot be empty"), http.StatusBadRequest)
+		return
+	}
+
+	req.DiscountApprovalPercent = h.discountApprovalPercent
+	req.MaxLineQuantity = h.maxLineQuantity
+	req.IdempotencyKey = strings.TrimSpace(r.Header.Get(headerIdempotencyKey))
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		log.Printf("[flow-3] Checkout idempotency key too long length=%d", len(req.IdempotencyKey))
+		writeCheckoutError(w, errors.New("Idempotency-Key is too long"), http.StatusBadRequest)
 		return
 	}
+	req.IdempotencyScope = idempotencyScope(r.Context())
+	log.Printf("[flow-3] Checkout register=%q items count=%d payments count=%d manual_discount=%d idempotency_key=%q scope=%s",
+		req.Register, len(req.Items), len(req.Payments), req.ManualDiscount, req.IdempotencyKey, req.IdempotencyScope)
 
-	log.Printf("[flow-3] Checkout items count=%d", len(req.Items))
+	// Klaim Idempotency-Key lebih dulu; request ulang mendapat transaksi yang tersimpan.
+	if req.IdempotencyKey != "" {
+		replayed, err := h.store.ClaimCheckoutKey(r.Context(), req)
+		if err != nil {
+			log.Printf("[flow-4] Checkout idempotency key rejected key=%q err=%v", req.IdempotencyKey, err)
+			writeCheckoutError(w, err, checkoutErrorStatus(err))
+			return
+		}
+		if replayed != nil {
+			log.Printf("[flow-4] Checkout replayed key=%q id=%d", req.IdempotencyKey, replayed.ID)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(headerIdempotentReplayed, "true")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(replayed)
+			return
+		}
+	}
 
-	// Panggil store untuk membuat transaksi.
-	transaction, err := store.CreateTransaction(req.Items)
+	// Panggil store untuk membuat transaksi. Persetujuan manajer dari header
+	// baru diperiksa jika store memintanya.
+	transaction, err := h.store.CreateTransaction(r.Context(), req)
+	if errors.Is(err, store.ErrApprovalRequired) {
+		transaction, err = h.checkoutWithApproval(r.Context(), req, err)
+	}
 	if err != nil {
 		log.Printf("[flow-4] Checkout create transaction failed err=%v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// Checkout yang gagal tidak disimpan agar key yang sama bisa dipakai mencoba
+		// lagi. Klaim tetap dilepas walau klien sudah memutus request.
+		if req.IdempotencyKey != "" {
+			if err := h.store.ReleaseCheckoutKey(context.WithoutCancel(r.Context()), req); err != nil {
+				log.Printf("[flow-4] Checkout release idempotency key failed key=%q err=%v", req.IdempotencyKey, err)
+			}
+		}
+		writeCheckoutError(w, err, checkoutErrorStatus(err))
 		return
 	}
 
-	log.Printf("[flow-5] Checkout success id=%d total=%d", transaction.ID, transaction.TotalAmount)
+	log.Printf("[flow-5] Checkout success id=%d shift_id=%d total=%d change=%d", transaction.ID, transaction.ShiftID, transaction.TotalAmount, transaction.ChangeAmount)
 
 	// Kirim response.
 	w.Header().Set("Content-Type", "application/json")
@@ -64,8 +139,72 @@ func Checkout(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(transaction)
 }
 
+// idempotencyScope mengembalikan pemilik Idempotency-Key: kunci API atau
+// pengguna yang login, sehingga key yang sama dari pemilik lain tidak bisa
+// membaca transaksinya.
+func idempotencyScope(ctx context.Context) string {
+	if key, ok := CurrentAPIKey(ctx); ok {
+		return "api_key:" + strconv.Itoa(key.ID)
+	}
+	if user, ok := CurrentUser(ctx); ok {
+		return "user:" + strconv.Itoa(user.ID)
+	}
+	return ""
+}
+
+// checkoutWithApproval mengulang checkout yang ditolak store karena butuh
+// persetujuan, setelah persetujuan yang ditunda Approve diperiksa. Checkout
+// pertama sudah di-rollback seluruhnya dan Idempotency-Key-nya masih diklaim,
+// jadi aman diulang. Tanpa persetujuan di request, approvalErr dikembalikan.
+func (h *TransactionHandler) checkoutWithApproval(ctx context.Context, req models.CheckoutRequest, approvalErr error) (*models.Transaction, error) {
+	ctx, ok, err := resolveApproval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, approvalErr
+	}
+	log.Printf("[flow-4] Checkout retry with manager approval register=%q", req.Register)
+	return h.store.CreateTransaction(ctx, req)
+}
+
+// checkoutErrorStatus memilih status HTTP berdasarkan error dari store.
+func checkoutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, store.ErrInvalidPayment), errors.Is(err, store.ErrUnderpaid), errors.Is(err, store.ErrInvalidVoucher),
+		errors.Is(err, store.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrConflict), errors.Is(err, store.ErrInsufficientStock):
+		return http.StatusConflict
+	case errors.Is(err, store.ErrApprovalRequired), errors.Is(err, store.ErrUnauthorized):
+		// ErrUnauthorized berasal dari PIN atau token persetujuan yang tidak valid.
+		return http.StatusForbidden
+	case errors.Is(err, store.ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	case errors.Is(err, store.ErrIdempotencyMismatch):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeCheckoutError menulis error checkout sebagai JSON. Kesalahan per baris
+// items dari store dikirim di field lines agar kasir tahu baris mana yang harus diperbaiki.
+func writeCheckoutError(w http.ResponseWriter, err error, status int) {
+	resp := models.CheckoutErrorResponse{Error: err.Error()}
+	var itemsErr *store.CheckoutItemsError
+	if errors.As(err, &itemsErr) {
+		resp.Error = itemsErr.Err.Error()
+		resp.Lines = itemsErr.Lines
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
 // GetTransactionByID menangani GET /api/transaction/{id}.
-func GetTransactionByID(w http.ResponseWriter, r *http.Request) {
+func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[flow-1] GetTransactionByID start method=%s path=%s", r.Method, r.URL.Path)
 
 	// Ambil ID dari path URL.
@@ -80,7 +219,7 @@ func GetTransactionByID(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[flow-3] GetTransactionByID parsed id=%d", id)
 
 	// Ambil data dari store.
-	transaction, err := store.GetTransactionByID(id)
+	transaction, err := h.store.GetTransactionByID(r.Context(), id)
 	if err != nil {
 		log.Printf("[flow-4] GetTransactionByID not found id=%d err=%v", id, err)
 		http.Error(w, err.Error(), http.StatusNotFound)
@@ -94,18 +233,131 @@ func GetTransactionByID(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetAllTransactions menangani GET /api/transaction.
-func GetAllTransactions(w http.ResponseWriter, r *http.Request) {
+func (h *TransactionHandler) GetAllTransactions(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[flow-1] GetAllTransactions start method=%s path=%s", r.Method, r.URL.Path)
 
-	transactions, err := store.GetAllTransactions()
+	// Ambil filter metode pembayaran dari query parameter.
+	filter := store.TransactionFilter{
+		PaymentMethod: r.URL.Query().Get("payment_method"),
+	}
+	log.Printf("[flow-2] GetAllTransactions payment_method filter=%q", filter.PaymentMethod)
+	if filter.PaymentMethod != "" && !store.IsValidPaymentMethod(filter.PaymentMethod) {
+		log.Printf("[flow-3] GetAllTransactions invalid payment_method=%q", filter.PaymentMethod)
+		http.Error(w, "Invalid payment_method", http.StatusBadRequest)
+		return
+	}
+	if raw := r.URL.Query().Get("shift_id"); raw != "" {
+		shiftID, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Printf("[flow-3] GetAllTransactions invalid shift_id raw=%q", raw)
+			http.Error(w, "Invalid shift_id", http.StatusBadRequest)
+			return
+		}
+		filter.ShiftID = shiftID
+	}
+
+	transactions, err := h.store.GetAllTransactions(r.Context(), filter)
 	if err != nil {
-		log.Printf("[flow-2] GetAllTransactions failed err=%v", err)
+		log.Printf("[flow-3] GetAllTransactions failed err=%v", err)
 		http.Error(w, "Failed to get transactions", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("[flow-3] GetAllTransactions total=%d", len(transactions))
+	log.Printf("[flow-4] GetAllTransactions total=%d", len(transactions))
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(transactions)
 }
+
+// VoidTransaction menangani POST /api/transaction/{id}/void.
+func (h *TransactionHandler) VoidTransaction(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] VoidTransaction start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Ambil ID dari path URL.
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/transaction/"), "/void")
+	log.Printf("[flow-2] VoidTransaction parse id raw=%q", idStr)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Printf("[flow-3] VoidTransaction parse id failed err=%v", err)
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	// Decode request body.
+	var req models.VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] VoidTransaction decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] VoidTransaction id=%d operator=%q", id, req.Operator)
+
+	reversal, err := h.store.VoidTransaction(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] VoidTransaction failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), reversalErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] VoidTransaction success id=%d reversal_id=%d amount=%d", id, reversal.ID, reversal.Amount)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reversal)
+}
+
+// RefundTransaction menangani POST /api/transaction/{id}/refund.
+func (h *TransactionHandler) RefundTransaction(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] RefundTransaction start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Ambil ID dari path URL.
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/transaction/"), "/refund")
+	log.Printf("[flow-2] RefundTransaction parse id raw=%q", idStr)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		log.Printf("[flow-3] RefundTransaction parse id failed err=%v", err)
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	// Decode request body.
+	var req models.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] RefundTransaction decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !bindRegister(w, r, &req.Register) {
+		return
+	}
+	log.Printf("[flow-3] RefundTransaction id=%d items=%d operator=%q", id, len(req.Items), req.Operator)
+
+	reversal, err := h.store.RefundTransaction(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] RefundTransaction failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), reversalErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] RefundTransaction success id=%d reversal_id=%d amount=%d", id, reversal.ID, reversal.Amount)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reversal)
+}
+
+// reversalErrorStatus memilih status HTTP untuk error void/refund dari store.
+func reversalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, store.ErrInvalidReversal):
+		return http.StatusBadRequest
+	case errors.Is(err, store.ErrReversalNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, store.ErrApprovalRequired):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
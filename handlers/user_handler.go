@@ -0,0 +1,97 @@
+// Package handlers menyimpan HTTP handler untuk akun pengguna.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// UserHandler menangani HTTP request untuk akun pengguna.
+type UserHandler struct {
+	store store.UserStore
+}
+
+// NewUserHandler membuat UserHandler dengan store yang diberikan.
+func NewUserHandler(s store.UserStore) *UserHandler {
+	return &UserHandler{store: s}
+}
+
+// ListUsers menangani GET /api/user.
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListUsers start method=%s path=%s", r.Method, r.URL.Path)
+
+	users, err := h.store.GetAllUsers(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListUsers failed err=%v", err)
+		http.Error(w, "Failed to get users", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListUsers success count=%d", len(users))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// CreateUser menangani POST /api/user.
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateUser start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body.
+	var req models.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-2] CreateUser decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreateUser username=%q role=%q", req.Username, req.Role)
+
+	user, err := h.store.CreateUser(r.Context(), req)
+	if err != nil {
+		log.Printf("[flow-3] CreateUser failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateUser success id=%d", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateUser menangani PUT /api/user/{id}.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdateUser start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/user/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[flow-3] UpdateUser decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] UpdateUser id=%d role_changed=%t active_changed=%t password_changed=%t pin_changed=%t",
+		id, req.Role != nil, req.Active != nil, req.Password != nil, req.PIN != nil)
+
+	user, err := h.store.UpdateUser(r.Context(), id, req)
+	if err != nil {
+		log.Printf("[flow-4] UpdateUser failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] UpdateUser success id=%d role=%s active=%t", id, user.Role, user.Active)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
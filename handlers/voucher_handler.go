@@ -0,0 +1,118 @@
+// Package handlers menyimpan HTTP handler untuk voucher.
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"kasir-api/models"
+	"kasir-api/store"
+)
+
+// VoucherHandler menangani HTTP request untuk kode voucher.
+type VoucherHandler struct {
+	store store.VoucherStore
+}
+
+// NewVoucherHandler membuat VoucherHandler dengan store yang diberikan.
+func NewVoucherHandler(s store.VoucherStore) *VoucherHandler {
+	return &VoucherHandler{store: s}
+}
+
+// ListVouchers menangani GET /api/voucher.
+func (h *VoucherHandler) ListVouchers(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] ListVouchers start method=%s path=%s", r.Method, r.URL.Path)
+
+	vouchers, err := h.store.GetAllVouchers(r.Context())
+	if err != nil {
+		log.Printf("[flow-2] ListVouchers failed err=%v", err)
+		http.Error(w, "Failed to get vouchers", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[flow-2] ListVouchers success count=%d", len(vouchers))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// CreateVoucher menangani POST /api/voucher.
+func (h *VoucherHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] CreateVoucher start method=%s path=%s", r.Method, r.URL.Path)
+
+	// Decode request body. Voucher baru aktif kecuali dikirim "active": false.
+	voucher := models.Voucher{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&voucher); err != nil {
+		log.Printf("[flow-2] CreateVoucher decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-2] CreateVoucher code=%q discount_type=%s", voucher.Code, voucher.DiscountType)
+
+	created, err := h.store.AddVoucher(r.Context(), voucher)
+	if err != nil {
+		log.Printf("[flow-3] CreateVoucher failed err=%v", err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] CreateVoucher success id=%d", created.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetVoucher menangani GET /api/voucher/{id}.
+func (h *VoucherHandler) GetVoucher(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] GetVoucher start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/voucher/", "")
+	if !ok {
+		return
+	}
+
+	voucher, err := h.store.GetVoucherByID(r.Context(), id)
+	if err != nil {
+		log.Printf("[flow-3] GetVoucher failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-4] GetVoucher success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// UpdateVoucher menangani PUT /api/voucher/{id}.
+func (h *VoucherHandler) UpdateVoucher(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[flow-1] UpdateVoucher start method=%s path=%s", r.Method, r.URL.Path)
+
+	id, ok := parsePathID(w, r, "/api/voucher/", "")
+	if !ok {
+		return
+	}
+
+	// Decode request body.
+	voucher := models.Voucher{Active: true}
+	if err := json.NewDecoder(r.Body).Decode(&voucher); err != nil {
+		log.Printf("[flow-3] UpdateVoucher decode failed err=%v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	log.Printf("[flow-3] UpdateVoucher id=%d code=%q active=%t", id, voucher.Code, voucher.Active)
+
+	updated, err := h.store.UpdateVoucher(r.Context(), id, voucher)
+	if err != nil {
+		log.Printf("[flow-4] UpdateVoucher failed id=%d err=%v", id, err)
+		http.Error(w, err.Error(), storeErrorStatus(err))
+		return
+	}
+
+	log.Printf("[flow-5] UpdateVoucher success id=%d", id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
@@ -4,9 +4,13 @@ package main
 import (
 	"log"
 	"net/http"
+	"strings"
 
+	"kasir-api/config"
 	"kasir-api/database"
 	"kasir-api/handlers"
+	"kasir-api/models"
+	"kasir-api/store"
 )
 
 // main mendaftarkan route dan menyalakan server.
@@ -15,95 +19,574 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
 	// Load konfigurasi dari environment variables atau file .env
-	config := LoadConfig()
+	cfg := config.LoadConfig()
 
 	// Koneksi ke database
-	err := database.ConnectDatabase(config.GetDBConnectionString())
+	err := database.ConnectDatabase(cfg.GetDBConnectionString())
 	if err != nil {
 		log.Fatalf("[main] Gagal koneksi ke database: %v", err)
 	}
 	defer database.CloseDatabase()
 
-	// Endpoint untuk operasi berdasarkan ID (GET/PUT/DELETE).
-	http.HandleFunc("/api/produk/", func(w http.ResponseWriter, r *http.Request) {
+	// Siapkan store PostgreSQL lalu suntikkan ke masing-masing handler.
+	pgStore := store.NewPostgresStore(database.DB)
+	produkHandler := handlers.NewProdukHandler(pgStore)
+	kategoriHandler := handlers.NewKategoriHandler(pgStore)
+	transactionHandler := handlers.NewTransactionHandler(pgStore, cfg.DiscountApprovalPercent, cfg.MaxLineQuantity)
+	reportHandler := handlers.NewReportHandler(pgStore)
+	opnameHandler := handlers.NewOpnameHandler(pgStore)
+	purchaseHandler := handlers.NewPurchaseHandler(pgStore)
+	promotionHandler := handlers.NewPromotionHandler(pgStore)
+	voucherHandler := handlers.NewVoucherHandler(pgStore)
+	taxHandler := handlers.NewTaxHandler(pgStore)
+	roundingHandler := handlers.NewRoundingHandler(pgStore)
+	customerHandler := handlers.NewCustomerHandler(pgStore)
+	loyaltyHandler := handlers.NewLoyaltyHandler(pgStore)
+	shiftHandler := handlers.NewShiftHandler(pgStore)
+	authHandler := handlers.NewAuthHandler(pgStore, pgStore, cfg.SessionTTL)
+	userHandler := handlers.NewUserHandler(pgStore)
+	approvalHandler := handlers.NewApprovalHandler(pgStore, cfg.ApprovalTokenTTL)
+	apiKeyHandler := handlers.NewAPIKeyHandler(pgStore)
+	auditHandler := handlers.NewAuditHandler(pgStore)
+
+	// Semua endpoint /api kecuali login dibungkus authHandler.Protect dengan
+	// role yang boleh membaca (GET) dan mengubah (method lain). Terminal POS dan
+	// integrasi memakai header X-API-Key dengan scope <resource>:<read|write>.
+
+	// Endpoint login (POST), satu-satunya endpoint /api yang terbuka.
+	http.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			authHandler.Login(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Endpoint logout (POST) untuk mencabut token yang sedang dipakai.
+	http.HandleFunc("/api/auth/logout", authHandler.Protect(nil, handlers.RolesAll, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			authHandler.Logout(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint profil pengguna yang sedang login (GET).
+	http.HandleFunc("/api/auth/me", authHandler.Protect(handlers.RolesAll, nil, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.GetProdukByID(w, r)
+			authHandler.Me(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk akun pengguna berdasarkan ID (PUT), khusus owner.
+	http.HandleFunc("/api/user/", authHandler.Protect(handlers.RolesOwner, handlers.RolesOwner, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
 		case http.MethodPut:
-			handlers.UpdateProduk(w, r)
-		case http.MethodDelete:
-			handlers.DeleteProduk(w, r)
+			userHandler.UpdateUser(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
+
+	// Endpoint koleksi akun pengguna (GET semua, POST tambah), khusus owner.
+	http.HandleFunc("/api/user", authHandler.Protect(handlers.RolesOwner, handlers.RolesOwner, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			userHandler.ListUsers(w, r)
+		case http.MethodPost:
+			userHandler.CreateUser(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk kunci API berdasarkan ID (POST revoke/rotate), khusus owner.
+	http.HandleFunc("/api/api-key/", authHandler.Protect(nil, handlers.RolesOwner, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/revoke"):
+			apiKeyHandler.RevokeAPIKey(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rotate"):
+			apiKeyHandler.RotateAPIKey(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi kunci API (GET semua, POST buat), khusus owner.
+	http.HandleFunc("/api/api-key", authHandler.Protect(handlers.RolesOwner, handlers.RolesOwner, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			apiKeyHandler.ListAPIKeys(w, r)
+		case http.MethodPost:
+			apiKeyHandler.CreateAPIKey(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint token persetujuan sekali pakai (POST) yang dibuat manajer untuk kasir.
+	http.HandleFunc("/api/approval/token", authHandler.Protect(nil, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			approvalHandler.CreateToken(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint riwayat persetujuan manajer (GET, filter ?action= dan ?entity_id=).
+	http.HandleFunc("/api/approval", authHandler.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			approvalHandler.ListApprovals(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint audit log perubahan master data (GET, filter ?entity=, ?actor=, ?start= dan ?end=).
+	http.HandleFunc("/api/audit", authHandler.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			auditHandler.ListAudit(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk operasi berdasarkan ID (GET/PUT/DELETE) dan riwayat stok.
+	// Kasir boleh mengubah/menghapus produk dengan persetujuan manajer.
+	http.HandleFunc("/api/produk/", authHandler.Protect(handlers.RolesAll, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/stock-history"):
+			produkHandler.GetStockHistory(w, r)
+		case r.Method == http.MethodGet:
+			produkHandler.GetProdukByID(w, r)
+		case r.Method == http.MethodPut:
+			approvalHandler.Approve(models.ApprovalActionProdukUpdate, true, produkHandler.UpdateProduk)(w, r)
+		case r.Method == http.MethodDelete:
+			approvalHandler.Approve(models.ApprovalActionProdukDelete, true, produkHandler.DeleteProduk)(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	// Endpoint koleksi produk (GET semua, POST tambah).
-	http.HandleFunc("/api/produk", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/api/produk", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.ListProduk(w, r)
+			produkHandler.ListProduk(w, r)
 		case http.MethodPost:
-			handlers.CreateProduk(w, r)
+			produkHandler.CreateProduk(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
 
 	// Endpoint untuk operasi kategori berdasarkan ID (GET/PUT/DELETE).
-	http.HandleFunc("/api/kategori/", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/api/kategori/", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.GetKategoriByIDHandler(w, r)
+			kategoriHandler.GetKategoriByIDHandler(w, r)
 		case http.MethodPut:
-			handlers.UpdateKategoriHandler(w, r)
+			kategoriHandler.UpdateKategoriHandler(w, r)
 		case http.MethodDelete:
-			handlers.DeleteKategoriHandler(w, r)
+			kategoriHandler.DeleteKategoriHandler(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
 
 	// Endpoint koleksi kategori (GET semua, POST tambah).
-	http.HandleFunc("/api/kategori", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/api/kategori", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.GetKategoriHandler(w, r)
+			kategoriHandler.GetKategoriHandler(w, r)
 		case http.MethodPost:
-			handlers.CreateKategoriHandler(w, r)
+			kategoriHandler.CreateKategoriHandler(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
 
-	// Endpoint checkout (POST).
-	http.HandleFunc("/api/checkout", handlers.HandleCheckout)
+	// Endpoint untuk operasi promosi berdasarkan ID (GET/PUT/DELETE).
+	http.HandleFunc("/api/promotion/", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			promotionHandler.GetPromotion(w, r)
+		case http.MethodPut:
+			promotionHandler.UpdatePromotion(w, r)
+		case http.MethodDelete:
+			promotionHandler.DeletePromotion(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
 
-	// Endpoint untuk operasi transaksi berdasarkan ID (GET).
-	http.HandleFunc("/api/transaction/", func(w http.ResponseWriter, r *http.Request) {
+	// Endpoint koleksi promosi (GET semua, POST tambah).
+	http.HandleFunc("/api/promotion", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.GetTransactionByID(w, r)
+			promotionHandler.ListPromotions(w, r)
+		case http.MethodPost:
+			promotionHandler.CreatePromotion(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
+
+	// Endpoint untuk operasi voucher berdasarkan ID (GET/PUT).
+	http.HandleFunc("/api/voucher/", authHandler.Protect(handlers.RolesSales, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			voucherHandler.GetVoucher(w, r)
+		case http.MethodPut:
+			voucherHandler.UpdateVoucher(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi voucher (GET semua, POST tambah).
+	http.HandleFunc("/api/voucher", authHandler.Protect(handlers.RolesSales, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			voucherHandler.ListVouchers(w, r)
+		case http.MethodPost:
+			voucherHandler.CreateVoucher(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk operasi aturan pajak berdasarkan ID (GET/PUT/DELETE).
+	http.HandleFunc("/api/tax-rule/", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			taxHandler.GetTaxRule(w, r)
+		case http.MethodPut:
+			taxHandler.UpdateTaxRule(w, r)
+		case http.MethodDelete:
+			taxHandler.DeleteTaxRule(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi aturan pajak (GET semua, POST tambah).
+	http.HandleFunc("/api/tax-rule", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			taxHandler.ListTaxRules(w, r)
+		case http.MethodPost:
+			taxHandler.CreateTaxRule(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint kebijakan pembulatan per metode pembayaran (PUT simpan, DELETE hapus).
+	http.HandleFunc("/api/rounding-policy/", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			roundingHandler.SetRoundingPolicy(w, r)
+		case http.MethodDelete:
+			roundingHandler.DeleteRoundingPolicy(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint daftar kebijakan pembulatan (GET).
+	http.HandleFunc("/api/rounding-policy", authHandler.Protect(handlers.RolesAll, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			roundingHandler.ListRoundingPolicies(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk operasi pelanggan berdasarkan ID (GET/PUT/DELETE), riwayat transaksi, dan saldo poin.
+	http.HandleFunc("/api/customer/", authHandler.Protect(handlers.RolesSales, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/transactions"):
+			customerHandler.CustomerTransactions(w, r)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/points"):
+			loyaltyHandler.CustomerPoints(w, r)
+		case r.Method == http.MethodGet:
+			customerHandler.GetCustomer(w, r)
+		case r.Method == http.MethodPut:
+			customerHandler.UpdateCustomer(w, r)
+		case r.Method == http.MethodDelete:
+			customerHandler.DeleteCustomer(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi pelanggan (GET semua atau cari ?phone=, POST tambah).
+	http.HandleFunc("/api/customer", authHandler.Protect(handlers.RolesSales, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			customerHandler.ListCustomers(w, r)
+		case http.MethodPost:
+			customerHandler.CreateCustomer(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint pengaturan program poin (GET, PUT).
+	http.HandleFunc("/api/loyalty/settings", authHandler.Protect(handlers.RolesSales, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			loyaltyHandler.GetSettings(w, r)
+		case http.MethodPut:
+			loyaltyHandler.UpdateSettings(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint pengali poin per kategori (PUT simpan, DELETE hapus).
+	http.HandleFunc("/api/loyalty/multiplier/", authHandler.Protect(handlers.RolesSales, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			loyaltyHandler.SetMultiplier(w, r)
+		case http.MethodDelete:
+			loyaltyHandler.DeleteMultiplier(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi pengali poin (GET semua).
+	http.HandleFunc("/api/loyalty/multiplier", authHandler.Protect(handlers.RolesSales, handlers.RolesManagers, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			loyaltyHandler.ListMultipliers(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk shift kasir berdasarkan ID (GET laporan, POST close).
+	http.HandleFunc("/api/shift/", authHandler.Protect(handlers.RolesSales, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			shiftHandler.GetShift(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/close"):
+			shiftHandler.CloseShift(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi shift kasir (GET semua, POST buka shift).
+	http.HandleFunc("/api/shift", authHandler.Protect(handlers.RolesSales, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			shiftHandler.ListShifts(w, r)
+		case http.MethodPost:
+			shiftHandler.OpenShift(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint pay-in/pay-out laci pada shift yang terbuka (POST).
+	http.HandleFunc("/api/cash-movement", authHandler.Protect(nil, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			shiftHandler.RecordCashMovement(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint buka laci tanpa penjualan (POST), butuh persetujuan manajer.
+	http.HandleFunc("/api/no-sale", authHandler.Protect(nil, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			approvalHandler.Approve(models.ApprovalActionNoSale, true, shiftHandler.RecordNoSale)(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint checkout (POST). Ubah harga atau potongan manual di atas batas
+	// butuh persetujuan manajer.
+	http.HandleFunc("/api/checkout", authHandler.Protect(nil, handlers.RolesSales,
+		approvalHandler.Approve(models.ApprovalActionCheckout, false, transactionHandler.HandleCheckout)))
+
+	// Endpoint untuk operasi transaksi berdasarkan ID (GET, POST void/refund).
+	http.HandleFunc("/api/transaction/", authHandler.Protect(handlers.RolesSales, handlers.RolesSales, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			transactionHandler.GetTransactionByID(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/void"):
+			approvalHandler.Approve(models.ApprovalActionVoid, true, transactionHandler.VoidTransaction)(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/refund"):
+			transactionHandler.RefundTransaction(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	// Endpoint koleksi transaksi (GET semua).
-	http.HandleFunc("/api/transaction", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/api/transaction", authHandler.Protect(handlers.RolesSales, nil, func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
-			handlers.GetAllTransactions(w, r)
+			transactionHandler.GetAllTransactions(w, r)
 		default:
 			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
-	})
+	}))
+
+	// Endpoint laporan penjualan bersih (GET).
+	http.HandleFunc("/api/report/sales", authHandler.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			reportHandler.SalesSummary(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint laporan laba kotor per produk, kategori, atau hari (GET).
+	http.HandleFunc("/api/report/margin", authHandler.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			reportHandler.MarginReport(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint laporan pajak dan service charge per periode (GET).
+	http.HandleFunc("/api/report/tax", authHandler.Protect(handlers.RolesManagers, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			reportHandler.TaxReport(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint untuk sesi stock opname berdasarkan ID (GET laporan, POST counts/finalize/cancel).
+	http.HandleFunc("/api/opname/", authHandler.Protect(handlers.RolesStock, handlers.RolesStock, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			opnameHandler.GetOpname(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/counts"):
+			opnameHandler.RecordCounts(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/finalize"):
+			opnameHandler.FinalizeOpname(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/cancel"):
+			opnameHandler.CancelOpname(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi sesi stock opname (GET semua, POST buka sesi).
+	http.HandleFunc("/api/opname", authHandler.Protect(handlers.RolesStock, handlers.RolesStock, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			opnameHandler.ListOpname(w, r)
+		case http.MethodPost:
+			opnameHandler.OpenOpname(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi supplier (GET semua, POST tambah).
+	http.HandleFunc("/api/supplier", authHandler.Protect(handlers.RolesStock, handlers.RolesStock, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			purchaseHandler.ListSuppliers(w, r)
+		case http.MethodPost:
+			purchaseHandler.CreateSupplier(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint purchase order berdasarkan ID (GET, POST approve/receive/cancel).
+	http.HandleFunc("/api/purchase-order/", authHandler.Protect(handlers.RolesStock, handlers.RolesStock, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			purchaseHandler.GetPurchaseOrder(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/approve"):
+			purchaseHandler.ApprovePurchaseOrder(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/receive"):
+			purchaseHandler.ReceivePurchaseOrder(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/cancel"):
+			purchaseHandler.CancelPurchaseOrder(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Endpoint koleksi purchase order (GET daftar/outstanding, POST buat).
+	http.HandleFunc("/api/purchase-order", authHandler.Protect(handlers.RolesStock, handlers.RolesStock, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			purchaseHandler.ListPurchaseOrders(w, r)
+		case http.MethodPost:
+			purchaseHandler.CreatePurchaseOrder(w, r)
+		default:
+			log.Printf("[flow-0] Method not allowed method=%s path=%s", r.Method, r.URL.Path)
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	// Endpoint health check untuk memastikan server hidup.
 	http.HandleFunc("/health", handlers.Health)
@@ -122,10 +605,10 @@ func main() {
 	}))
 
 	// Log sederhana saat server mulai jalan.
-	log.Printf("[flow-0] Server running di %s:%s", config.Host, config.Port)
+	log.Printf("[flow-0] Server running di %s:%s", cfg.Host, cfg.Port)
 
 	// Jalankan HTTP server dengan konfigurasi dari env atau .env
-	addr := config.Host + ":" + config.Port
+	addr := cfg.Host + ":" + cfg.Port
 	err = http.ListenAndServe(addr, nil)
 	if err != nil {
 		// Tampilkan error jika server gagal start.
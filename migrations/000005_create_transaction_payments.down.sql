@@ -0,0 +1,7 @@
+-- Drop index dan tabel transaction_payments.
+DROP INDEX IF EXISTS idx_transaction_payments_transaction_id;
+DROP TABLE IF EXISTS transaction_payments;
+
+-- Hapus kolom pembayaran dari transactions.
+ALTER TABLE transactions DROP COLUMN IF EXISTS change_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS paid_amount;
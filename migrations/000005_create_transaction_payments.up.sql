@@ -0,0 +1,18 @@
+-- Mencatat jumlah bayar dan kembalian pada setiap transaksi.
+ALTER TABLE transactions ADD COLUMN paid_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN change_amount INT NOT NULL DEFAULT 0;
+
+-- Transaksi lama dianggap dibayar pas.
+UPDATE transactions SET paid_amount = total_amount;
+
+-- Membuat tabel transaction_payments untuk menyimpan cara pelanggan membayar.
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'debit', 'qris', 'e-wallet')),
+    amount INT NOT NULL CHECK (amount >= 0),
+    reference VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+-- Membuat index untuk performa query pembayaran berdasarkan transaction_id.
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction_id ON transaction_payments(transaction_id);
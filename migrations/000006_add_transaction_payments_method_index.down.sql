@@ -0,0 +1,2 @@
+-- Drop index metode pembayaran.
+DROP INDEX IF EXISTS idx_transaction_payments_method;
@@ -0,0 +1,2 @@
+-- Membuat index untuk filter daftar transaksi berdasarkan metode pembayaran.
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_method ON transaction_payments(method, transaction_id);
@@ -0,0 +1,11 @@
+-- Drop index reversal terlebih dahulu.
+DROP INDEX IF EXISTS idx_transaction_reversal_items_detail_id;
+DROP INDEX IF EXISTS idx_transaction_reversals_created_at;
+DROP INDEX IF EXISTS idx_transaction_reversals_transaction_id;
+
+-- Drop tabel reversal.
+DROP TABLE IF EXISTS transaction_reversal_items;
+DROP TABLE IF EXISTS transaction_reversals;
+
+-- Hapus kolom status dari transactions.
+ALTER TABLE transactions DROP COLUMN IF EXISTS status;
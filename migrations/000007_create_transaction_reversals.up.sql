@@ -0,0 +1,29 @@
+-- Menambahkan status transaksi agar void/refund bisa dilacak.
+ALTER TABLE transactions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed'
+    CHECK (status IN ('completed', 'voided', 'refunded', 'partially_refunded'));
+
+-- Membuat tabel transaction_reversals untuk mencatat void dan refund.
+CREATE TABLE IF NOT EXISTS transaction_reversals (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('void', 'refund')),
+    amount INT NOT NULL CHECK (amount >= 0),
+    reason TEXT NOT NULL,
+    operator VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membuat tabel transaction_reversal_items untuk barang yang dikembalikan ke stok.
+CREATE TABLE IF NOT EXISTS transaction_reversal_items (
+    id SERIAL PRIMARY KEY,
+    reversal_id INT NOT NULL REFERENCES transaction_reversals(id) ON DELETE CASCADE,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES produk(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    amount INT NOT NULL CHECK (amount >= 0)
+);
+
+-- Index untuk mengambil reversal per transaksi, per detail, dan untuk laporan per tanggal.
+CREATE INDEX IF NOT EXISTS idx_transaction_reversals_transaction_id ON transaction_reversals(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_reversals_created_at ON transaction_reversals(created_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_reversal_items_detail_id ON transaction_reversal_items(transaction_detail_id);
@@ -0,0 +1,7 @@
+-- Drop trigger dan function append-only.
+DROP TRIGGER IF EXISTS trg_stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS stock_movements_append_only();
+
+-- Drop index dan tabel stock_movements.
+DROP INDEX IF EXISTS idx_stock_movements_product_id;
+DROP TABLE IF EXISTS stock_movements;
@@ -0,0 +1,42 @@
+-- Membuat tabel stock_movements sebagai ledger append-only untuk setiap perubahan stok.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    -- Produk yang dihapus tidak menghapus riwayatnya; product_id menjadi NULL
+    -- dan baris tetap bisa dikenali dari snapshot product_name.
+    product_id INT REFERENCES produk(id) ON DELETE SET NULL,
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    delta INT NOT NULL CHECK (delta <> 0),
+    stok_after INT NOT NULL,
+    reason VARCHAR(20) NOT NULL
+        CHECK (reason IN ('sale', 'void', 'refund', 'adjustment', 'purchase', 'opname')),
+    reference_id INT,
+    operator VARCHAR(100) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Index untuk riwayat stok per produk.
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, id);
+
+-- Ledger tidak boleh diubah atau dihapus; koreksi dilakukan dengan menambah
+-- baris baru. Satu-satunya UPDATE yang diizinkan adalah ON DELETE SET NULL
+-- saat produknya dihapus, tanpa mengubah kolom lain.
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.product_id IS NOT NULL AND NEW.product_id IS NULL
+        AND (to_jsonb(NEW) - 'product_id') = (to_jsonb(OLD) - 'product_id') THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+-- Catat stok yang sudah ada sebagai saldo awal agar riwayat setiap produk lengkap.
+INSERT INTO stock_movements (product_id, product_name, delta, stok_after, reason, note)
+SELECT id, nama, stok, stok, 'adjustment', 'saldo awal ledger'
+FROM produk
+WHERE stok <> 0;
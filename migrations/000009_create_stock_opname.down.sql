@@ -0,0 +1,5 @@
+-- Drop tabel stock opname.
+DROP TABLE IF EXISTS stock_opname_counts;
+DROP TABLE IF EXISTS stock_opname_items;
+DROP INDEX IF EXISTS uq_stock_opname_open;
+DROP TABLE IF EXISTS stock_opname;
@@ -0,0 +1,45 @@
+-- Membuat tabel stock_opname untuk sesi penghitungan stok fisik.
+CREATE TABLE IF NOT EXISTS stock_opname (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'finalized', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    opened_by VARCHAR(100) NOT NULL DEFAULT '',
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP
+);
+
+-- Hanya boleh ada satu sesi opname yang terbuka.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stock_opname_open ON stock_opname(status) WHERE status = 'open';
+
+-- Snapshot stok setiap produk saat sesi dibuka. snapshot_movement_id adalah posisi
+-- ledger stock_movements saat snapshot, dipakai untuk menghitung penjualan selama sesi.
+-- Kolom hasil (counted_quantity sampai variance) dibekukan saat finalisasi.
+-- Produk yang dihapus tidak menghapus riwayat opname; product_id menjadi NULL
+-- dan baris tetap bisa dikenali dari snapshot product_name.
+CREATE TABLE IF NOT EXISTS stock_opname_items (
+    id SERIAL PRIMARY KEY,
+    opname_id INT NOT NULL REFERENCES stock_opname(id) ON DELETE CASCADE,
+    product_id INT REFERENCES produk(id) ON DELETE SET NULL,
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    snapshot_stok INT NOT NULL,
+    snapshot_movement_id INT NOT NULL DEFAULT 0,
+    counted_quantity INT,
+    sold_during_session INT,
+    moved_after_count INT,
+    system_stok INT,
+    variance INT,
+    UNIQUE (opname_id, product_id)
+);
+
+-- Hasil hitung per penghitung. last_movement_id adalah posisi ledger saat barang
+-- dihitung, sehingga pergerakan stok setelahnya bisa diperhitungkan.
+CREATE TABLE IF NOT EXISTS stock_opname_counts (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES stock_opname_items(id) ON DELETE CASCADE,
+    counter VARCHAR(100) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    last_movement_id INT NOT NULL DEFAULT 0,
+    counted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, counter)
+);
@@ -0,0 +1,14 @@
+-- Drop index purchase order terlebih dahulu.
+DROP INDEX IF EXISTS idx_purchase_receipts_po_id;
+DROP INDEX IF EXISTS idx_purchase_order_items_po_id;
+DROP INDEX IF EXISTS idx_purchase_orders_status;
+
+-- Drop tabel penerimaan, purchase order, dan supplier.
+DROP TABLE IF EXISTS purchase_receipt_items;
+DROP TABLE IF EXISTS purchase_receipts;
+DROP TABLE IF EXISTS purchase_order_items;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS supplier;
+
+-- Hapus kolom harga beli dari produk.
+ALTER TABLE produk DROP COLUMN IF EXISTS harga_beli;
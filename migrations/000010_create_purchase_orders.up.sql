@@ -0,0 +1,60 @@
+-- Menambahkan harga beli terakhir (HPP) ke produk, diperbarui setiap penerimaan barang.
+ALTER TABLE produk ADD COLUMN harga_beli INT NOT NULL DEFAULT 0 CHECK (harga_beli >= 0);
+
+-- Membuat tabel supplier.
+CREATE TABLE IF NOT EXISTS supplier (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(255) NOT NULL,
+    kontak VARCHAR(100) NOT NULL DEFAULT '',
+    alamat TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membuat tabel purchase_orders untuk pesanan pembelian ke supplier.
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES supplier(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'approved', 'partially_received', 'received', 'cancelled')),
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    approved_by VARCHAR(100) NOT NULL DEFAULT '',
+    approved_at TIMESTAMP,
+    cancelled_by VARCHAR(100) NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membuat tabel purchase_order_items untuk barang yang dipesan beserta harga belinya.
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES produk(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    cost_price INT NOT NULL CHECK (cost_price >= 0),
+    received_quantity INT NOT NULL DEFAULT 0 CHECK (received_quantity >= 0 AND received_quantity <= quantity)
+);
+
+-- Membuat tabel purchase_receipts untuk setiap penerimaan barang (bisa sebagian).
+CREATE TABLE IF NOT EXISTS purchase_receipts (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    received_by VARCHAR(100) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membuat tabel purchase_receipt_items untuk jumlah yang diterima per item PO.
+CREATE TABLE IF NOT EXISTS purchase_receipt_items (
+    id SERIAL PRIMARY KEY,
+    receipt_id INT NOT NULL REFERENCES purchase_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id INT NOT NULL REFERENCES purchase_order_items(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES produk(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    cost_price INT NOT NULL CHECK (cost_price >= 0)
+);
+
+-- Index untuk daftar PO per status dan pengambilan item/penerimaan per PO.
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_items_po_id ON purchase_order_items(purchase_order_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_receipts_po_id ON purchase_receipts(purchase_order_id);
@@ -0,0 +1,3 @@
+-- Hapus snapshot harga dari transaction_details.
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS cost_price;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS unit_price;
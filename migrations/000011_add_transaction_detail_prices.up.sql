@@ -0,0 +1,11 @@
+-- Menyimpan harga jual dan harga beli per unit pada saat transaksi, sehingga
+-- perubahan harga produk setelahnya tidak mengubah laba historis.
+ALTER TABLE transaction_details ADD COLUMN unit_price INT;
+ALTER TABLE transaction_details ADD COLUMN cost_price INT NOT NULL DEFAULT 0 CHECK (cost_price >= 0);
+
+-- Isi data lama: harga jual diturunkan dari subtotal, harga beli memakai harga beli produk saat ini.
+UPDATE transaction_details SET unit_price = subtotal / NULLIF(quantity, 0);
+UPDATE transaction_details SET unit_price = 0 WHERE unit_price IS NULL;
+UPDATE transaction_details td SET cost_price = p.harga_beli FROM produk p WHERE p.id = td.product_id;
+
+ALTER TABLE transaction_details ALTER COLUMN unit_price SET NOT NULL;
@@ -0,0 +1,26 @@
+-- Riwayat penjualan produk yang sudah dihapus tidak bisa memenuhi constraint
+-- lama. Rollback dihentikan daripada menghapus data keuangan tersebut.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transaction_details WHERE product_id IS NULL)
+        OR EXISTS (SELECT 1 FROM transaction_reversal_items WHERE product_id IS NULL) THEN
+        RAISE EXCEPTION 'cannot roll back 000012: transaction details reference deleted products';
+    END IF;
+END;
+$$;
+
+-- Kembalikan foreign key produk tanpa ON DELETE SET NULL.
+ALTER TABLE transaction_reversal_items DROP CONSTRAINT IF EXISTS transaction_reversal_items_product_id_fkey;
+ALTER TABLE transaction_reversal_items ADD CONSTRAINT transaction_reversal_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES produk(id);
+ALTER TABLE transaction_reversal_items ALTER COLUMN product_id SET NOT NULL;
+
+ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES produk(id);
+ALTER TABLE transaction_details ALTER COLUMN product_id SET NOT NULL;
+
+-- Hapus snapshot nama produk dan kategori.
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS kategori_nama;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS kategori_id;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS product_name;
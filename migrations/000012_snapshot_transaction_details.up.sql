@@ -0,0 +1,24 @@
+-- Menyimpan nama produk dan kategori pada saat transaksi, sehingga struk lama
+-- tetap tampil seperti saat dijual walaupun produk diubah atau dihapus.
+ALTER TABLE transaction_details ADD COLUMN product_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE transaction_details ADD COLUMN kategori_id INT;
+ALTER TABLE transaction_details ADD COLUMN kategori_nama VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Isi data lama dari produk dan kategori saat ini.
+UPDATE transaction_details td
+SET product_name = p.nama, kategori_id = p.kategori_id, kategori_nama = COALESCE(k.nama, '')
+FROM produk p
+LEFT JOIN kategori k ON k.id = p.kategori_id
+WHERE p.id = td.product_id;
+
+-- Produk yang dihapus tidak lagi menghapus atau memblokir riwayat penjualan;
+-- product_id menjadi NULL dan detail tetap memakai snapshot di atas.
+ALTER TABLE transaction_details ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES produk(id) ON DELETE SET NULL;
+
+ALTER TABLE transaction_reversal_items ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE transaction_reversal_items DROP CONSTRAINT IF EXISTS transaction_reversal_items_product_id_fkey;
+ALTER TABLE transaction_reversal_items ADD CONSTRAINT transaction_reversal_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES produk(id) ON DELETE SET NULL;
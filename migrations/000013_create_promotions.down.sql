@@ -0,0 +1,13 @@
+-- Drop catatan promosi transaksi.
+DROP INDEX IF EXISTS idx_transaction_promotions_transaction_id;
+DROP TABLE IF EXISTS transaction_promotions;
+
+-- Hapus kolom potongan dari detail dan transaksi.
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS cart_discount;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS discount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS cart_discount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS line_discount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS subtotal_amount;
+
+-- Drop tabel promotions.
+DROP TABLE IF EXISTS promotions;
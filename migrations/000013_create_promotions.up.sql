@@ -0,0 +1,42 @@
+-- Membuat tabel promotions untuk aturan diskon yang dievaluasi saat checkout.
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('discount', 'buy_x_get_y', 'min_spend')),
+    discount_type VARCHAR(20) NOT NULL DEFAULT '' CHECK (discount_type IN ('', 'percentage', 'fixed')),
+    value INT NOT NULL DEFAULT 0 CHECK (value >= 0),
+    product_id INT REFERENCES produk(id) ON DELETE CASCADE,
+    kategori_id INT REFERENCES kategori(id) ON DELETE CASCADE,
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    min_spend INT NOT NULL DEFAULT 0,
+    start_at TIMESTAMP,
+    end_at TIMESTAMP,
+    hour_start VARCHAR(5) NOT NULL DEFAULT '',
+    hour_end VARCHAR(5) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Menyimpan subtotal sebelum potongan dan total potongan pada transaksi.
+ALTER TABLE transactions ADD COLUMN subtotal_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN line_discount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN cart_discount INT NOT NULL DEFAULT 0;
+UPDATE transactions SET subtotal_amount = total_amount;
+
+-- Menyimpan potongan per baris; subtotal detail menjadi jumlah setelah potongan.
+ALTER TABLE transaction_details ADD COLUMN discount INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN cart_discount INT NOT NULL DEFAULT 0;
+
+-- Membuat tabel transaction_promotions untuk mencatat promosi yang diterapkan.
+-- transaction_detail_id NULL berarti promosi keranjang.
+CREATE TABLE IF NOT EXISTS transaction_promotions (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    transaction_detail_id INT REFERENCES transaction_details(id) ON DELETE CASCADE,
+    promotion_id INT REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name VARCHAR(255) NOT NULL,
+    amount INT NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_promotions_transaction_id ON transaction_promotions(transaction_id);
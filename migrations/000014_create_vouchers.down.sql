@@ -0,0 +1,8 @@
+-- Hapus kolom voucher dari transaksi.
+ALTER TABLE transactions DROP COLUMN IF EXISTS voucher_discount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS voucher_code;
+
+-- Drop tabel penukaran voucher dan voucher.
+DROP INDEX IF EXISTS idx_voucher_redemptions_voucher_customer;
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
@@ -0,0 +1,37 @@
+-- Membuat tabel vouchers untuk kode kupon yang bisa ditukar saat checkout.
+CREATE TABLE IF NOT EXISTS vouchers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percentage', 'fixed')),
+    value INT NOT NULL CHECK (value > 0),
+    max_discount INT NOT NULL DEFAULT 0 CHECK (max_discount >= 0),
+    min_spend INT NOT NULL DEFAULT 0 CHECK (min_spend >= 0),
+    start_at TIMESTAMP,
+    end_at TIMESTAMP,
+    usage_limit INT NOT NULL DEFAULT 0 CHECK (usage_limit >= 0),
+    per_customer_limit INT NOT NULL DEFAULT 0 CHECK (per_customer_limit >= 0),
+    used_count INT NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (usage_limit = 0 OR used_count <= usage_limit)
+);
+
+-- Membuat tabel voucher_redemptions untuk setiap penukaran voucher.
+-- customer_id diisi jika checkout menyebutkan pelanggan.
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id SERIAL PRIMARY KEY,
+    voucher_id INT NOT NULL REFERENCES vouchers(id),
+    transaction_id INT NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    customer_id INT,
+    amount INT NOT NULL CHECK (amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'redeemed' CHECK (status IN ('redeemed', 'released')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_customer ON voucher_redemptions(voucher_id, customer_id);
+
+-- Menyimpan kode dan potongan voucher pada transaksi.
+ALTER TABLE transactions ADD COLUMN voucher_code VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN voucher_discount INT NOT NULL DEFAULT 0;
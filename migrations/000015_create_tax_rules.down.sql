@@ -0,0 +1,16 @@
+-- Drop catatan pajak transaksi.
+DROP INDEX IF EXISTS idx_transaction_taxes_detail_id;
+DROP INDEX IF EXISTS idx_transaction_taxes_transaction_id;
+DROP TABLE IF EXISTS transaction_taxes;
+
+-- Hapus kolom pajak dari detail dan transaksi.
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS total;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax_included;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS service_charge;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tax_included;
+ALTER TABLE transactions DROP COLUMN IF EXISTS service_charge;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tax_amount;
+
+-- Drop tabel tax_rules.
+DROP TABLE IF EXISTS tax_rules;
@@ -0,0 +1,46 @@
+-- Membuat tabel tax_rules untuk PPN dan service charge yang dihitung saat checkout.
+-- Aturan bisa berlaku untuk satu produk, satu kategori, atau semua produk.
+CREATE TABLE IF NOT EXISTS tax_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('tax', 'service')),
+    rate NUMERIC(5, 2) NOT NULL CHECK (rate > 0 AND rate <= 100),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    product_id INT REFERENCES produk(id) ON DELETE CASCADE,
+    kategori_id INT REFERENCES kategori(id) ON DELETE CASCADE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (product_id IS NULL OR kategori_id IS NULL)
+);
+
+-- Menyimpan total pajak dan service charge pada transaksi. tax_included adalah
+-- bagian pajak/service yang sudah termasuk dalam harga jual.
+ALTER TABLE transactions ADD COLUMN tax_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN service_charge INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN tax_included INT NOT NULL DEFAULT 0;
+
+-- Menyimpan pajak per baris; total adalah jumlah yang dibayar untuk baris tersebut.
+ALTER TABLE transaction_details ADD COLUMN tax INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN service_charge INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN tax_included INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN total INT NOT NULL DEFAULT 0;
+UPDATE transaction_details SET total = subtotal;
+
+-- Membuat tabel transaction_taxes untuk mencatat setiap aturan pajak yang
+-- diterapkan per baris. Nama, tarif, dan sifat inklusif disalin agar laporan
+-- pajak tidak berubah ketika aturannya diubah.
+CREATE TABLE IF NOT EXISTS transaction_taxes (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    tax_rule_id INT REFERENCES tax_rules(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    rate NUMERIC(5, 2) NOT NULL,
+    inclusive BOOLEAN NOT NULL,
+    base INT NOT NULL,
+    amount INT NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_taxes_transaction_id ON transaction_taxes(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_taxes_detail_id ON transaction_taxes(transaction_detail_id);
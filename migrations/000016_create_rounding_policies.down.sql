@@ -0,0 +1,5 @@
+-- Hapus kolom pembulatan dari transaksi.
+ALTER TABLE transactions DROP COLUMN IF EXISTS rounding_adjustment;
+
+-- Drop tabel rounding_policies.
+DROP TABLE IF EXISTS rounding_policies;
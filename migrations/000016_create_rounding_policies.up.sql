@@ -0,0 +1,11 @@
+-- Membuat tabel rounding_policies untuk pembulatan grand total per metode pembayaran.
+CREATE TABLE IF NOT EXISTS rounding_policies (
+    method VARCHAR(20) PRIMARY KEY,
+    mode VARCHAR(10) NOT NULL CHECK (mode IN ('nearest', 'up', 'down')),
+    unit INT NOT NULL CHECK (unit > 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Menyimpan selisih pembulatan sebagai baris tersendiri pada transaksi.
+-- total_amount sudah termasuk rounding_adjustment.
+ALTER TABLE transactions ADD COLUMN rounding_adjustment INT NOT NULL DEFAULT 0;
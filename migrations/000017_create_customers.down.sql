@@ -0,0 +1,9 @@
+-- Lepas relasi pelanggan dari penukaran voucher dan transaksi.
+ALTER TABLE voucher_redemptions DROP CONSTRAINT IF EXISTS voucher_redemptions_customer_id_fkey;
+DROP INDEX IF EXISTS idx_transactions_customer_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS customer_id;
+
+-- Drop tabel customers.
+DROP INDEX IF EXISTS idx_customers_nomor_member;
+DROP INDEX IF EXISTS idx_customers_telepon;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,23 @@
+-- Membuat tabel customers untuk data pelanggan. Telepon disimpan dalam format
+-- angka saja (awalan 62 diganti 0) agar pencarian tidak bergantung format ketik.
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(255) NOT NULL,
+    telepon VARCHAR(20) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    nomor_member VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Telepon dan nomor member unik jika diisi.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_telepon ON customers(telepon) WHERE telepon <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_nomor_member ON customers(nomor_member) WHERE nomor_member <> '';
+
+-- Menghubungkan transaksi dengan pelanggan (opsional).
+ALTER TABLE transactions ADD COLUMN customer_id INT REFERENCES customers(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_customer_id ON transactions(customer_id);
+
+-- Penukaran voucher per pelanggan sekarang merujuk ke tabel customers.
+ALTER TABLE voucher_redemptions
+    ADD CONSTRAINT voucher_redemptions_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL;
@@ -0,0 +1,32 @@
+-- Pembayaran dengan poin tidak bisa memenuhi constraint lama. Rollback
+-- dihentikan daripada menghapus data keuangan tersebut.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transaction_payments WHERE method = 'points') THEN
+        RAISE EXCEPTION 'cannot roll back 000018: transaction payments use loyalty points';
+    END IF;
+END;
+$$;
+
+-- Kembalikan daftar metode pembayaran sebelum ada poin.
+ALTER TABLE transaction_payments DROP CONSTRAINT IF EXISTS transaction_payments_method_check;
+ALTER TABLE transaction_payments ADD CONSTRAINT transaction_payments_method_check
+    CHECK (method IN ('cash', 'debit', 'qris', 'e-wallet'));
+
+-- Hapus kolom poin dari reversal, detail, dan transaksi.
+ALTER TABLE transaction_reversals DROP COLUMN IF EXISTS points_amount;
+ALTER TABLE transaction_reversals DROP COLUMN IF EXISTS points_restored;
+ALTER TABLE transaction_reversals DROP COLUMN IF EXISTS points_reversed;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS points_earned;
+ALTER TABLE transactions DROP COLUMN IF EXISTS points_redeemed;
+ALTER TABLE transactions DROP COLUMN IF EXISTS points_earned;
+
+-- Drop ledger poin dan saldo pelanggan.
+DROP INDEX IF EXISTS idx_loyalty_points_open_lots;
+DROP INDEX IF EXISTS idx_loyalty_points_customer_id;
+DROP TABLE IF EXISTS loyalty_points;
+ALTER TABLE customers DROP COLUMN IF EXISTS points_balance;
+
+-- Drop tabel pengaturan poin.
+DROP TABLE IF EXISTS loyalty_multipliers;
+DROP TABLE IF EXISTS loyalty_settings;
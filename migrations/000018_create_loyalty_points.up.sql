@@ -0,0 +1,55 @@
+-- Membuat tabel loyalty_settings (satu baris) untuk aturan program poin.
+-- spend_per_point 0 menonaktifkan perolehan poin, point_value 0 menonaktifkan
+-- penukaran poin, dan expiry_days 0 berarti poin tidak kedaluwarsa.
+CREATE TABLE IF NOT EXISTS loyalty_settings (
+    id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    spend_per_point INT NOT NULL DEFAULT 0 CHECK (spend_per_point >= 0),
+    point_value INT NOT NULL DEFAULT 0 CHECK (point_value >= 0),
+    expiry_days INT NOT NULL DEFAULT 0 CHECK (expiry_days >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO loyalty_settings (id) VALUES (1) ON CONFLICT (id) DO NOTHING;
+
+-- Membuat tabel loyalty_multipliers untuk pengali poin per kategori.
+-- Kategori tanpa pengali memakai pengali 1.
+CREATE TABLE IF NOT EXISTS loyalty_multipliers (
+    kategori_id INT PRIMARY KEY REFERENCES kategori(id) ON DELETE CASCADE,
+    multiplier NUMERIC(5, 2) NOT NULL CHECK (multiplier >= 0 AND multiplier <= 100),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Saldo poin disimpan di pelanggan dan selalu diubah bersama ledger dalam
+-- database transaction yang sama.
+ALTER TABLE customers ADD COLUMN points_balance INT NOT NULL DEFAULT 0;
+
+-- Membuat tabel loyalty_points sebagai ledger poin. Entri earn dan restore
+-- adalah lot poin; remaining adalah sisa lot yang belum ditukar, dibalik, atau
+-- kedaluwarsa, dipakai berurutan dari yang paling cepat kedaluwarsa.
+CREATE TABLE IF NOT EXISTS loyalty_points (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('earn', 'redeem', 'reverse', 'restore', 'expire')),
+    points INT NOT NULL,
+    remaining INT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_points_customer_id ON loyalty_points(customer_id);
+CREATE INDEX IF NOT EXISTS idx_loyalty_points_open_lots ON loyalty_points(customer_id, expires_at) WHERE remaining > 0;
+
+-- Poin yang diperoleh dan ditukar per transaksi serta per baris, agar refund
+-- bisa membalik poin sebanding dengan barang yang dikembalikan.
+ALTER TABLE transactions ADD COLUMN points_earned INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN points_redeemed INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN points_earned INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_reversals ADD COLUMN points_reversed INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_reversals ADD COLUMN points_restored INT NOT NULL DEFAULT 0;
+-- Bagian amount reversal yang dikembalikan sebagai poin, bukan uang dari laci.
+ALTER TABLE transaction_reversals ADD COLUMN points_amount INT NOT NULL DEFAULT 0;
+
+-- Poin bisa dipakai sebagai metode pembayaran.
+ALTER TABLE transaction_payments DROP CONSTRAINT IF EXISTS transaction_payments_method_check;
+ALTER TABLE transaction_payments ADD CONSTRAINT transaction_payments_method_check
+    CHECK (method IN ('cash', 'debit', 'qris', 'e-wallet', 'points'));
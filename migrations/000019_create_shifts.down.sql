@@ -0,0 +1,10 @@
+-- Lepas relasi shift dari void/refund dan transaksi.
+DROP INDEX IF EXISTS idx_transaction_reversals_shift_id;
+ALTER TABLE transaction_reversals DROP COLUMN IF EXISTS shift_id;
+DROP INDEX IF EXISTS idx_transactions_shift_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS shift_id;
+
+-- Drop tabel shift.
+DROP TABLE IF EXISTS shift_counts;
+DROP INDEX IF EXISTS uq_shifts_open_register;
+DROP TABLE IF EXISTS shifts;
@@ -0,0 +1,32 @@
+-- Membuat tabel shifts untuk sesi kerja kasir per register. Hanya boleh ada
+-- satu shift terbuka untuk setiap register.
+CREATE TABLE IF NOT EXISTS shifts (
+    id SERIAL PRIMARY KEY,
+    register VARCHAR(50) NOT NULL,
+    cashier VARCHAR(100) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float INT NOT NULL DEFAULT 0 CHECK (opening_float >= 0),
+    expected_cash INT NOT NULL DEFAULT 0,
+    counted_cash INT NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    opened_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_by VARCHAR(100) NOT NULL DEFAULT '',
+    closed_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shifts_open_register ON shifts(register) WHERE status = 'open';
+
+-- Hasil hitung per metode pembayaran saat shift ditutup (termasuk tunai).
+CREATE TABLE IF NOT EXISTS shift_counts (
+    shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount INT NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (shift_id, method)
+);
+
+-- Menghubungkan transaksi dan void/refund dengan shift yang menerima atau
+-- mengeluarkan uangnya.
+ALTER TABLE transactions ADD COLUMN shift_id INT REFERENCES shifts(id);
+CREATE INDEX IF NOT EXISTS idx_transactions_shift_id ON transactions(shift_id);
+ALTER TABLE transaction_reversals ADD COLUMN shift_id INT REFERENCES shifts(id);
+CREATE INDEX IF NOT EXISTS idx_transaction_reversals_shift_id ON transaction_reversals(shift_id);
@@ -0,0 +1,3 @@
+-- Drop tabel shift_cash_movements.
+DROP INDEX IF EXISTS idx_shift_cash_movements_shift_id;
+DROP TABLE IF EXISTS shift_cash_movements;
@@ -0,0 +1,13 @@
+-- Membuat tabel shift_cash_movements untuk uang tunai yang masuk (pay-in) atau
+-- keluar (pay-out) laci di luar penjualan, misalnya beli es atau bayar parkir.
+CREATE TABLE IF NOT EXISTS shift_cash_movements (
+    id SERIAL PRIMARY KEY,
+    shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('pay_in', 'pay_out')),
+    amount INT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    operator VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_cash_movements_shift_id ON shift_cash_movements(shift_id);
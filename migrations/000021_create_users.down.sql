@@ -0,0 +1,4 @@
+-- Drop tabel sesi dan pengguna.
+DROP INDEX IF EXISTS idx_user_sessions_user_id;
+DROP TABLE IF EXISTS user_sessions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,9 @@
+-- Drop kolom dan tabel persetujuan manajer.
+ALTER TABLE transactions DROP COLUMN IF EXISTS manual_discount;
+DROP INDEX IF EXISTS idx_shift_no_sales_shift_id;
+DROP TABLE IF EXISTS shift_no_sales;
+DROP INDEX IF EXISTS idx_approvals_action_entity;
+DROP TABLE IF EXISTS approvals;
+DROP TABLE IF EXISTS approval_pin_failures;
+DROP TABLE IF EXISTS approval_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS pin_hash;
@@ -0,0 +1,54 @@
+-- Menambah PIN manajer pada users. PIN disimpan sebagai hash PBKDF2 seperti
+-- password dan hanya dipakai untuk menyetujui aksi kasir.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_hash TEXT;
+
+-- Membuat tabel approval_tokens untuk token persetujuan sekali pakai yang
+-- dibuat manajer. Hanya hash SHA-256 dari token yang disimpan.
+CREATE TABLE IF NOT EXISTS approval_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    manager_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Membuat tabel approval_pin_failures untuk menghitung PIN manajer yang salah.
+-- subject berisi manager:<username> atau caller:<pengguna yang mencoba>;
+-- subject yang terlalu sering gagal dikunci sampai locked_until.
+CREATE TABLE IF NOT EXISTS approval_pin_failures (
+    subject VARCHAR(120) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP
+);
+
+-- Membuat tabel approvals untuk mencatat manajer yang menyetujui aksi kasir.
+-- entity_id tidak memakai foreign key karena bisa merujuk transaksi, produk
+-- yang sudah dihapus, atau catatan buka laci.
+CREATE TABLE IF NOT EXISTS approvals (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(30) NOT NULL,
+    entity_id INT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    requested_by VARCHAR(100) NOT NULL,
+    approved_by INT REFERENCES users(id) ON DELETE SET NULL,
+    approver_name VARCHAR(50) NOT NULL,
+    method VARCHAR(10) NOT NULL CHECK (method IN ('role', 'pin', 'token')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_action_entity ON approvals(action, entity_id);
+
+-- Membuat tabel shift_no_sales untuk laci yang dibuka tanpa penjualan.
+CREATE TABLE IF NOT EXISTS shift_no_sales (
+    id SERIAL PRIMARY KEY,
+    shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    operator VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_no_sales_shift_id ON shift_no_sales(shift_id);
+
+-- Potongan manual kasir untuk seluruh keranjang.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS manual_discount INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Drop tabel api_keys.
+DROP TABLE IF EXISTS api_keys;
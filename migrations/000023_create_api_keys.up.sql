@@ -0,0 +1,17 @@
+-- Membuat tabel api_keys untuk terminal POS dan integrasi yang memanggil API
+-- tanpa login pengguna. Hanya hash SHA-256 dari kunci yang disimpan.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    -- Role yang dipakai untuk cek hak akses route, sama seperti role pengguna.
+    role VARCHAR(20) NOT NULL DEFAULT 'cashier' CHECK (role IN ('owner', 'manager', 'cashier', 'stock_clerk')),
+    outlet VARCHAR(50) NOT NULL DEFAULT '',
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Drop tabel checkout_idempotency_keys.
+DROP TABLE IF EXISTS checkout_idempotency_keys;
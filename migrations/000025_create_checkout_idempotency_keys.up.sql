@@ -0,0 +1,14 @@
+-- Membuat tabel checkout_idempotency_keys untuk header Idempotency-Key pada
+-- checkout. Key diklaim dengan status processing sebelum checkout berjalan dan
+-- ditandai completed bersama transaksi yang dibuat. Key berlaku per scope,
+-- yaitu pengguna atau kunci API yang mengirim request.
+CREATE TABLE IF NOT EXISTS checkout_idempotency_keys (
+    scope VARCHAR(64) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'completed')),
+    transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
+    claimed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
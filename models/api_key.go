@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// APIKey merepresentasikan kunci API untuk terminal POS atau integrasi tanpa
+// login pengguna. Database hanya menyimpan hash kunci; kunci utuh hanya
+// dikirim sekali saat dibuat atau dirotasi.
+type APIKey struct {
+	ID         int        `json:"id"`                     // ID unik untuk kunci.
+	Name       string     `json:"name"`                   // Nama kunci, misalnya "tablet-kasir-1".
+	Prefix     string     `json:"prefix"`                 // Awalan kunci untuk mengenali kunci tanpa membuka rahasianya.
+	Key        string     `json:"key,omitempty"`          // Kunci utuh, hanya diisi saat dibuat atau dirotasi.
+	Scopes     []string   `json:"scopes"`                 // Hak akses, misalnya checkout:write atau produk:read.
+	Role       string     `json:"role"`                   // Role yang dicek terhadap route, sama seperti role pengguna.
+	Outlet     string     `json:"outlet,omitempty"`       // Kode register yang terikat dengan kunci (kosong berarti bebas).
+	CreatedBy  string     `json:"created_by"`             // Pengguna yang membuat kunci.
+	CreatedAt  time.Time  `json:"created_at"`             // Waktu kunci dibuat.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // Waktu kunci terakhir dipakai.
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`   // Waktu kunci terakhir dirotasi.
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`   // Waktu kunci dicabut.
+}
+
+// CreateAPIKeyRequest merepresentasikan request body untuk membuat kunci API.
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name"`   // Nama kunci.
+	Scopes    []string `json:"scopes"` // Hak akses dalam format <resource>:<read|write>.
+	Role      string   `json:"role"`   // Role kunci (opsional, default cashier).
+	Outlet    string   `json:"outlet"` // Kode register yang terikat (opsional).
+	CreatedBy string   `json:"-"`      // Diisi handler dari pengguna yang login.
+}
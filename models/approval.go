@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// Aksi kasir yang membutuhkan persetujuan manajer. Token persetujuan selalu
+// terikat ke salah satu aksi ini.
+const (
+	ApprovalActionCheckout     = "checkout"      // Checkout dengan ubah harga atau diskon manual di atas batas.
+	ApprovalActionVoid         = "void"          // Void transaksi.
+	ApprovalActionNoSale       = "no_sale"       // Buka laci tanpa penjualan.
+	ApprovalActionProdukUpdate = "produk_update" // Ubah data produk.
+	ApprovalActionProdukDelete = "produk_delete" // Hapus produk.
+)
+
+// Cara manajer memberi persetujuan.
+const (
+	ApprovalMethodRole  = "role"  // Pengguna yang login sudah manajer/owner.
+	ApprovalMethodPIN   = "pin"   // PIN manajer dikirim bersama request.
+	ApprovalMethodToken = "token" // Token persetujuan sekali pakai dari manajer.
+)
+
+// Approval merepresentasikan persetujuan manajer yang tercatat bersama aksi
+// yang disetujuinya.
+type Approval struct {
+	ID           int       `json:"id"`               // ID unik untuk persetujuan.
+	Action       string    `json:"action"`           // Aksi yang disetujui.
+	EntityID     int       `json:"entity_id"`        // ID data hasil aksi (transaksi, produk, dll).
+	Detail       string    `json:"detail,omitempty"` // Alasan persetujuan dibutuhkan, misalnya price_override.
+	RequestedBy  string    `json:"requested_by"`     // Pengguna yang melakukan aksi.
+	ApprovedBy   int       `json:"approved_by"`      // ID manajer yang menyetujui.
+	ApproverName string    `json:"approver_name"`    // Username manajer saat menyetujui.
+	Method       string    `json:"method"`           // Cara persetujuan (role, pin, token).
+	CreatedAt    time.Time `json:"created_at"`       // Waktu aksi dicatat.
+}
+
+// ApprovalTokenRequest merepresentasikan request body manajer untuk membuat token persetujuan.
+type ApprovalTokenRequest struct {
+	Action string `json:"action"` // Aksi yang akan disetujui.
+}
+
+// ApprovalToken adalah token persetujuan sekali pakai yang berlaku singkat.
+// Token hanya dikirim sekali; database hanya menyimpan hash-nya.
+type ApprovalToken struct {
+	Token     string    `json:"token"`      // Token untuk header X-Approval-Token.
+	Action    string    `json:"action"`     // Aksi yang boleh disetujui token ini.
+	ExpiresAt time.Time `json:"expires_at"` // Waktu token kedaluwarsa.
+}
+
+// ManagerPINFailure adalah isi audit log untuk PIN manajer yang ditolak.
+type ManagerPINFailure struct {
+	Caller   string `json:"caller"`             // Pengguna yang mengirim PIN.
+	Failures int    `json:"failures,omitempty"` // Jumlah gagal beruntun untuk manajer tersebut.
+	Locked   bool   `json:"locked"`             // PIN sedang dikunci atau baru dikunci oleh percobaan ini.
+}
+
+// NoSaleRequest merepresentasikan request body untuk membuka laci tanpa penjualan.
+type NoSaleRequest struct {
+	Register string `json:"register"` // Register yang lacinya dibuka; harus punya shift yang terbuka.
+	Reason   string `json:"reason"`   // Alasan membuka laci.
+	Operator string `json:"operator"` // Petugas yang membuka laci.
+}
+
+// NoSale merepresentasikan catatan laci dibuka tanpa penjualan.
+type NoSale struct {
+	ID        int       `json:"id"`         // ID unik untuk catatan.
+	ShiftID   int       `json:"shift_id"`   // Shift yang lacinya dibuka.
+	Reason    string    `json:"reason"`     // Alasan membuka laci.
+	Operator  string    `json:"operator"`   // Pengguna yang membuka laci.
+	CreatedAt time.Time `json:"created_at"` // Waktu laci dibuka.
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis perubahan yang dicatat di audit log.
+const (
+	AuditActionCreate = "create" // Data baru dibuat.
+	AuditActionUpdate = "update" // Data yang ada diubah.
+	AuditActionDelete = "delete" // Data dihapus.
+	AuditActionFailed = "failed" // Percobaan yang ditolak, misalnya PIN manajer salah.
+)
+
+// Entitas master data yang perubahannya dicatat di audit log.
+const (
+	AuditEntityProduk            = "produk"
+	AuditEntityKategori          = "kategori"
+	AuditEntityCustomer          = "customer"
+	AuditEntityPromotion         = "promotion"
+	AuditEntityVoucher           = "voucher"
+	AuditEntityTaxRule           = "tax_rule"
+	AuditEntityRoundingPolicy    = "rounding_policy"
+	AuditEntityLoyaltySettings   = "loyalty_settings"
+	AuditEntityLoyaltyMultiplier = "loyalty_multiplier"
+	AuditEntitySupplier          = "supplier"
+	AuditEntityPurchaseOrder     = "purchase_order"
+	AuditEntityUser              = "user"
+	AuditEntityAPIKey            = "api_key"
+	AuditEntityManagerPIN        = "manager_pin"
+)
+
+// AuditEntry merepresentasikan satu perubahan master data beserta pelakunya
+// dan isi data sebelum dan sesudah perubahan.
+type AuditEntry struct {
+	ID        int             `json:"id"`               // ID unik untuk catatan audit.
+	Actor     string          `json:"actor"`            // Pengguna atau kunci API yang melakukan perubahan.
+	Action    string          `json:"action"`           // Jenis perubahan (create, update, delete).
+	Entity    string          `json:"entity"`           // Entitas yang diubah, misalnya produk.
+	EntityID  string          `json:"entity_id"`        // ID data yang diubah.
+	Before    json.RawMessage `json:"before,omitempty"` // Data sebelum perubahan, kosong untuk create.
+	After     json.RawMessage `json:"after,omitempty"`  // Data sesudah perubahan, kosong untuk delete.
+	RequestID string          `json:"request_id"`       // ID request HTTP yang melakukan perubahan.
+	CreatedAt time.Time       `json:"created_at"`       // Waktu perubahan dicatat.
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Customer merepresentasikan pelanggan yang bisa dikaitkan dengan transaksi.
+type Customer struct {
+	ID            int       `json:"id"`             // ID unik untuk pelanggan.
+	Nama          string    `json:"nama"`           // Nama pelanggan.
+	Telepon       string    `json:"telepon"`        // Nomor telepon (disimpan angka saja, awalan 0).
+	Email         string    `json:"email"`          // Email pelanggan.
+	NomorMember   string    `json:"nomor_member"`   // Nomor kartu member.
+	PointsBalance int       `json:"points_balance"` // Saldo poin loyalitas (hanya dibaca, diubah lewat transaksi).
+	CreatedAt     time.Time `json:"created_at"`     // Waktu pelanggan didaftarkan.
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Jenis entri ledger poin.
+const (
+	PointsTypeEarn    = "earn"    // Poin diperoleh dari transaksi.
+	PointsTypeRedeem  = "redeem"  // Poin ditukar sebagai pembayaran.
+	PointsTypeReverse = "reverse" // Poin perolehan dibalik karena void/refund.
+	PointsTypeRestore = "restore" // Poin yang ditukar dikembalikan karena void.
+	PointsTypeExpire  = "expire"  // Poin hangus karena melewati masa berlaku.
+)
+
+// LoyaltySettings mengatur perolehan dan penukaran poin pelanggan.
+type LoyaltySettings struct {
+	SpendPerPoint int       `json:"spend_per_point"` // Belanja (rupiah) untuk 1 poin; 0 menonaktifkan perolehan poin.
+	PointValue    int       `json:"point_value"`     // Nilai rupiah 1 poin saat ditukar; 0 menonaktifkan penukaran.
+	ExpiryDays    int       `json:"expiry_days"`     // Masa berlaku poin sejak diperoleh; 0 berarti tidak kedaluwarsa.
+	UpdatedAt     time.Time `json:"updated_at"`      // Waktu pengaturan terakhir diubah.
+}
+
+// LoyaltyMultiplier adalah pengali poin untuk satu kategori. Kategori tanpa
+// pengali memakai pengali 1, dan pengali 0 berarti kategori tidak memberi poin.
+type LoyaltyMultiplier struct {
+	KategoriID int       `json:"kategori_id"` // ID kategori.
+	Multiplier float64   `json:"multiplier"`  // Pengali poin, misalnya 2 untuk poin ganda.
+	UpdatedAt  time.Time `json:"updated_at"`  // Waktu pengali terakhir diubah.
+}
+
+// PointsEntry adalah satu baris ledger poin pelanggan. Points bernilai positif
+// untuk earn/restore dan negatif untuk redeem/reverse/expire.
+type PointsEntry struct {
+	ID            int        `json:"id"`                       // ID unik untuk entri.
+	CustomerID    int        `json:"customer_id"`              // ID pelanggan pemilik poin.
+	TransactionID int        `json:"transaction_id,omitempty"` // ID transaksi terkait (0 untuk poin hangus).
+	Type          string     `json:"type"`                     // Jenis entri (earn, redeem, reverse, restore, expire).
+	Points        int        `json:"points"`                   // Perubahan saldo poin.
+	Remaining     int        `json:"remaining"`                // Sisa poin lot earn/restore yang masih bisa dipakai.
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`     // Batas berlaku lot earn/restore (opsional).
+	CreatedAt     time.Time  `json:"created_at"`               // Waktu entri dibuat.
+}
+
+// PointsAccount merangkum saldo dan riwayat poin satu pelanggan.
+type PointsAccount struct {
+	CustomerID int           `json:"customer_id"` // ID pelanggan.
+	Balance    int           `json:"balance"`     // Saldo poin saat ini.
+	Entries    []PointsEntry `json:"entries"`     // Riwayat ledger, terbaru lebih dulu.
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Status sesi stock opname.
+const (
+	OpnameStatusOpen      = "open"
+	OpnameStatusFinalized = "finalized"
+	OpnameStatusCancelled = "cancelled"
+)
+
+// StockOpname merepresentasikan satu sesi penghitungan stok fisik.
+type StockOpname struct {
+	ID            int          `json:"id"`                  // ID unik untuk sesi opname.
+	Status        string       `json:"status"`              // Status sesi (open, finalized, cancelled).
+	Note          string       `json:"note"`                // Catatan sesi.
+	OpenedBy      string       `json:"opened_by"`           // Petugas yang membuka sesi.
+	StartedAt     time.Time    `json:"started_at"`          // Waktu snapshot stok diambil.
+	ClosedBy      string       `json:"closed_by,omitempty"` // Petugas yang memfinalisasi/membatalkan sesi.
+	ClosedAt      *time.Time   `json:"closed_at,omitempty"` // Waktu sesi difinalisasi/dibatalkan.
+	Lines         []OpnameLine `json:"lines,omitempty"`     // Laporan selisih per produk.
+	TotalVariance int          `json:"total_variance"`      // Jumlah selisih semua produk yang dihitung.
+}
+
+// OpnameLine merepresentasikan laporan selisih satu produk dalam sesi opname.
+//
+// Penjualan dan pergerakan stok lain yang terjadi setelah barang dihitung
+// ditambahkan ke hasil hitung (AdjustedCount), sehingga selisih hanya
+// mencerminkan barang yang benar-benar hilang atau berlebih.
+type OpnameLine struct {
+	ProductID         int    `json:"product_id"`          // ID produk (0 jika produk sudah dihapus).
+	ProductName       string `json:"product_name"`        // Nama produk (snapshot saat sesi dibuka jika produk sudah dihapus).
+	SnapshotStok      int    `json:"snapshot_stok"`       // Stok sistem saat sesi dibuka.
+	SoldDuringSession int    `json:"sold_during_session"` // Jumlah terjual sejak sesi dibuka.
+	Counted           bool   `json:"counted"`             // Apakah produk sudah dihitung.
+	CountedQuantity   int    `json:"counted_quantity"`    // Total hasil hitung dari semua penghitung.
+	MovedAfterCount   int    `json:"moved_after_count"`   // Pergerakan stok setelah produk dihitung.
+	AdjustedCount     int    `json:"adjusted_count"`      // Hasil hitung yang disesuaikan ke kondisi sekarang.
+	SystemStok        int    `json:"system_stok"`         // Stok sistem saat ini (atau saat finalisasi).
+	Variance          int    `json:"variance"`            // Selisih AdjustedCount - SystemStok.
+}
+
+// OpenOpnameRequest merepresentasikan request body untuk membuka sesi opname.
+type OpenOpnameRequest struct {
+	Note     string `json:"note"`     // Catatan sesi.
+	Operator string `json:"operator"` // Petugas yang membuka sesi.
+}
+
+// OpnameCountItem merepresentasikan hasil hitung satu produk.
+type OpnameCountItem struct {
+	ProductID int `json:"product_id"` // ID produk yang dihitung.
+	Quantity  int `json:"quantity"`   // Jumlah fisik yang ditemukan.
+}
+
+// OpnameCountRequest merepresentasikan satu batch hasil hitung dari seorang
+// penghitung. Hitungan ulang oleh penghitung yang sama untuk produk yang sama
+// menggantikan hitungan sebelumnya; hitungan dari penghitung berbeda dijumlahkan.
+type OpnameCountRequest struct {
+	Counter string            `json:"counter"` // Nama penghitung.
+	Items   []OpnameCountItem `json:"items"`   // Hasil hitung per produk.
+}
+
+// CloseOpnameRequest merepresentasikan request body untuk finalisasi atau pembatalan sesi.
+type CloseOpnameRequest struct {
+	Operator string `json:"operator"` // Petugas yang menutup sesi.
+}
@@ -3,9 +3,10 @@ package models
 
 // Produk merepresentasikan data produk pada sistem kasir.
 type Produk struct {
-	ID         int    `json:"id"`         // ID unik untuk produk.
-	Nama       string `json:"nama"`       // Nama produk yang tampil di API.
-	Harga      int    `json:"harga"`      // Harga produk dalam satuan rupiah.
-	Stok       int    `json:"stok"`       // Stok tersedia untuk produk ini.
+	ID         int    `json:"id"`          // ID unik untuk produk.
+	Nama       string `json:"nama"`        // Nama produk yang tampil di API.
+	Harga      int    `json:"harga"`       // Harga produk dalam satuan rupiah.
+	HargaBeli  int    `json:"harga_beli"`  // Harga beli terakhir dari supplier dalam satuan rupiah.
+	Stok       int    `json:"stok"`        // Stok tersedia untuk produk ini.
 	KategoriID int    `json:"kategori_id"` // ID kategori produk, foreign key ke tabel kategori.
 }
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Jenis promosi.
+const (
+	PromotionTypeDiscount = "discount"    // Potongan harga per baris untuk produk/kategori/semua produk.
+	PromotionTypeBuyXGetY = "buy_x_get_y" // Beli X gratis Y untuk produk/kategori/semua produk.
+	PromotionTypeMinSpend = "min_spend"   // Potongan keranjang jika belanja mencapai minimum.
+)
+
+// Jenis potongan untuk promosi discount dan min_spend.
+const (
+	DiscountTypePercentage = "percentage" // Value adalah persen (1-100).
+	DiscountTypeFixed      = "fixed"      // Value adalah rupiah (per unit untuk discount, per keranjang untuk min_spend).
+)
+
+// Promotion merepresentasikan aturan promosi yang dievaluasi saat checkout.
+//
+// Promosi hanya berlaku di antara StartAt dan EndAt (jika diisi). HourStart dan
+// HourEnd (format HH:MM) membatasi promosi ke jam tertentu setiap hari untuk
+// harga happy hour; jika HourEnd lebih kecil dari HourStart, jendela waktunya
+// melewati tengah malam.
+type Promotion struct {
+	ID           int        `json:"id"`                    // ID unik untuk promosi.
+	Name         string     `json:"name"`                  // Nama promosi yang tampil di struk.
+	Type         string     `json:"type"`                  // Jenis promosi (discount, buy_x_get_y, min_spend).
+	DiscountType string     `json:"discount_type"`         // Jenis potongan (percentage, fixed) untuk discount dan min_spend.
+	Value        int        `json:"value"`                 // Besar potongan sesuai DiscountType.
+	ProductID    int        `json:"product_id,omitempty"`  // Target produk (opsional).
+	KategoriID   int        `json:"kategori_id,omitempty"` // Target kategori (opsional).
+	BuyQuantity  int        `json:"buy_quantity"`          // Jumlah yang harus dibeli untuk buy_x_get_y.
+	GetQuantity  int        `json:"get_quantity"`          // Jumlah gratis untuk buy_x_get_y.
+	MinSpend     int        `json:"min_spend"`             // Minimum belanja untuk min_spend.
+	StartAt      *time.Time `json:"start_at,omitempty"`    // Awal masa berlaku (opsional).
+	EndAt        *time.Time `json:"end_at,omitempty"`      // Akhir masa berlaku, eksklusif (opsional).
+	HourStart    string     `json:"hour_start,omitempty"`  // Jam mulai harian HH:MM (opsional).
+	HourEnd      string     `json:"hour_end,omitempty"`    // Jam selesai harian HH:MM, eksklusif (opsional).
+	Active       bool       `json:"active"`                // Promosi bisa dinonaktifkan tanpa dihapus.
+	CreatedAt    time.Time  `json:"created_at"`            // Waktu promosi dibuat.
+}
+
+// AppliedPromotion mencatat promosi yang diterapkan pada transaksi. Jika
+// TransactionDetailID bernilai 0, promosi berlaku untuk seluruh keranjang.
+type AppliedPromotion struct {
+	ID                  int    `json:"id"`                              // ID unik untuk catatan promosi.
+	TransactionID       int    `json:"transaction_id"`                  // ID transaksi.
+	TransactionDetailID int    `json:"transaction_detail_id,omitempty"` // ID detail yang mendapat potongan (0 untuk keranjang).
+	PromotionID         int    `json:"promotion_id"`                    // ID promosi (0 jika promosi sudah dihapus).
+	PromotionName       string `json:"promotion_name"`                  // Nama promosi saat transaksi.
+	Amount              int    `json:"amount"`                          // Besar potongan.
+}
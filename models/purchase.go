@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// Status purchase order.
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusApproved          = "approved"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// Supplier merepresentasikan pemasok barang.
+type Supplier struct {
+	ID        int       `json:"id"`         // ID unik untuk supplier.
+	Nama      string    `json:"nama"`       // Nama supplier.
+	Kontak    string    `json:"kontak"`     // Nomor telepon atau email supplier.
+	Alamat    string    `json:"alamat"`     // Alamat supplier.
+	CreatedAt time.Time `json:"created_at"` // Waktu supplier dibuat.
+}
+
+// PurchaseOrder merepresentasikan pesanan pembelian barang ke supplier.
+type PurchaseOrder struct {
+	ID          int                 `json:"id"`                     // ID unik untuk purchase order.
+	SupplierID  int                 `json:"supplier_id"`            // ID supplier.
+	Status      string              `json:"status"`                 // Status PO.
+	Note        string              `json:"note"`                   // Catatan PO.
+	CreatedBy   string              `json:"created_by"`             // Petugas yang membuat PO.
+	ApprovedBy  string              `json:"approved_by,omitempty"`  // Petugas yang menyetujui PO.
+	ApprovedAt  *time.Time          `json:"approved_at,omitempty"`  // Waktu PO disetujui.
+	CancelledBy string              `json:"cancelled_by,omitempty"` // Petugas yang membatalkan PO.
+	CancelledAt *time.Time          `json:"cancelled_at,omitempty"` // Waktu PO dibatalkan.
+	CreatedAt   time.Time           `json:"created_at"`             // Waktu PO dibuat.
+	TotalCost   int                 `json:"total_cost"`             // Total nilai pesanan.
+	Items       []PurchaseOrderItem `json:"items"`                  // Barang yang dipesan.
+	Receipts    []PurchaseReceipt   `json:"receipts,omitempty"`     // Riwayat penerimaan barang.
+}
+
+// PurchaseOrderItem merepresentasikan satu barang yang dipesan dalam PO.
+type PurchaseOrderItem struct {
+	ID               int    `json:"id"`                // ID unik untuk item PO.
+	PurchaseOrderID  int    `json:"purchase_order_id"` // ID purchase order.
+	ProductID        int    `json:"product_id"`        // ID produk yang dipesan.
+	ProductName      string `json:"product_name"`      // Nama produk.
+	Quantity         int    `json:"quantity"`          // Jumlah yang dipesan.
+	CostPrice        int    `json:"cost_price"`        // Harga beli per unit.
+	ReceivedQuantity int    `json:"received_quantity"` // Jumlah yang sudah diterima.
+}
+
+// PurchaseReceipt merepresentasikan satu kali penerimaan barang untuk PO.
+type PurchaseReceipt struct {
+	ID              int                   `json:"id"`                // ID unik untuk penerimaan.
+	PurchaseOrderID int                   `json:"purchase_order_id"` // ID purchase order.
+	ReceivedBy      string                `json:"received_by"`       // Petugas yang menerima barang.
+	Note            string                `json:"note"`              // Catatan penerimaan.
+	CreatedAt       time.Time             `json:"created_at"`        // Waktu barang diterima.
+	Items           []PurchaseReceiptItem `json:"items"`             // Barang yang diterima.
+}
+
+// PurchaseReceiptItem merepresentasikan jumlah yang diterima untuk satu item PO.
+type PurchaseReceiptItem struct {
+	ID                  int `json:"id"`                     // ID unik untuk item penerimaan.
+	ReceiptID           int `json:"receipt_id"`             // ID penerimaan.
+	PurchaseOrderItemID int `json:"purchase_order_item_id"` // ID item PO.
+	ProductID           int `json:"product_id"`             // ID produk yang diterima.
+	Quantity            int `json:"quantity"`               // Jumlah yang diterima.
+	CostPrice           int `json:"cost_price"`             // Harga beli per unit saat diterima.
+}
+
+// PurchaseOrderLine merepresentasikan satu baris barang pada request pembuatan PO.
+type PurchaseOrderLine struct {
+	ProductID int `json:"product_id"` // ID produk yang dipesan.
+	Quantity  int `json:"quantity"`   // Jumlah yang dipesan.
+	CostPrice int `json:"cost_price"` // Harga beli per unit.
+}
+
+// CreatePurchaseOrderRequest merepresentasikan request body untuk membuat PO.
+type CreatePurchaseOrderRequest struct {
+	SupplierID int                 `json:"supplier_id"` // ID supplier.
+	Note       string              `json:"note"`        // Catatan PO.
+	Operator   string              `json:"operator"`    // Petugas yang membuat PO.
+	Items      []PurchaseOrderLine `json:"items"`       // Barang yang dipesan.
+}
+
+// PurchaseOrderActionRequest merepresentasikan request body untuk approve atau cancel PO.
+type PurchaseOrderActionRequest struct {
+	Operator string `json:"operator"` // Petugas yang melakukan aksi.
+}
+
+// ReceiveItem merepresentasikan jumlah yang diterima untuk satu item PO.
+type ReceiveItem struct {
+	ItemID   int `json:"item_id"`  // ID item PO.
+	Quantity int `json:"quantity"` // Jumlah yang diterima.
+}
+
+// ReceivePurchaseOrderRequest merepresentasikan request body untuk penerimaan barang.
+// Items kosong berarti semua sisa barang diterima.
+type ReceivePurchaseOrderRequest struct {
+	Operator string        `json:"operator"` // Petugas yang menerima barang.
+	Note     string        `json:"note"`     // Catatan penerimaan.
+	Items    []ReceiveItem `json:"items"`    // Barang yang diterima.
+}
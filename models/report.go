@@ -0,0 +1,61 @@
+package models
+
+// SalesSummary merangkum penjualan dalam satu periode setelah dikurangi void dan refund.
+type SalesSummary struct {
+	StartDate          string `json:"start_date"`          // Tanggal awal periode (YYYY-MM-DD).
+	EndDate            string `json:"end_date"`            // Tanggal akhir periode, inklusif (YYYY-MM-DD).
+	TransactionCount   int    `json:"transaction_count"`   // Jumlah transaksi yang dibuat dalam periode.
+	GrossSales         int    `json:"gross_sales"`         // Total penjualan sebelum void/refund.
+	RoundingAdjustment int    `json:"rounding_adjustment"` // Total selisih pembulatan yang sudah termasuk dalam GrossSales.
+	VoidCount          int    `json:"void_count"`          // Jumlah void dalam periode.
+	VoidedAmount       int    `json:"voided_amount"`       // Nilai transaksi yang di-void.
+	RefundCount        int    `json:"refund_count"`        // Jumlah refund dalam periode.
+	RefundedAmount     int    `json:"refunded_amount"`     // Nilai barang yang di-refund.
+	NetSales           int    `json:"net_sales"`           // Penjualan bersih setelah void dan refund.
+}
+
+// Pengelompokan laporan margin.
+const (
+	MarginGroupProduct  = "product"
+	MarginGroupKategori = "kategori"
+	MarginGroupDay      = "day"
+)
+
+// MarginReport merangkum laba kotor (penjualan bersih dikurangi HPP) dalam satu periode.
+type MarginReport struct {
+	StartDate string       `json:"start_date"` // Tanggal awal periode (YYYY-MM-DD).
+	EndDate   string       `json:"end_date"`   // Tanggal akhir periode, inklusif (YYYY-MM-DD).
+	GroupBy   string       `json:"group_by"`   // Pengelompokan baris (product, kategori, day).
+	Lines     []MarginLine `json:"lines"`      // Laba kotor per kelompok.
+	Total     MarginLine   `json:"total"`      // Total seluruh kelompok.
+}
+
+// MarginLine merangkum laba kotor satu kelompok (produk, kategori, atau tanggal).
+type MarginLine struct {
+	Key           string  `json:"key"`            // ID produk/kategori atau tanggal (YYYY-MM-DD).
+	Name          string  `json:"name"`           // Nama produk/kategori, atau tanggal.
+	Quantity      int     `json:"quantity"`       // Jumlah barang terjual bersih.
+	Revenue       int     `json:"revenue"`        // Penjualan bersih setelah void/refund.
+	Cost          int     `json:"cost"`           // Harga pokok barang terjual bersih.
+	GrossMargin   int     `json:"gross_margin"`   // Revenue - Cost.
+	MarginPercent float64 `json:"margin_percent"` // GrossMargin / Revenue dalam persen.
+}
+
+// TaxReport merangkum pajak dan service charge dalam satu periode untuk pelaporan.
+type TaxReport struct {
+	StartDate    string          `json:"start_date"`    // Tanggal awal periode (YYYY-MM-DD).
+	EndDate      string          `json:"end_date"`      // Tanggal akhir periode, inklusif (YYYY-MM-DD).
+	Lines        []TaxReportLine `json:"lines"`         // Rekap per aturan pajak.
+	TotalTax     int             `json:"total_tax"`     // Total pajak bersih.
+	TotalService int             `json:"total_service"` // Total service charge bersih.
+}
+
+// TaxReportLine merangkum satu aturan pajak (nama, tarif, dan sifat inklusif saat transaksi).
+type TaxReportLine struct {
+	Name      string  `json:"name"`      // Nama aturan pajak.
+	Type      string  `json:"type"`      // Jenis aturan (tax, service).
+	Rate      float64 `json:"rate"`      // Tarif dalam persen.
+	Inclusive bool    `json:"inclusive"` // Tarif sudah termasuk dalam harga jual.
+	Base      int     `json:"base"`      // Dasar pengenaan bersih setelah void/refund.
+	Amount    int     `json:"amount"`    // Pajak bersih setelah void/refund.
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Jenis pembalikan transaksi.
+const (
+	ReversalTypeVoid   = "void"   // Pembatalan seluruh transaksi pada hari yang sama.
+	ReversalTypeRefund = "refund" // Pengembalian sebagian atau seluruh barang.
+)
+
+// Reversal merepresentasikan void atau refund yang membalik sebagian atau seluruh transaksi.
+type Reversal struct {
+	ID             int            `json:"id"`                 // ID unik untuk reversal.
+	TransactionID  int            `json:"transaction_id"`     // ID transaksi asal yang dibalik.
+	Type           string         `json:"type"`               // Jenis reversal (void atau refund).
+	Amount         int            `json:"amount"`             // Nilai yang dikembalikan, termasuk bagian yang dibayar dengan poin.
+	PointsAmount   int            `json:"points_amount"`      // Bagian Amount yang dikembalikan sebagai poin; sisanya dibayar dari laci.
+	PointsReversed int            `json:"points_reversed"`    // Poin perolehan yang dibalik dari saldo pelanggan.
+	PointsRestored int            `json:"points_restored"`    // Poin yang ditukar dan dikembalikan ke pelanggan.
+	ShiftID        int            `json:"shift_id,omitempty"` // ID shift yang mengeluarkan uang reversal.
+	Reason         string         `json:"reason"`             // Alasan void/refund.
+	Operator       string         `json:"operator"`           // Petugas yang memproses.
+	CreatedAt      time.Time      `json:"created_at"`         // Waktu reversal dibuat.
+	Items          []ReversalItem `json:"items"`              // Barang yang dikembalikan ke stok.
+}
+
+// ReversalItem merepresentasikan satu baris barang yang dikembalikan.
+type ReversalItem struct {
+	ID                  int `json:"id"`                    // ID unik untuk item reversal.
+	ReversalID          int `json:"reversal_id"`           // ID reversal yang terkait.
+	TransactionDetailID int `json:"transaction_detail_id"` // ID detail transaksi asal.
+	ProductID           int `json:"product_id"`            // ID produk yang dikembalikan (0 jika produk sudah dihapus).
+	Quantity            int `json:"quantity"`              // Jumlah barang yang dikembalikan.
+	Amount              int `json:"amount"`                // Nilai uang untuk baris ini.
+}
+
+// VoidRequest merepresentasikan request body untuk void transaksi.
+type VoidRequest struct {
+	Reason   string `json:"reason"`   // Alasan pembatalan.
+	Operator string `json:"operator"` // Petugas yang membatalkan.
+}
+
+// RefundItem merepresentasikan barang yang diminta untuk di-refund.
+type RefundItem struct {
+	DetailID int `json:"detail_id"` // ID detail transaksi asal.
+	Quantity int `json:"quantity"`  // Jumlah barang yang dikembalikan.
+}
+
+// RefundRequest merepresentasikan request body untuk refund. Items kosong
+// berarti semua barang yang belum dikembalikan ikut di-refund.
+type RefundRequest struct {
+	Reason   string       `json:"reason"`          // Alasan refund.
+	Operator string       `json:"operator"`        // Petugas yang memproses refund.
+	Register string       `json:"register"`        // Register yang mengeluarkan uang refund; harus punya shift yang terbuka.
+	Items    []RefundItem `json:"items,omitempty"` // Barang yang dikembalikan (opsional).
+}
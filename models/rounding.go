@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Mode pembulatan grand total.
+const (
+	RoundingModeNearest = "nearest" // Ke kelipatan terdekat; tepat di tengah dibulatkan ke atas.
+	RoundingModeUp      = "up"      // Selalu ke atas.
+	RoundingModeDown    = "down"    // Selalu ke bawah.
+)
+
+// RoundingPolicy mengatur pembulatan grand total untuk satu metode pembayaran,
+// misalnya tunai dibulatkan ke Rp100 terdekat. Metode tanpa kebijakan tidak dibulatkan.
+type RoundingPolicy struct {
+	Method    string    `json:"method"`     // Metode pembayaran (cash, debit, qris, e-wallet).
+	Mode      string    `json:"mode"`       // Mode pembulatan (nearest, up, down).
+	Unit      int       `json:"unit"`       // Kelipatan pembulatan dalam rupiah, misalnya 100 atau 500.
+	UpdatedAt time.Time `json:"updated_at"` // Waktu kebijakan terakhir diubah.
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Status shift kasir.
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+)
+
+// Jenis uang masuk/keluar laci di luar penjualan.
+const (
+	CashMovementPayIn  = "pay_in"  // Uang masuk, misalnya tambahan uang kembalian.
+	CashMovementPayOut = "pay_out" // Uang keluar, misalnya beli es atau bayar parkir.
+)
+
+// Shift merepresentasikan satu sesi kerja kasir pada satu register (mesin
+// kasir). Checkout hanya bisa dilakukan pada register yang shift-nya terbuka.
+type Shift struct {
+	ID             int               `json:"id"`                       // ID unik untuk shift.
+	Register       string            `json:"register"`                 // Kode register/mesin kasir.
+	Cashier        string            `json:"cashier"`                  // Kasir yang membuka shift.
+	Status         string            `json:"status"`                   // Status shift (open, closed).
+	OpeningFloat   int               `json:"opening_float"`            // Uang tunai awal di laci.
+	ExpectedCash   int               `json:"expected_cash"`            // Uang tunai yang seharusnya ada di laci (dibekukan saat tutup).
+	CountedCash    int               `json:"counted_cash"`             // Uang tunai hasil hitung saat tutup.
+	CashDifference int               `json:"cash_difference"`          // Selisih CountedCash - ExpectedCash.
+	Note           string            `json:"note,omitempty"`           // Catatan saat tutup shift.
+	OpenedAt       time.Time         `json:"opened_at"`                // Waktu shift dibuka.
+	ClosedBy       string            `json:"closed_by,omitempty"`      // Petugas yang menutup shift.
+	ClosedAt       *time.Time        `json:"closed_at,omitempty"`      // Waktu shift ditutup.
+	Report         []ShiftReportLine `json:"report,omitempty"`         // Rekonsiliasi per metode pembayaran.
+	CashMovements  []CashMovement    `json:"cash_movements,omitempty"` // Pay-in/pay-out selama shift.
+	NoSales        []NoSale          `json:"no_sales,omitempty"`       // Laci dibuka tanpa penjualan selama shift.
+}
+
+// ShiftReportLine merangkum uang yang seharusnya dan yang dihitung untuk satu
+// metode pembayaran dalam shift. Counted dan Difference kosong jika metode
+// tersebut tidak dihitung saat tutup shift.
+type ShiftReportLine struct {
+	Method     string `json:"method"`               // Metode pembayaran.
+	Opening    int    `json:"opening"`              // Saldo awal (uang tunai awal untuk cash).
+	Sales      int    `json:"sales"`                // Penjualan yang dibayar dengan metode ini (tunai sudah dikurangi kembalian).
+	Refunds    int    `json:"refunds"`              // Uang yang dikembalikan lewat void/refund.
+	PayIns     int    `json:"pay_ins"`              // Uang masuk laci di luar penjualan (hanya cash).
+	PayOuts    int    `json:"pay_outs"`             // Uang keluar laci di luar refund (hanya cash).
+	Expected   int    `json:"expected"`             // Opening + Sales - Refunds + PayIns - PayOuts.
+	Counted    *int   `json:"counted,omitempty"`    // Hasil hitung saat tutup shift.
+	Difference *int   `json:"difference,omitempty"` // Selisih Counted - Expected.
+}
+
+// OpenShiftRequest merepresentasikan request body untuk membuka shift.
+type OpenShiftRequest struct {
+	Register     string `json:"register"`      // Kode register/mesin kasir.
+	Cashier      string `json:"cashier"`       // Kasir yang bertugas.
+	OpeningFloat int    `json:"opening_float"` // Uang tunai awal di laci.
+}
+
+// ShiftCount merepresentasikan hasil hitung satu metode pembayaran non-tunai,
+// misalnya total settlement EDC.
+type ShiftCount struct {
+	Method string `json:"method"` // Metode pembayaran.
+	Amount int    `json:"amount"` // Jumlah hasil hitung.
+}
+
+// CloseShiftRequest merepresentasikan request body untuk menutup shift.
+type CloseShiftRequest struct {
+	CountedCash int          `json:"counted_cash"`     // Uang tunai hasil hitung di laci.
+	Counts      []ShiftCount `json:"counts,omitempty"` // Hasil hitung metode non-tunai (opsional).
+	Operator    string       `json:"operator"`         // Petugas yang menutup shift.
+	Note        string       `json:"note,omitempty"`   // Catatan penutupan.
+}
+
+// CashMovement merepresentasikan uang tunai yang masuk atau keluar laci di luar
+// penjualan dan refund.
+type CashMovement struct {
+	ID        int       `json:"id"`         // ID unik untuk catatan.
+	ShiftID   int       `json:"shift_id"`   // Shift yang lacinya berubah.
+	Type      string    `json:"type"`       // Jenis (pay_in, pay_out).
+	Amount    int       `json:"amount"`     // Jumlah uang, selalu positif.
+	Reason    string    `json:"reason"`     // Alasan uang masuk/keluar.
+	Operator  string    `json:"operator"`   // Petugas yang mencatat.
+	CreatedAt time.Time `json:"created_at"` // Waktu dicatat.
+}
+
+// CashMovementRequest merepresentasikan request body untuk mencatat pay-in/pay-out
+// pada shift yang sedang terbuka di register.
+type CashMovementRequest struct {
+	Register string `json:"register"` // Register yang lacinya berubah; harus punya shift yang terbuka.
+	Type     string `json:"type"`     // Jenis (pay_in, pay_out).
+	Amount   int    `json:"amount"`   // Jumlah uang, harus lebih dari 0.
+	Reason   string `json:"reason"`   // Alasan uang masuk/keluar.
+	Operator string `json:"operator"` // Petugas yang mencatat.
+}
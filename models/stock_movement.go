@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Alasan perubahan stok pada ledger stock_movements.
+const (
+	StockReasonSale       = "sale"       // Barang terjual lewat checkout.
+	StockReasonVoid       = "void"       // Barang kembali karena transaksi di-void.
+	StockReasonRefund     = "refund"     // Barang kembali karena refund.
+	StockReasonAdjustment = "adjustment" // Koreksi manual, misalnya stok awal saat produk dibuat.
+	StockReasonPurchase   = "purchase"   // Barang masuk dari pembelian ke supplier.
+	StockReasonOpname     = "opname"     // Penyesuaian hasil stock opname.
+)
+
+// StockMovement merepresentasikan satu baris ledger perubahan stok produk.
+type StockMovement struct {
+	ID          int       `json:"id"`                     // ID unik untuk pergerakan stok.
+	ProductID   int       `json:"product_id"`             // ID produk yang stoknya berubah (0 jika produk sudah dihapus).
+	ProductName string    `json:"product_name"`           // Nama produk saat perubahan dicatat.
+	Delta       int       `json:"delta"`                  // Perubahan stok (negatif berarti keluar).
+	StokAfter   int       `json:"stok_after"`             // Stok produk setelah perubahan ini.
+	Reason      string    `json:"reason"`                 // Alasan perubahan (sale, refund, adjustment, ...).
+	ReferenceID *int      `json:"reference_id,omitempty"` // ID dokumen sumber (transaksi, reversal, PO, ...).
+	Operator    string    `json:"operator"`               // Petugas yang melakukan perubahan.
+	Note        string    `json:"note,omitempty"`         // Catatan tambahan.
+	CreatedAt   time.Time `json:"created_at"`             // Waktu perubahan dicatat.
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Jenis aturan pajak.
+const (
+	TaxTypeTax     = "tax"     // Pajak (PPN).
+	TaxTypeService = "service" // Service charge.
+)
+
+// TaxRule merepresentasikan tarif pajak atau service charge yang dihitung saat checkout.
+//
+// Aturan berlaku untuk satu produk, satu kategori, atau semua produk jika
+// keduanya kosong. Untuk setiap jenis, satu baris hanya dikenai aturan yang
+// paling spesifik (produk, lalu kategori, lalu semua produk). Jika Inclusive,
+// tarif sudah termasuk dalam harga jual sehingga tidak menambah total. PPN
+// dihitung dari dasar pengenaan ditambah service charge.
+type TaxRule struct {
+	ID         int       `json:"id"`                    // ID unik untuk aturan pajak.
+	Name       string    `json:"name"`                  // Nama yang tampil di struk, misalnya "PPN 11%".
+	Type       string    `json:"type"`                  // Jenis aturan (tax, service).
+	Rate       float64   `json:"rate"`                  // Tarif dalam persen, maksimal dua desimal.
+	Inclusive  bool      `json:"inclusive"`             // Tarif sudah termasuk dalam harga jual.
+	ProductID  int       `json:"product_id,omitempty"`  // Target produk (opsional).
+	KategoriID int       `json:"kategori_id,omitempty"` // Target kategori (opsional).
+	Active     bool      `json:"active"`                // Aturan bisa dinonaktifkan tanpa dihapus.
+	CreatedAt  time.Time `json:"created_at"`            // Waktu aturan dibuat.
+}
+
+// AppliedTax mencatat pajak atau service charge yang dikenakan pada satu detail transaksi.
+type AppliedTax struct {
+	ID                  int     `json:"id"`                    // ID unik untuk catatan pajak.
+	TransactionID       int     `json:"transaction_id"`        // ID transaksi.
+	TransactionDetailID int     `json:"transaction_detail_id"` // ID detail yang dikenai pajak.
+	TaxRuleID           int     `json:"tax_rule_id"`           // ID aturan pajak (0 jika aturan sudah dihapus).
+	Name                string  `json:"name"`                  // Nama aturan saat transaksi.
+	Type                string  `json:"type"`                  // Jenis aturan (tax, service).
+	Rate                float64 `json:"rate"`                  // Tarif dalam persen saat transaksi.
+	Inclusive           bool    `json:"inclusive"`             // Tarif sudah termasuk dalam harga jual.
+	Base                int     `json:"base"`                  // Dasar pengenaan pajak.
+	Amount              int     `json:"amount"`                // Besar pajak atau service charge.
+}
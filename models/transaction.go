@@ -5,29 +5,140 @@ import "time"
 
 // Transaction merepresentasikan data transaksi pada sistem kasir.
 type Transaction struct {
-	ID          int                 `json:"id"`          // ID unik untuk transaksi.
-	TotalAmount int                 `json:"total_amount"` // Total harga dari transaksi.
-	CreatedAt   time.Time           `json:"created_at"`   // Waktu transaksi dibuat.
-	Details     []TransactionDetail `json:"details"`      // Detail item dalam transaksi.
+	ID                 int                  `json:"id"`                          // ID unik untuk transaksi.
+	Status             string               `json:"status"`                      // Status transaksi (completed, voided, refunded, partially_refunded).
+	CustomerID         int                  `json:"customer_id,omitempty"`       // ID pelanggan (0 jika tanpa pelanggan atau pelanggan sudah dihapus).
+	ShiftID            int                  `json:"shift_id,omitempty"`          // ID shift kasir saat transaksi dibuat.
+	SubtotalAmount     int                  `json:"subtotal_amount"`             // Total harga sebelum potongan.
+	LineDiscount       int                  `json:"line_discount"`               // Total potongan promosi per baris.
+	CartDiscount       int                  `json:"cart_discount"`               // Total potongan promosi keranjang.
+	VoucherCode        string               `json:"voucher_code,omitempty"`      // Kode voucher yang ditukar.
+	VoucherDiscount    int                  `json:"voucher_discount"`            // Potongan dari voucher.
+	ManualDiscount     int                  `json:"manual_discount"`             // Potongan manual kasir untuk seluruh keranjang.
+	TaxAmount          int                  `json:"tax_amount"`                  // Total pajak (PPN), termasuk yang sudah ada di harga.
+	ServiceCharge      int                  `json:"service_charge"`              // Total service charge, termasuk yang sudah ada di harga.
+	TaxIncluded        int                  `json:"tax_included"`                // Bagian pajak dan service yang sudah termasuk dalam harga.
+	RoundingAdjustment int                  `json:"rounding_adjustment"`         // Selisih pembulatan grand total (bisa negatif).
+	TotalAmount        int                  `json:"total_amount"`                // Grand total: subtotal - potongan + pajak + service - TaxIncluded + RoundingAdjustment.
+	PaidAmount         int                  `json:"paid_amount"`                 // Jumlah uang yang dibayarkan pelanggan.
+	ChangeAmount       int                  `json:"change_amount"`               // Kembalian untuk pelanggan.
+	PointsEarned       int                  `json:"points_earned"`               // Poin loyalitas yang diperoleh pelanggan.
+	PointsRedeemed     int                  `json:"points_redeemed"`             // Poin loyalitas yang ditukar sebagai pembayaran.
+	CreatedAt          time.Time            `json:"created_at"`                  // Waktu transaksi dibuat.
+	Details            []TransactionDetail  `json:"details"`                     // Detail item dalam transaksi.
+	Payments           []TransactionPayment `json:"payments,omitempty"`          // Pembayaran yang tercatat untuk transaksi.
+	PaymentBreakdown   []PaymentBreakdown   `json:"payment_breakdown,omitempty"` // Ringkasan pembayaran per metode.
+	Reversals          []Reversal           `json:"reversals,omitempty"`         // Void/refund yang terkait transaksi ini.
+	Promotions         []AppliedPromotion   `json:"promotions,omitempty"`        // Promosi yang diterapkan pada transaksi.
+	Taxes              []AppliedTax         `json:"taxes,omitempty"`             // Pajak dan service charge per baris.
 }
 
+// Status transaksi.
+const (
+	TransactionStatusCompleted         = "completed"
+	TransactionStatusVoided            = "voided"
+	TransactionStatusRefunded          = "refunded"
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+)
+
 // TransactionDetail merepresentasikan detail item dalam satu transaksi.
 type TransactionDetail struct {
-	ID            int    `json:"id"`             // ID unik untuk detail transaksi.
-	TransactionID int    `json:"transaction_id"` // ID transaksi yang terkait.
-	ProductID     int    `json:"product_id"`     // ID produk yang dibeli.
-	ProductName   string `json:"product_name,omitempty"` // Nama produk (opsional, dari join).
-	Quantity      int    `json:"quantity"`       // Jumlah barang yang dibeli.
-	Subtotal      int    `json:"subtotal"`       // Subtotal harga (harga * quantity).
+	ID               int    `json:"id"`                      // ID unik untuk detail transaksi.
+	TransactionID    int    `json:"transaction_id"`          // ID transaksi yang terkait.
+	ProductID        int    `json:"product_id"`              // ID produk yang dibeli (0 jika produk sudah dihapus).
+	ProductName      string `json:"product_name,omitempty"`  // Nama produk saat transaksi.
+	KategoriID       int    `json:"kategori_id,omitempty"`   // ID kategori produk saat transaksi.
+	KategoriNama     string `json:"kategori_nama,omitempty"` // Nama kategori produk saat transaksi.
+	Quantity         int    `json:"quantity"`                // Jumlah barang yang dibeli.
+	UnitPrice        int    `json:"unit_price"`              // Harga jual per unit saat transaksi.
+	CostPrice        int    `json:"cost_price"`              // Harga beli per unit saat transaksi.
+	Discount         int    `json:"discount"`                // Potongan promosi untuk baris ini.
+	CartDiscount     int    `json:"cart_discount"`           // Bagian potongan keranjang, voucher, dan potongan manual yang dibebankan ke baris ini.
+	Subtotal         int    `json:"subtotal"`                // Subtotal setelah potongan (harga * quantity - Discount - CartDiscount).
+	Tax              int    `json:"tax"`                     // PPN untuk baris ini.
+	ServiceCharge    int    `json:"service_charge"`          // Service charge untuk baris ini.
+	TaxIncluded      int    `json:"tax_included"`            // Bagian pajak dan service yang sudah termasuk dalam Subtotal.
+	Total            int    `json:"total"`                   // Jumlah yang dibayar untuk baris ini (Subtotal + Tax + ServiceCharge - TaxIncluded).
+	PointsEarned     int    `json:"points_earned"`           // Bagian poin perolehan transaksi untuk baris ini.
+	RefundedQuantity int    `json:"refunded_quantity"`       // Jumlah barang yang sudah dikembalikan lewat void/refund.
+}
+
+// Metode pembayaran yang didukung saat checkout.
+const (
+	PaymentMethodCash    = "cash"
+	PaymentMethodDebit   = "debit"
+	PaymentMethodQRIS    = "qris"
+	PaymentMethodEWallet = "e-wallet"
+	PaymentMethodPoints  = "points" // Penukaran poin loyalitas; Amount dalam rupiah.
+)
+
+// TransactionPayment merepresentasikan satu pembayaran yang tercatat pada transaksi.
+type TransactionPayment struct {
+	ID            int    `json:"id"`                  // ID unik untuk pembayaran.
+	TransactionID int    `json:"transaction_id"`      // ID transaksi yang dibayar.
+	Method        string `json:"method"`              // Metode pembayaran (cash, debit, qris, e-wallet, points).
+	Amount        int    `json:"amount"`              // Jumlah yang diserahkan pelanggan.
+	Reference     string `json:"reference,omitempty"` // Nomor referensi EDC/QRIS/e-wallet (opsional).
+}
+
+// PaymentBreakdown merangkum pembayaran satu metode dalam transaksi. Amount adalah
+// porsi total belanja yang ditutup metode ini (untuk tunai sudah dikurangi kembalian).
+type PaymentBreakdown struct {
+	Method   string `json:"method"`   // Metode pembayaran.
+	Amount   int    `json:"amount"`   // Jumlah yang dipakai untuk menutup total belanja.
+	Tendered int    `json:"tendered"` // Jumlah yang diserahkan pelanggan dengan metode ini.
+}
+
+// CheckoutPayment merepresentasikan pembayaran yang dikirim saat checkout.
+type CheckoutPayment struct {
+	Method    string `json:"method"`              // Metode pembayaran.
+	Amount    int    `json:"amount"`              // Jumlah yang diserahkan pelanggan.
+	Reference string `json:"reference,omitempty"` // Nomor referensi (opsional).
 }
 
 // CheckoutItem merepresentasikan item yang akan di-checkout.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"` // ID produk yang dibeli.
-	Quantity  int `json:"quantity"`   // Jumlah barang yang dibeli.
+	ProductID     int  `json:"product_id"`               // ID produk yang dibeli.
+	Quantity      int  `json:"quantity"`                 // Jumlah barang yang dibeli.
+	PriceOverride *int `json:"price_override,omitempty"` // Harga per unit pengganti harga katalog (butuh persetujuan manajer).
 }
 
 // CheckoutRequest merepresentasikan request body untuk checkout.
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"` // Daftar item yang akan dibeli.
+	Items       []CheckoutItem    `json:"items"`                  // Daftar item yang akan dibeli.
+	Payments    []CheckoutPayment `json:"payments"`               // Satu atau beberapa pembayaran (split tender).
+	VoucherCode string            `json:"voucher_code,omitempty"` // Kode voucher (opsional).
+	CustomerID  int               `json:"customer_id,omitempty"`  // ID pelanggan (opsional).
+	Register    string            `json:"register"`               // Kode register; harus punya shift yang terbuka.
+	// ManualDiscount adalah potongan manual kasir (rupiah) untuk seluruh
+	// keranjang. Di atas DiscountApprovalPercent dari harga sebelum potongan,
+	// checkout butuh persetujuan manajer.
+	ManualDiscount int `json:"manual_discount,omitempty"`
+	// DiscountApprovalPercent diisi handler dari konfigurasi; 0 berarti setiap
+	// potongan manual butuh persetujuan.
+	DiscountApprovalPercent int `json:"-"`
+	// MaxLineQuantity diisi handler dari konfigurasi: jumlah maksimum satu
+	// produk setelah baris yang sama digabung. 0 berarti tanpa batas.
+	MaxLineQuantity int `json:"-"`
+	// IdempotencyKey diisi handler dari header Idempotency-Key. Key yang sudah
+	// diklaim ditandai selesai bersama transaksi yang dibuat.
+	IdempotencyKey string `json:"-"`
+	// IdempotencyScope diisi handler dari pengguna atau kunci API yang login
+	// (misalnya user:3 atau api_key:7). Key yang sama dari pemilik lain
+	// dianggap key berbeda.
+	IdempotencyScope string `json:"-"`
+}
+
+// CheckoutLineError menjelaskan kesalahan pada satu baris items checkout.
+type CheckoutLineError struct {
+	Line      int    `json:"line"`       // Nomor baris di items (mulai dari 1).
+	ProductID int    `json:"product_id"` // ID produk pada baris tersebut.
+	Field     string `json:"field"`      // Field yang salah (product_id, quantity, price_override).
+	Message   string `json:"message"`    // Penjelasan kesalahan.
+}
+
+// CheckoutErrorResponse adalah response body checkout yang ditolak.
+type CheckoutErrorResponse struct {
+	Error string              `json:"error"`           // Ringkasan kesalahan.
+	Lines []CheckoutLineError `json:"lines,omitempty"` // Kesalahan per baris items (jika ada).
 }
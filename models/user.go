@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Role pengguna. Hak akses setiap role diatur oleh middleware di package handlers.
+const (
+	RoleOwner      = "owner"
+	RoleManager    = "manager"
+	RoleCashier    = "cashier"
+	RoleStockClerk = "stock_clerk"
+)
+
+// User merepresentasikan akun pengguna aplikasi kasir. Hash password tidak
+// pernah dikirim ke client.
+type User struct {
+	ID        int       `json:"id"`         // ID unik untuk pengguna.
+	Username  string    `json:"username"`   // Nama login, unik dan huruf kecil.
+	Role      string    `json:"role"`       // Role (owner, manager, cashier, stock_clerk).
+	Active    bool      `json:"active"`     // Pengguna nonaktif tidak bisa login.
+	CreatedAt time.Time `json:"created_at"` // Waktu akun dibuat.
+	UpdatedAt time.Time `json:"updated_at"` // Waktu akun terakhir diubah.
+}
+
+// CreateUserRequest merepresentasikan request body untuk membuat pengguna.
+type CreateUserRequest struct {
+	Username string `json:"username"` // Nama login.
+	Password string `json:"password"` // Password, minimal 8 karakter.
+	Role     string `json:"role"`     // Role pengguna.
+}
+
+// UpdateUserRequest merepresentasikan perubahan akun pengguna. Field yang
+// kosong (nil) tidak diubah.
+type UpdateUserRequest struct {
+	Role     *string `json:"role,omitempty"`     // Role baru.
+	Active   *bool   `json:"active,omitempty"`   // Aktif/nonaktifkan akun.
+	Password *string `json:"password,omitempty"` // Password baru, minimal 8 karakter.
+	PIN      *string `json:"pin,omitempty"`      // PIN persetujuan 4-8 digit untuk manajer/owner; string kosong menghapus PIN.
+}
+
+// LoginRequest merepresentasikan request body untuk login.
+type LoginRequest struct {
+	Username string `json:"username"` // Nama login.
+	Password string `json:"password"` // Password.
+}
+
+// Session merepresentasikan token sesi hasil login. Token hanya dikirim sekali
+// saat login; database hanya menyimpan hash-nya.
+type Session struct {
+	Token     string    `json:"token"`      // Token untuk header Authorization: Bearer.
+	ExpiresAt time.Time `json:"expires_at"` // Waktu token kedaluwarsa.
+	User      User      `json:"user"`       // Pengguna pemilik sesi.
+}
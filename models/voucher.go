@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Status penukaran voucher.
+const (
+	VoucherRedemptionRedeemed = "redeemed"
+	VoucherRedemptionReleased = "released"
+)
+
+// Voucher merepresentasikan kode kupon yang bisa ditukar saat checkout.
+//
+// UsageLimit membatasi jumlah penukaran total dan PerCustomerLimit membatasi
+// penukaran per pelanggan; nilai 0 berarti tidak dibatasi. Voucher dengan
+// PerCustomerLimit hanya bisa dipakai jika checkout menyebutkan pelanggan.
+type Voucher struct {
+	ID               int        `json:"id"`                 // ID unik untuk voucher.
+	Code             string     `json:"code"`               // Kode voucher (disimpan huruf besar).
+	Description      string     `json:"description"`        // Keterangan voucher.
+	DiscountType     string     `json:"discount_type"`      // Jenis potongan (percentage, fixed).
+	Value            int        `json:"value"`              // Persen atau rupiah sesuai DiscountType.
+	MaxDiscount      int        `json:"max_discount"`       // Batas potongan untuk voucher persen (0 = tanpa batas).
+	MinSpend         int        `json:"min_spend"`          // Minimum belanja setelah promosi.
+	StartAt          *time.Time `json:"start_at,omitempty"` // Awal masa berlaku (opsional).
+	EndAt            *time.Time `json:"end_at,omitempty"`   // Akhir masa berlaku, eksklusif (opsional).
+	UsageLimit       int        `json:"usage_limit"`        // Batas penukaran total (0 = tanpa batas).
+	PerCustomerLimit int        `json:"per_customer_limit"` // Batas penukaran per pelanggan (0 = tanpa batas).
+	UsedCount        int        `json:"used_count"`         // Jumlah penukaran yang masih berlaku.
+	Active           bool       `json:"active"`             // Voucher bisa dinonaktifkan tanpa dihapus.
+	CreatedAt        time.Time  `json:"created_at"`         // Waktu voucher dibuat.
+}
+
+// VoucherRedemption mencatat satu penukaran voucher pada transaksi.
+type VoucherRedemption struct {
+	ID            int        `json:"id"`                    // ID unik untuk penukaran.
+	VoucherID     int        `json:"voucher_id"`            // ID voucher.
+	TransactionID int        `json:"transaction_id"`        // ID transaksi.
+	CustomerID    int        `json:"customer_id,omitempty"` // ID pelanggan (opsional).
+	Amount        int        `json:"amount"`                // Besar potongan.
+	Status        string     `json:"status"`                // Status penukaran (redeemed, released).
+	CreatedAt     time.Time  `json:"created_at"`            // Waktu voucher ditukar.
+	ReleasedAt    *time.Time `json:"released_at,omitempty"` // Waktu penukaran dilepas karena void.
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// Format kunci API: awalan tetap diikuti 256 bit acak dalam base64url.
+const (
+	apiKeyPrefix       = "kasir_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+// apiKeyResources berisi resource /api yang boleh diberikan ke kunci API.
+// Akun pengguna, kunci API, dan persetujuan manajer sengaja tidak ada agar
+// kunci tidak bisa menaikkan hak aksesnya sendiri.
+var apiKeyResources = map[string]bool{
+	"produk":          true,
+	"kategori":        true,
+	"promotion":       true,
+	"voucher":         true,
+	"tax-rule":        true,
+	"rounding-policy": true,
+	"customer":        true,
+	"loyalty":         true,
+	"shift":           true,
+	"cash-movement":   true,
+	"checkout":        true,
+	"transaction":     true,
+	"report":          true,
+	"opname":          true,
+	"supplier":        true,
+	"purchase-order":  true,
+}
+
+// prepareAPIKey memvalidasi request pembuatan kunci API lalu merapikan isinya.
+// Scope diurutkan dan duplikatnya dibuang; role kosong menjadi cashier.
+func prepareAPIKey(req models.CreateAPIKeyRequest) (models.CreateAPIKeyRequest, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Outlet = strings.TrimSpace(req.Outlet)
+	req.Role = strings.TrimSpace(req.Role)
+	if req.Name == "" {
+		return req, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if req.Role == "" {
+		req.Role = models.RoleCashier
+	}
+	if !validRoles[req.Role] {
+		return req, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, req.Role)
+	}
+	if len(req.Scopes) == 0 {
+		return req, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		resource, access, _ := strings.Cut(scope, ":")
+		if !apiKeyResources[resource] || (access != "read" && access != "write") {
+			return req, fmt.Errorf("%w: invalid scope %q", ErrInvalidInput, scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+	req.Scopes = slices.Compact(scopes)
+	return req, nil
+}
+
+// newAPIKey membuat kunci API acak beserta awalan untuk ditampilkan dan hash
+// yang disimpan di database.
+func newAPIKey() (key, prefix, keyHash string, err error) {
+	token, _, err := newSessionToken()
+	if err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + token
+	return key, key[:apiKeyPrefixLength], hashToken(key), nil
+}
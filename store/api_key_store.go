@@ -0,0 +1,219 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+
+	"kasir-api/models"
+)
+
+// apiKeyColumns adalah kolom api_keys sesuai urutan scanAPIKey (tanpa hash kunci).
+const apiKeyColumns = "id, name, prefix, scopes, role, outlet, created_by, created_at, last_used_at, rotated_at, revoked_at"
+
+// CreateAPIKey membuat kunci API baru. Kunci utuh hanya ada di hasil fungsi ini.
+func (s *PostgresStore) CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.APIKey, error) {
+	req, err := prepareAPIKey(req)
+	if err != nil {
+		return nil, err
+	}
+	key, prefix, keyHash, err := newAPIKey()
+	if err != nil {
+		log.Printf("[api-key-store] Error generate api key: %v", err)
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[api-key-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	k, err := scanAPIKey(tx.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, role, outlet, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING `+apiKeyColumns,
+		req.Name, prefix, keyHash, pq.Array(req.Scopes), req.Role, req.Outlet, req.CreatedBy))
+	if err != nil {
+		log.Printf("[api-key-store] Error CreateAPIKey: %v", err)
+		return nil, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityAPIKey, models.AuditActionCreate, k.ID, nil, k); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[api-key-store] Error commit CreateAPIKey: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[api-key-store] API key created id=%d prefix=%s scopes=%v role=%s", k.ID, k.Prefix, k.Scopes, k.Role)
+	k.Key = key
+	return &k, nil
+}
+
+// GetAllAPIKeys mengembalikan semua kunci API urut ID, termasuk yang sudah dicabut.
+func (s *PostgresStore) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY id")
+	if err != nil {
+		log.Printf("[api-key-store] Error GetAllAPIKeys: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("[api-key-store] Error scanning api key row: %v", err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[api-key-store] Error iterating api key rows: %v", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey mencabut kunci API sehingga tidak bisa dipakai lagi.
+func (s *PostgresStore) RevokeAPIKey(ctx context.Context, id int) (*models.APIKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[api-key-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockActiveAPIKey(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := scanAPIKey(tx.QueryRowContext(ctx, `
+		UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+apiKeyColumns, id))
+	if err != nil {
+		log.Printf("[api-key-store] Error RevokeAPIKey: %v", err)
+		return nil, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityAPIKey, models.AuditActionUpdate, id, before, k); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[api-key-store] Error commit RevokeAPIKey: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[api-key-store] API key revoked id=%d prefix=%s", k.ID, k.Prefix)
+	return &k, nil
+}
+
+// RotateAPIKey mengganti rahasia kunci API dengan scope dan outlet yang sama.
+// Kunci lama langsung tidak berlaku.
+func (s *PostgresStore) RotateAPIKey(ctx context.Context, id int) (*models.APIKey, error) {
+	key, prefix, keyHash, err := newAPIKey()
+	if err != nil {
+		log.Printf("[api-key-store] Error generate api key: %v", err)
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[api-key-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockActiveAPIKey(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := scanAPIKey(tx.QueryRowContext(ctx, `
+		UPDATE api_keys SET prefix = $1, key_hash = $2, rotated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+		RETURNING `+apiKeyColumns, prefix, keyHash, id))
+	if err != nil {
+		log.Printf("[api-key-store] Error RotateAPIKey: %v", err)
+		return nil, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityAPIKey, models.AuditActionUpdate, id, before, k); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[api-key-store] Error commit RotateAPIKey: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[api-key-store] API key rotated id=%d prefix=%s", k.ID, k.Prefix)
+	k.Key = key
+	return &k, nil
+}
+
+// AuthenticateAPIKey mengembalikan kunci API yang belum dicabut sekaligus
+// mencatat waktu terakhir dipakai.
+func (s *PostgresStore) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	k, err := scanAPIKey(s.db.QueryRowContext(ctx, `
+		UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns, hashToken(key)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: invalid or revoked api key", ErrUnauthorized)
+	}
+	if err != nil {
+		log.Printf("[api-key-store] Error AuthenticateAPIKey: %v", err)
+		return nil, err
+	}
+	return &k, nil
+}
+
+// lockActiveAPIKey mengunci kunci API yang belum dicabut, membedakan kunci
+// yang tidak ada dari kunci yang sudah dicabut.
+func lockActiveAPIKey(ctx context.Context, tx *sql.Tx, id int) (models.APIKey, error) {
+	k, err := scanAPIKey(tx.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.APIKey{}, fmt.Errorf("%w: api key id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[api-key-store] Error lock api key: %v", err)
+		return models.APIKey{}, err
+	}
+	if k.RevokedAt != nil {
+		return models.APIKey{}, fmt.Errorf("%w: api key %d is revoked", ErrConflict, id)
+	}
+	return k, nil
+}
+
+// scanAPIKey membaca satu baris kunci API.
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var k models.APIKey
+	var lastUsedAt, rotatedAt, revokedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, pq.Array(&k.Scopes), &k.Role, &k.Outlet, &k.CreatedBy, &k.CreatedAt,
+		&lastUsedAt, &rotatedAt, &revokedAt)
+	if err != nil {
+		return k, err
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if rotatedAt.Valid {
+		k.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, nil
+}
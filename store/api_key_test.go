@@ -0,0 +1,46 @@
+package store
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestPrepareAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    []string
+		wantErr bool
+	}{
+		{"sorted and deduplicated", []string{"produk:read", " checkout:write", "produk:read"}, []string{"checkout:write", "produk:read"}, false},
+		// Kunci tidak boleh mengelola akun, kunci API, atau persetujuan manajer.
+		{"user accounts", []string{"user:write"}, nil, true},
+		{"api keys", []string{"api-key:write"}, nil, true},
+		{"approvals", []string{"approval:write"}, nil, true},
+		{"unknown resource", []string{"gudang:read"}, nil, true},
+		{"unknown access", []string{"produk:delete"}, nil, true},
+		{"missing access", []string{"produk"}, nil, true},
+		{"one bad scope", []string{"produk:read", "user:read"}, nil, true},
+		{"empty", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := prepareAPIKey(models.CreateAPIKeyRequest{Name: "tablet-kasir-1", Scopes: tt.scopes})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("err = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !slices.Equal(req.Scopes, tt.want) {
+				t.Errorf("scopes = %v, want %v", req.Scopes, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// Batas panjang PIN persetujuan manajer.
+const (
+	minPINLength = 4
+	maxPINLength = 8
+)
+
+// validApprovalActions berisi aksi yang bisa disetujui manajer.
+var validApprovalActions = map[string]bool{
+	models.ApprovalActionCheckout:     true,
+	models.ApprovalActionVoid:         true,
+	models.ApprovalActionNoSale:       true,
+	models.ApprovalActionProdukUpdate: true,
+	models.ApprovalActionProdukDelete: true,
+}
+
+// Batas PIN persetujuan yang salah. Manajer atau pengguna yang mencoba PIN dan
+// gagal maxPINFailures kali berturut-turut (jeda antar-gagal kurang dari
+// pinLockout) dikunci selama pinLockout. Selama dikunci PIN tidak diperiksa
+// sama sekali, sehingga tebakan beruntun juga tidak membebani CPU dengan PBKDF2.
+const (
+	maxPINFailures = 5
+	pinLockout     = 15 * time.Minute
+)
+
+// approverRoles berisi role yang boleh menyetujui aksi kasir.
+var approverRoles = map[string]bool{
+	models.RoleOwner:   true,
+	models.RoleManager: true,
+}
+
+// approvalContextKey adalah key context untuk persetujuan manajer pada request.
+type approvalContextKey struct{}
+
+// WithApproval menyimpan persetujuan manajer di context. Store hanya memakai
+// persetujuan ini untuk aksi yang membutuhkannya dan mencatatnya bersama aksi
+// tersebut dalam database transaction yang sama.
+func WithApproval(ctx context.Context, a models.Approval) context.Context {
+	return context.WithValue(ctx, approvalContextKey{}, a)
+}
+
+// approvalFromContext mengembalikan persetujuan yang disimpan WithApproval.
+func approvalFromContext(ctx context.Context) (models.Approval, bool) {
+	a, ok := ctx.Value(approvalContextKey{}).(models.Approval)
+	return a, ok
+}
+
+// approvalFor mengembalikan persetujuan di context jika ditujukan untuk action.
+func approvalFor(ctx context.Context, action string) (models.Approval, bool) {
+	a, ok := approvalFromContext(ctx)
+	return a, ok && a.Action == action
+}
+
+// requireApproval mengembalikan persetujuan untuk action dari context beserta
+// detail alasan persetujuan dibutuhkan, atau ErrApprovalRequired jika tidak ada.
+func requireApproval(ctx context.Context, action, detail string) (models.Approval, error) {
+	a, ok := approvalFor(ctx, action)
+	if !ok {
+		if detail == "" {
+			return models.Approval{}, fmt.Errorf("%w: %s", ErrApprovalRequired, action)
+		}
+		return models.Approval{}, fmt.Errorf("%w: %s (%s)", ErrApprovalRequired, action, detail)
+	}
+	a.Detail = detail
+	return a, nil
+}
+
+// validateApprovalAction memastikan aksi token persetujuan dikenal.
+func validateApprovalAction(action string) error {
+	if !validApprovalActions[action] {
+		return fmt.Errorf("%w: unknown approval action %q", ErrInvalidInput, action)
+	}
+	return nil
+}
+
+// maxUsernameLength sama dengan panjang kolom users.username.
+const maxUsernameLength = 50
+
+// pinAttemptUsername memotong username yang dikirim bersama PIN agar muat di
+// penghitung gagal dan audit log; username yang lebih panjang pasti tidak ada.
+func pinAttemptUsername(username string) string {
+	if len(username) <= maxUsernameLength {
+		return username
+	}
+	return strings.ToValidUTF8(username[:maxUsernameLength], "")
+}
+
+// pinSubjects mengembalikan key penghitung gagal PIN untuk manajer yang
+// dituju dan pengguna yang mencoba (pelaku di context).
+func pinSubjects(ctx context.Context, username string) []string {
+	return []string{"manager:" + username, "caller:" + auditSourceFromContext(ctx).actor}
+}
+
+// errPINLocked adalah error untuk PIN yang sedang dikunci.
+func errPINLocked(username string) error {
+	return fmt.Errorf("%w: manager pin for %q is locked, try again later", ErrTooManyAttempts, username)
+}
+
+// validatePIN memastikan PIN hanya berisi 4-8 digit.
+func validatePIN(pin string) error {
+	if len(pin) < minPINLength || len(pin) > maxPINLength || strings.Trim(pin, "0123456789") != "" {
+		return fmt.Errorf("%w: pin must be %d-%d digits", ErrInvalidInput, minPINLength, maxPINLength)
+	}
+	return nil
+}
+
+// hashPIN membuat hash PIN baru. PIN kosong berarti PIN dihapus dan
+// menghasilkan hash kosong.
+func hashPIN(pin *string) (string, error) {
+	if pin == nil || *pin == "" {
+		return "", nil
+	}
+	return hashPassword(*pin)
+}
+
+// itemPrice mengembalikan harga per unit untuk item checkout dan alasan
+// persetujuan jika kasir mengganti harga katalog.
+func itemPrice(item models.CheckoutItem, catalogPrice int) (int, string, error) {
+	if item.PriceOverride == nil || *item.PriceOverride == catalogPrice {
+		return catalogPrice, "", nil
+	}
+	if *item.PriceOverride < 0 {
+		return 0, "", fmt.Errorf("%w: price_override for product %d cannot be negative", ErrInvalidInput, item.ProductID)
+	}
+	return *item.PriceOverride, fmt.Sprintf("price_override product_id=%d %d->%d", item.ProductID, catalogPrice, *item.PriceOverride), nil
+}
+
+// applyManualDiscount membagi potongan manual kasir ke details setelah promosi
+// dan voucher, lalu mengembalikan alasan persetujuan jika potongannya melebihi
+// batas persen dari subtotal sebelum potongan manual.
+func applyManualDiscount(details []models.TransactionDetail, req models.CheckoutRequest) (string, error) {
+	if req.ManualDiscount == 0 {
+		return "", nil
+	}
+	_, _, _, base := sumDetails(details)
+	if req.ManualDiscount < 0 || req.ManualDiscount > base {
+		return "", fmt.Errorf("%w: manual_discount must be between 0 and %d", ErrInvalidInput, base)
+	}
+	allocateCartDiscount(details, req.ManualDiscount)
+	if req.ManualDiscount*100 <= req.DiscountApprovalPercent*base {
+		return "", nil
+	}
+	return fmt.Sprintf("manual_discount %d of %d exceeds %d%%", req.ManualDiscount, base, req.DiscountApprovalPercent), nil
+}
+
+// checkoutApproval meminta persetujuan checkout jika ada alasan yang
+// membutuhkannya. Hasil nil berarti checkout tidak perlu persetujuan.
+func checkoutApproval(ctx context.Context, reasons []string) (*models.Approval, error) {
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	a, err := requireApproval(ctx, models.ApprovalActionCheckout, strings.Join(reasons, "; "))
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// approvalColumns adalah kolom approvals sesuai urutan scanApproval.
+const approvalColumns = "id, action, entity_id, detail, requested_by, COALESCE(approved_by, 0), approver_name, method, created_at"
+
+// VerifyManagerPIN memeriksa PIN persetujuan lalu mengembalikan manajer/owner
+// aktif pemiliknya. Username yang tidak ada dan PIN yang salah menghasilkan
+// error yang sama. Setiap PIN yang salah dihitung untuk manajer yang dituju dan
+// pengguna yang mencoba serta dicatat di audit log; setelah maxPINFailures kali
+// gagal keduanya dikunci dan request ditolak dengan ErrTooManyAttempts tanpa
+// memeriksa PIN.
+func (s *PostgresStore) VerifyManagerPIN(ctx context.Context, username, pin string) (*models.User, error) {
+	username = normalizeUsername(username)
+	attempt := pinAttemptUsername(username)
+	subjects := pinSubjects(ctx, attempt)
+
+	var locked bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM approval_pin_failures
+			WHERE subject IN ($1, $2) AND locked_until > CURRENT_TIMESTAMP
+		)
+	`, subjects[0], subjects[1]).Scan(&locked)
+	if err != nil {
+		log.Printf("[approval-store] Error check pin lockout: %v", err)
+		return nil, err
+	}
+	if locked {
+		failure := models.ManagerPINFailure{Caller: auditSourceFromContext(ctx).actor, Locked: true}
+		if err := insertAudit(ctx, s.db, models.AuditEntityManagerPIN, models.AuditActionFailed, attempt, nil, failure); err != nil {
+			return nil, err
+		}
+		log.Printf("[approval-store] Manager pin locked username=%s caller=%s", attempt, failure.Caller)
+		return nil, errPINLocked(attempt)
+	}
+
+	var u models.User
+	var pinHash sql.NullString
+	err = s.db.QueryRowContext(ctx, "SELECT "+userColumns+", pin_hash FROM users WHERE username = $1", username).
+		Scan(&u.ID, &u.Username, &u.Role, &u.Active, &u.CreatedAt, &u.UpdatedAt, &pinHash)
+	if err == sql.ErrNoRows || (err == nil && !pinHash.Valid) {
+		verifyPassword(dummyPasswordHash(), pin)
+		return nil, s.recordPINFailure(ctx, attempt, subjects)
+	}
+	if err != nil {
+		log.Printf("[approval-store] Error get user for pin: %v", err)
+		return nil, err
+	}
+	if !verifyPassword(pinHash.String, pin) || !u.Active || !approverRoles[u.Role] {
+		return nil, s.recordPINFailure(ctx, attempt, subjects)
+	}
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM approval_pin_failures WHERE subject IN ($1, $2)", subjects[0], subjects[1]); err != nil {
+		log.Printf("[approval-store] Error reset pin failures: %v", err)
+		return nil, err
+	}
+	return &u, nil
+}
+
+// recordPINFailure menambah penghitung gagal PIN untuk setiap subject, mengunci
+// subject yang mencapai maxPINFailures, lalu mencatat percobaan di audit log
+// dalam database transaction yang sama. Hasilnya adalah error yang dikembalikan
+// ke pemanggil: ErrUnauthorized, atau ErrTooManyAttempts jika percobaan ini
+// membuat PIN dikunci.
+func (s *PostgresStore) recordPINFailure(ctx context.Context, username string, subjects []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[approval-store] Error begin tx: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	failure := models.ManagerPINFailure{Caller: auditSourceFromContext(ctx).actor}
+	for i, subject := range subjects {
+		// Gagal yang lebih lama dari pinLockout tidak dihitung lagi.
+		var failures int
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO approval_pin_failures (subject, failures, last_failure_at)
+			VALUES ($1, 1, CURRENT_TIMESTAMP)
+			ON CONFLICT (subject) DO UPDATE SET
+				failures = CASE
+					WHEN approval_pin_failures.last_failure_at < CURRENT_TIMESTAMP - make_interval(secs => $2) THEN 1
+					ELSE approval_pin_failures.failures + 1
+				END,
+				last_failure_at = CURRENT_TIMESTAMP
+			RETURNING failures
+		`, subject, pinLockout.Seconds()).Scan(&failures)
+		if err != nil {
+			log.Printf("[approval-store] Error record pin failure subject=%s: %v", subject, err)
+			return err
+		}
+		if i == 0 {
+			failure.Failures = failures
+		}
+		if failures < maxPINFailures {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE approval_pin_failures
+			SET failures = 0, locked_until = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			WHERE subject = $1
+		`, subject, pinLockout.Seconds())
+		if err != nil {
+			log.Printf("[approval-store] Error lock pin subject=%s: %v", subject, err)
+			return err
+		}
+		failure.Locked = true
+		log.Printf("[approval-store] Manager pin locked subject=%s until=+%s", subject, pinLockout)
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityManagerPIN, models.AuditActionFailed, username, nil, failure); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("[approval-store] Error commit pin failure: %v", err)
+		return err
+	}
+
+	if failure.Locked {
+		return errPINLocked(username)
+	}
+	return fmt.Errorf("%w: invalid manager pin", ErrUnauthorized)
+}
+
+// CreateApprovalToken membuat token persetujuan sekali pakai untuk action
+// atas nama manajer yang berlaku selama ttl.
+func (s *PostgresStore) CreateApprovalToken(ctx context.Context, managerID int, action string, ttl time.Duration) (*models.ApprovalToken, error) {
+	if err := validateApprovalAction(action); err != nil {
+		return nil, err
+	}
+	token, tokenHash, err := newSessionToken()
+	if err != nil {
+		log.Printf("[approval-store] Error generate approval token: %v", err)
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	// Bersihkan token kedaluwarsa sekalian membuat token baru.
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM approval_tokens WHERE expires_at <= CURRENT_TIMESTAMP"); err != nil {
+		log.Printf("[approval-store] Error delete expired approval tokens: %v", err)
+		return nil, err
+	}
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO approval_tokens (token_hash, manager_id, action, expires_at) VALUES ($1, $2, $3, $4)",
+		tokenHash, managerID, action, expiresAt)
+	if err != nil {
+		log.Printf("[approval-store] Error insert approval token: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[approval-store] Approval token created manager_id=%d action=%s", managerID, action)
+	return &models.ApprovalToken{Token: token, Action: action, ExpiresAt: expiresAt}, nil
+}
+
+// RedeemApprovalToken memakai token persetujuan untuk action lalu mengembalikan
+// manajer pembuatnya. Token langsung dihapus sehingga tidak bisa dipakai lagi,
+// termasuk jika aksi yang disetujui kemudian gagal.
+func (s *PostgresStore) RedeemApprovalToken(ctx context.Context, token, action string) (*models.User, error) {
+	u, err := scanUser(s.db.QueryRowContext(ctx, `
+		WITH redeemed AS (
+			DELETE FROM approval_tokens
+			WHERE token_hash = $1 AND action = $2 AND expires_at > CURRENT_TIMESTAMP
+			RETURNING manager_id
+		)
+		SELECT u.id, u.username, u.role, u.active, u.created_at, u.updated_at
+		FROM redeemed r
+		JOIN users u ON u.id = r.manager_id
+		WHERE u.active AND u.role IN ($3, $4)
+	`, hashToken(token), action, models.RoleOwner, models.RoleManager))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: invalid or expired approval token", ErrUnauthorized)
+	}
+	if err != nil {
+		log.Printf("[approval-store] Error RedeemApprovalToken: %v", err)
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetAllApprovals mengembalikan catatan persetujuan terbaru lebih dulu.
+func (s *PostgresStore) GetAllApprovals(ctx context.Context, filter ApprovalFilter) ([]models.Approval, error) {
+	query := "SELECT " + approvalColumns + " FROM approvals"
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+
+	if filter.EntityID > 0 {
+		args = append(args, filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("[approval-store] Error GetAllApprovals: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []models.Approval{}
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			log.Printf("[approval-store] Error scanning approval row: %v", err)
+			continue
+		}
+		approvals = append(approvals, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[approval-store] Error iterating approval rows: %v", err)
+		return nil, err
+	}
+
+	return approvals, nil
+}
+
+// insertApproval mencatat persetujuan untuk entityID, dipanggil dalam database
+// transaction yang sama dengan aksi yang disetujui.
+func insertApproval(ctx context.Context, q queryer, a models.Approval, entityID int) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO approvals (action, entity_id, detail, requested_by, approved_by, approver_name, method)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, a.Action, entityID, a.Detail, a.RequestedBy, a.ApprovedBy, a.ApproverName, a.Method)
+	if err != nil {
+		log.Printf("[approval-store] Error insert approval: %v", err)
+		return err
+	}
+
+	log.Printf("[approval-store] Approval recorded action=%s entity_id=%d approved_by=%d method=%s",
+		a.Action, entityID, a.ApprovedBy, a.Method)
+	return nil
+}
+
+// scanApproval membaca satu baris persetujuan.
+func scanApproval(row rowScanner) (models.Approval, error) {
+	var a models.Approval
+	err := row.Scan(&a.ID, &a.Action, &a.EntityID, &a.Detail, &a.RequestedBy, &a.ApprovedBy, &a.ApproverName,
+		&a.Method, &a.CreatedAt)
+	return a, err
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestVerifyManagerPINLockout(t *testing.T) {
+	s := NewMemoryStore()
+	manager, err := s.CreateUser(context.Background(), models.CreateUserRequest{Username: "budi", Password: "rahasia123", Role: models.RoleManager})
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	pin := "1234"
+	if _, err := s.UpdateUser(context.Background(), manager.ID, models.UpdateUserRequest{PIN: &pin}); err != nil {
+		t.Fatalf("set pin: %v", err)
+	}
+
+	ctx := WithActor(context.Background(), "ani", "req-1")
+	for i := 1; i < maxPINFailures; i++ {
+		if _, err := s.VerifyManagerPIN(ctx, "budi", "0000"); !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("attempt %d err = %v, want ErrUnauthorized", i, err)
+		}
+	}
+	if _, err := s.VerifyManagerPIN(ctx, "budi", "0000"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("attempt %d err = %v, want ErrTooManyAttempts", maxPINFailures, err)
+	}
+
+	// Selama dikunci PIN yang benar juga ditolak, termasuk dari kasir lain.
+	if _, err := s.VerifyManagerPIN(ctx, "budi", pin); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("correct pin while locked err = %v, want ErrTooManyAttempts", err)
+	}
+	other := WithActor(context.Background(), "citra", "req-2")
+	if _, err := s.VerifyManagerPIN(other, "budi", pin); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("other caller while manager locked err = %v, want ErrTooManyAttempts", err)
+	}
+	// Kasir yang dikunci juga tidak bisa menebak PIN manajer lain.
+	if _, err := s.VerifyManagerPIN(ctx, "dewi", "1111"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("locked caller on other manager err = %v, want ErrTooManyAttempts", err)
+	}
+
+	entries, err := s.GetAuditLog(context.Background(), AuditFilter{Entity: models.AuditEntityManagerPIN})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+	if len(entries) != maxPINFailures+3 {
+		t.Fatalf("audit entries = %d, want %d", len(entries), maxPINFailures+3)
+	}
+	for _, e := range entries {
+		if e.Action != models.AuditActionFailed {
+			t.Errorf("audit action = %q, want %q", e.Action, models.AuditActionFailed)
+		}
+	}
+}
+
+func TestVerifyManagerPINResetsFailures(t *testing.T) {
+	s := NewMemoryStore()
+	manager, err := s.CreateUser(context.Background(), models.CreateUserRequest{Username: "budi", Password: "rahasia123", Role: models.RoleManager})
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	pin := "1234"
+	if _, err := s.UpdateUser(context.Background(), manager.ID, models.UpdateUserRequest{PIN: &pin}); err != nil {
+		t.Fatalf("set pin: %v", err)
+	}
+
+	ctx := WithActor(context.Background(), "ani", "req-1")
+	for round := 0; round < 2; round++ {
+		for i := 1; i < maxPINFailures; i++ {
+			if _, err := s.VerifyManagerPIN(ctx, "budi", "0000"); !errors.Is(err, ErrUnauthorized) {
+				t.Fatalf("round %d attempt %d err = %v, want ErrUnauthorized", round, i, err)
+			}
+		}
+		// PIN yang benar sebelum batas tercapai mengosongkan penghitung.
+		if u, err := s.VerifyManagerPIN(ctx, "budi", pin); err != nil || u.ID != manager.ID {
+			t.Fatalf("round %d correct pin = %+v, %v", round, u, err)
+		}
+	}
+}
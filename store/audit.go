@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kasir-api/models"
+)
+
+// auditSystemActor adalah pelaku audit untuk perubahan di luar request HTTP,
+// misalnya dari cmd/createuser.
+const auditSystemActor = "system"
+
+// auditContextKey adalah key context untuk pelaku dan ID request audit.
+type auditContextKey struct{}
+
+// auditSource berisi pelaku dan ID request yang disimpan WithActor.
+type auditSource struct {
+	actor     string
+	requestID string
+}
+
+// WithActor menyimpan pelaku dan ID request di context. Setiap perubahan
+// master data di store mencatat keduanya di audit log.
+func WithActor(ctx context.Context, actor, requestID string) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditSource{actor: actor, requestID: requestID})
+}
+
+// auditSourceFromContext mengembalikan pelaku dan ID request dari context, atau
+// pelaku system jika context tidak membawanya.
+func auditSourceFromContext(ctx context.Context) auditSource {
+	src, ok := ctx.Value(auditContextKey{}).(auditSource)
+	if !ok || src.actor == "" {
+		src.actor = auditSystemActor
+	}
+	return src
+}
+
+// newAuditEntry menyusun catatan audit untuk satu perubahan. before bernilai
+// nil untuk create dan after bernilai nil untuk delete.
+func newAuditEntry(ctx context.Context, entity, action string, entityID any, before, after any) (models.AuditEntry, error) {
+	src := auditSourceFromContext(ctx)
+	e := models.AuditEntry{
+		Actor:     src.actor,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  fmt.Sprint(entityID),
+		RequestID: src.requestID,
+	}
+
+	var err error
+	if e.Before, err = marshalAuditData(before); err != nil {
+		return models.AuditEntry{}, err
+	}
+	if e.After, err = marshalAuditData(after); err != nil {
+		return models.AuditEntry{}, err
+	}
+	return e, nil
+}
+
+// marshalAuditData mengubah data menjadi JSON untuk audit log. nil tetap nil.
+func marshalAuditData(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit data: %w", err)
+	}
+	return data, nil
+}
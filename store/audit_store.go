@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// auditColumns adalah kolom audit_log sesuai urutan scanAuditEntry.
+const auditColumns = "id, actor, action, entity, entity_id, before, after, request_id, created_at"
+
+// GetAuditLog mengembalikan catatan audit dalam rentang waktu [filter.Start,
+// filter.End), terbaru lebih dulu.
+func (s *PostgresStore) GetAuditLog(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	query := "SELECT " + auditColumns + " FROM audit_log"
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Entity != "" {
+		args = append(args, filter.Entity)
+		conditions = append(conditions, fmt.Sprintf("entity = $%d", len(args)))
+	}
+
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		conditions = append(conditions, fmt.Sprintf("actor = $%d", len(args)))
+	}
+
+	if !filter.Start.IsZero() {
+		args = append(args, filter.Start)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if !filter.End.IsZero() {
+		args = append(args, filter.End)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("[audit-store] Error GetAuditLog: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			log.Printf("[audit-store] Error scanning audit row: %v", err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[audit-store] Error iterating audit rows: %v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// insertAudit mencatat perubahan master data di audit_log, dipanggil dalam
+// database transaction yang sama dengan perubahannya. before bernilai nil untuk
+// create dan after bernilai nil untuk delete.
+func insertAudit(ctx context.Context, q queryer, entity, action string, entityID any, before, after any) error {
+	e, err := newAuditEntry(ctx, entity, action, entityID, before, after)
+	if err != nil {
+		log.Printf("[audit-store] Error build audit entry: %v", err)
+		return err
+	}
+
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO audit_log (actor, action, entity, entity_id, before, after, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, e.Actor, e.Action, e.Entity, e.EntityID, nullableJSON(e.Before), nullableJSON(e.After), e.RequestID)
+	if err != nil {
+		log.Printf("[audit-store] Error insert audit: %v", err)
+		return err
+	}
+
+	log.Printf("[audit-store] Audit recorded actor=%s action=%s entity=%s entity_id=%s request_id=%s",
+		e.Actor, e.Action, e.Entity, e.EntityID, e.RequestID)
+	return nil
+}
+
+// nullableJSON mengubah JSON kosong menjadi NULL.
+func nullableJSON(data []byte) sql.NullString {
+	return sql.NullString{String: string(data), Valid: len(data) > 0}
+}
+
+// scanAuditEntry membaca satu baris audit.
+func scanAuditEntry(row rowScanner) (models.AuditEntry, error) {
+	var e models.AuditEntry
+	var before, after []byte
+	err := row.Scan(&e.ID, &e.Actor, &e.Action, &e.Entity, &e.EntityID, &before, &after, &e.RequestID, &e.CreatedAt)
+	if err != nil {
+		return models.AuditEntry{}, err
+	}
+	e.Before = before
+	e.After = after
+	return e, nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestMemoryStoreAuditsMasterData(t *testing.T) {
+	s := NewMemoryStore()
+	ctx := WithActor(context.Background(), "budi", "req-1")
+
+	p, err := s.Add(ctx, models.Produk{Nama: "Kopi Susu", Harga: 15000, HargaBeli: 9000, Stok: 5})
+	if err != nil {
+		t.Fatalf("add produk: %v", err)
+	}
+	if _, err := s.Update(ctx, p.ID, models.Produk{Nama: "Kopi Susu", Harga: 17000, HargaBeli: 9000}); err != nil {
+		t.Fatalf("update produk: %v", err)
+	}
+	if err := s.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("delete produk: %v", err)
+	}
+	c, err := s.AddCustomer(ctx, models.Customer{Nama: "Sari"})
+	if err != nil {
+		t.Fatalf("add customer: %v", err)
+	}
+	// Perubahan yang gagal tidak dicatat.
+	if _, err := s.Update(ctx, p.ID, models.Produk{Nama: "Kopi Susu"}); err == nil {
+		t.Fatalf("update deleted produk: want error")
+	}
+
+	entries, err := s.GetAuditLog(ctx, AuditFilter{})
+	if err != nil {
+		t.Fatalf("get audit log: %v", err)
+	}
+	// Audit log urut terbaru lebih dulu.
+	want := []struct {
+		entity, action      string
+		id                  int
+		hasBefore, hasAfter bool
+	}{
+		{models.AuditEntityCustomer, models.AuditActionCreate, c.ID, false, true},
+		{models.AuditEntityProduk, models.AuditActionDelete, p.ID, true, false},
+		{models.AuditEntityProduk, models.AuditActionUpdate, p.ID, true, true},
+		{models.AuditEntityProduk, models.AuditActionCreate, p.ID, false, true},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %d entries", entries, len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Entity != w.entity || e.Action != w.action || e.EntityID != fmt.Sprint(w.id) {
+			t.Errorf("entry %d = %s %s %s, want %s %s %d", i, e.Entity, e.Action, e.EntityID, w.entity, w.action, w.id)
+		}
+		if e.Actor != "budi" || e.RequestID != "req-1" {
+			t.Errorf("entry %d actor/request = %q/%q, want %q/%q", i, e.Actor, e.RequestID, "budi", "req-1")
+		}
+		if (e.Before != nil) != w.hasBefore || (e.After != nil) != w.hasAfter {
+			t.Errorf("entry %d before/after = %s/%s, want present %t/%t", i, e.Before, e.After, w.hasBefore, w.hasAfter)
+		}
+	}
+
+	// Update mencatat data sebelum dan sesudah perubahan.
+	var before, after models.Produk
+	if err := json.Unmarshal(entries[2].Before, &before); err != nil {
+		t.Fatalf("decode before: %v", err)
+	}
+	if err := json.Unmarshal(entries[2].After, &after); err != nil {
+		t.Fatalf("decode after: %v", err)
+	}
+	if before.Harga != 15000 || after.Harga != 17000 {
+		t.Errorf("harga before/after = %d/%d, want 15000/17000", before.Harga, after.Harga)
+	}
+
+	produk, err := s.GetAuditLog(ctx, AuditFilter{Entity: models.AuditEntityProduk})
+	if err != nil {
+		t.Fatalf("get produk audit log: %v", err)
+	}
+	if len(produk) != 3 {
+		t.Errorf("produk entries = %d, want 3", len(produk))
+	}
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// CheckoutItemsError berisi kesalahan per baris items checkout. Err adalah
+// sentinel yang bisa dicek dengan errors.Is (ErrInvalidInput atau
+// ErrInsufficientStock), Lines menunjuk baris request yang harus diperbaiki.
+type CheckoutItemsError struct {
+	Err   error
+	Lines []models.CheckoutLineError
+}
+
+func (e *CheckoutItemsError) Error() string {
+	msgs := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		msgs[i] = fmt.Sprintf("line %d %s: %s", l.Line, l.Field, l.Message)
+	}
+	return fmt.Sprintf("%v: %s", e.Err, strings.Join(msgs, "; "))
+}
+
+func (e *CheckoutItemsError) Unwrap() error {
+	return e.Err
+}
+
+// add mencatat kesalahan satu baris (line mulai dari 1). ErrInvalidInput
+// diutamakan dari ErrInsufficientStock karena request yang salah tetap ditolak
+// walau stoknya cukup.
+func (e *CheckoutItemsError) add(err error, line, productID int, field, message string) {
+	if e.Err == nil || errors.Is(err, ErrInvalidInput) {
+		e.Err = err
+	}
+	e.Lines = append(e.Lines, models.CheckoutLineError{Line: line, ProductID: productID, Field: field, Message: message})
+}
+
+// errOrNil mengembalikan e jika ada baris yang salah, selain itu nil.
+func (e *CheckoutItemsError) errOrNil() error {
+	if len(e.Lines) == 0 {
+		return nil
+	}
+	return e
+}
+
+// normalizeCheckoutItems menggabungkan baris dengan product_id yang sama dan
+// memvalidasi setiap baris: product_id dan quantity harus positif, jumlah per
+// produk tidak boleh melebihi maxQuantity (0 berarti tanpa batas), dan baris
+// produk yang sama harus memakai price_override yang sama. Selain items hasil
+// gabungan, dikembalikan juga nomor baris asal (mulai dari 1) untuk setiap item
+// agar error stok berikutnya tetap menunjuk baris yang dikirim kasir.
+func normalizeCheckoutItems(items []models.CheckoutItem, maxQuantity int) ([]models.CheckoutItem, []int, error) {
+	if len(items) == 0 {
+		return nil, nil, fmt.Errorf("%w: items cannot be empty", ErrInvalidInput)
+	}
+
+	merged := make([]models.CheckoutItem, 0, len(items))
+	lines := make([]int, 0, len(items))
+	index := make(map[int]int)
+	var itemsErr CheckoutItemsError
+	for i, item := range items {
+		line := i + 1
+		if item.ProductID <= 0 {
+			itemsErr.add(ErrInvalidInput, line, item.ProductID, "product_id", "must be greater than zero")
+			continue
+		}
+		if item.Quantity <= 0 {
+			itemsErr.add(ErrInvalidInput, line, item.ProductID, "quantity", "must be greater than zero")
+			continue
+		}
+		// Cek per baris juga sebelum digabung agar penjumlahan tidak overflow.
+		if maxQuantity > 0 && item.Quantity > maxQuantity {
+			itemsErr.add(ErrInvalidInput, line, item.ProductID, "quantity",
+				fmt.Sprintf("quantity %d exceeds maximum %d", item.Quantity, maxQuantity))
+			continue
+		}
+		if item.PriceOverride != nil && *item.PriceOverride < 0 {
+			itemsErr.add(ErrInvalidInput, line, item.ProductID, "price_override", "cannot be negative")
+			continue
+		}
+
+		j, ok := index[item.ProductID]
+		if !ok {
+			index[item.ProductID] = len(merged)
+			merged = append(merged, item)
+			lines = append(lines, line)
+			continue
+		}
+		if !samePriceOverride(merged[j].PriceOverride, item.PriceOverride) {
+			itemsErr.add(ErrInvalidInput, line, item.ProductID, "price_override",
+				fmt.Sprintf("differs from line %d for the same product", lines[j]))
+			continue
+		}
+		merged[j].Quantity += item.Quantity
+	}
+
+	if maxQuantity > 0 {
+		for j, item := range merged {
+			if item.Quantity > maxQuantity {
+				itemsErr.add(ErrInvalidInput, lines[j], item.ProductID, "quantity",
+					fmt.Sprintf("total quantity %d exceeds maximum %d", item.Quantity, maxQuantity))
+			}
+		}
+	}
+
+	if err := itemsErr.errOrNil(); err != nil {
+		return nil, nil, err
+	}
+	return merged, lines, nil
+}
+
+// samePriceOverride melaporkan apakah dua price_override bernilai sama (keduanya kosong juga sama).
+func samePriceOverride(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
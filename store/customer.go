@@ -0,0 +1,45 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// NormalizePhone menyeragamkan nomor telepon menjadi angka saja dengan awalan 0,
+// sehingga "+62 812-3456" dan "08123456" dianggap nomor yang sama.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "62") {
+		digits = "0" + digits[2:]
+	}
+	return digits
+}
+
+// prepareCustomer memvalidasi data pelanggan lalu merapikan isinya sebelum disimpan.
+func prepareCustomer(c models.Customer) (models.Customer, error) {
+	c.Nama = strings.TrimSpace(c.Nama)
+	c.Email = strings.TrimSpace(c.Email)
+	c.NomorMember = strings.TrimSpace(c.NomorMember)
+	c.Telepon = NormalizePhone(c.Telepon)
+	// Saldo poin hanya berubah lewat ledger, bukan lewat data pelanggan.
+	c.PointsBalance = 0
+
+	if c.Nama == "" {
+		return models.Customer{}, fmt.Errorf("%w: nama is required", ErrInvalidInput)
+	}
+	if c.Email != "" && !strings.Contains(c.Email, "@") {
+		return models.Customer{}, fmt.Errorf("%w: invalid email %q", ErrInvalidInput, c.Email)
+	}
+	if len(c.Telepon) > 20 {
+		return models.Customer{}, fmt.Errorf("%w: telepon is too long", ErrInvalidInput)
+	}
+	return c, nil
+}
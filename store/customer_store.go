@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// customerColumns adalah kolom customers sesuai urutan scanCustomer.
+const customerColumns = "id, nama, telepon, email, nomor_member, points_balance, created_at"
+
+// AddCustomer menambahkan pelanggan baru.
+func (s *PostgresStore) AddCustomer(ctx context.Context, c models.Customer) (models.Customer, error) {
+	c, err := prepareCustomer(c)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[customer-store] Error begin transaction: %v", err)
+		return models.Customer{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO customers (nama, telepon, email, nomor_member) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		c.Nama, c.Telepon, c.Email, c.NomorMember,
+	).Scan(&c.ID, &c.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.Customer{}, fmt.Errorf("%w: telepon or nomor_member already registered", ErrConflict)
+	}
+	if err != nil {
+		log.Printf("[customer-store] Error AddCustomer: %v", err)
+		return models.Customer{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityCustomer, models.AuditActionCreate, c.ID, nil, c); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[customer-store] Error commit AddCustomer: %v", err)
+		return models.Customer{}, err
+	}
+
+	return c, nil
+}
+
+// GetAllCustomers mengembalikan semua pelanggan, atau hanya pelanggan dengan
+// nomor telepon phone jika diisi.
+func (s *PostgresStore) GetAllCustomers(ctx context.Context, phone string) ([]models.Customer, error) {
+	query := "SELECT " + customerColumns + " FROM customers"
+	args := []interface{}{}
+	if phone != "" {
+		query += " WHERE telepon = $1"
+		args = append(args, NormalizePhone(phone))
+	}
+	query += " ORDER BY id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("[customer-store] Error GetAllCustomers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []models.Customer{}
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			log.Printf("[customer-store] Error scanning customer row: %v", err)
+			continue
+		}
+		customers = append(customers, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[customer-store] Error iterating customer rows: %v", err)
+		return nil, err
+	}
+
+	return customers, nil
+}
+
+// GetCustomerByID mengembalikan satu pelanggan berdasarkan ID.
+func (s *PostgresStore) GetCustomerByID(ctx context.Context, id int) (models.Customer, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+customerColumns+" FROM customers WHERE id = $1", id)
+	c, err := scanCustomer(row)
+	if err == sql.ErrNoRows {
+		return models.Customer{}, fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[customer-store] Error GetCustomerByID: %v", err)
+		return models.Customer{}, err
+	}
+	return c, nil
+}
+
+// UpdateCustomer mengganti data pelanggan berdasarkan ID.
+func (s *PostgresStore) UpdateCustomer(ctx context.Context, id int, c models.Customer) (models.Customer, error) {
+	c, err := prepareCustomer(c)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[customer-store] Error begin transaction: %v", err)
+		return models.Customer{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanCustomer(tx.QueryRowContext(ctx, "SELECT "+customerColumns+" FROM customers WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.Customer{}, fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[customer-store] Error lock customer: %v", err)
+		return models.Customer{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE customers SET nama = $1, telepon = $2, email = $3, nomor_member = $4
+		WHERE id = $5
+		RETURNING id, points_balance, created_at
+	`, c.Nama, c.Telepon, c.Email, c.NomorMember, id).Scan(&c.ID, &c.PointsBalance, &c.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.Customer{}, fmt.Errorf("%w: telepon or nomor_member already registered", ErrConflict)
+	}
+	if err != nil {
+		log.Printf("[customer-store] Error UpdateCustomer: %v", err)
+		return models.Customer{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityCustomer, models.AuditActionUpdate, id, before, c); err != nil {
+		return models.Customer{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[customer-store] Error commit UpdateCustomer: %v", err)
+		return models.Customer{}, err
+	}
+
+	return c, nil
+}
+
+// DeleteCustomer menghapus pelanggan berdasarkan ID. Transaksi lama tetap ada
+// tanpa referensi pelanggan, sedangkan ledger poinnya ikut terhapus.
+func (s *PostgresStore) DeleteCustomer(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[customer-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := scanCustomer(tx.QueryRowContext(ctx, "DELETE FROM customers WHERE id = $1 RETURNING "+customerColumns, id))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[customer-store] Error DeleteCustomer: %v", err)
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityCustomer, models.AuditActionDelete, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[customer-store] Error commit DeleteCustomer: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetCustomerTransactions mengembalikan riwayat transaksi satu pelanggan, terbaru lebih dulu.
+func (s *PostgresStore) GetCustomerTransactions(ctx context.Context, id int) ([]models.Transaction, error) {
+	if _, err := s.GetCustomerByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetAllTransactions(ctx, TransactionFilter{CustomerID: id})
+}
+
+// scanCustomer membaca satu baris pelanggan.
+func scanCustomer(row rowScanner) (models.Customer, error) {
+	var c models.Customer
+	err := row.Scan(&c.ID, &c.Nama, &c.Telepon, &c.Email, &c.NomorMember, &c.PointsBalance, &c.CreatedAt)
+	return c, err
+}
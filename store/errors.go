@@ -0,0 +1,55 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Error sentinel yang bisa dicek handler dengan errors.Is untuk memilih status HTTP.
+var (
+	// ErrInvalidPayment menandakan data pembayaran checkout tidak valid.
+	ErrInvalidPayment = errors.New("invalid payment")
+	// ErrUnderpaid menandakan jumlah bayar kurang dari total belanja.
+	ErrUnderpaid = errors.New("payment is less than total amount")
+	// ErrNotFound menandakan data yang diminta tidak ada.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidReversal menandakan request void/refund tidak valid.
+	ErrInvalidReversal = errors.New("invalid reversal request")
+	// ErrReversalNotAllowed menandakan status transaksi tidak mengizinkan void/refund.
+	ErrReversalNotAllowed = errors.New("reversal not allowed")
+	// ErrInvalidVoucher menandakan voucher tidak ada atau tidak bisa dipakai untuk checkout ini.
+	ErrInvalidVoucher = errors.New("invalid voucher")
+	// ErrInvalidInput menandakan data request tidak valid untuk operasi store.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrConflict menandakan operasi bertabrakan dengan status data saat ini.
+	ErrConflict = errors.New("conflict")
+	// ErrUnauthorized menandakan kredensial atau token sesi tidak valid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrTooManyAttempts menandakan percobaan dikunci sementara karena terlalu sering gagal.
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+	// ErrApprovalRequired menandakan aksi butuh persetujuan manajer yang tidak ada di request.
+	ErrApprovalRequired = errors.New("manager approval required")
+	// ErrIdempotencyMismatch menandakan Idempotency-Key dipakai ulang untuk request yang berbeda.
+	ErrIdempotencyMismatch = errors.New("idempotency key reused with a different request")
+	// ErrInsufficientStock menandakan stok produk tidak cukup untuk penjualan.
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// isUniqueViolation melaporkan apakah err berasal dari pelanggaran unique constraint PostgreSQL.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation melaporkan apakah err berasal dari pelanggaran foreign key PostgreSQL.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// isCheckViolation melaporkan apakah err berasal dari pelanggaran check constraint PostgreSQL.
+func isCheckViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23514"
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"kasir-api/models"
+)
+
+// Status klaim Idempotency-Key checkout.
+const (
+	idempotencyStatusProcessing = "processing"
+	idempotencyStatusCompleted  = "completed"
+)
+
+// idempotencyClaimTimeout adalah lama klaim processing dianggap masih berjalan.
+// Klaim yang lebih lama (misalnya server mati di tengah checkout) boleh diambil
+// alih oleh request berikutnya dengan body yang sama.
+const idempotencyClaimTimeout = 2 * time.Minute
+
+// checkoutRequestHash mengembalikan hash SHA-256 dari request checkout yang
+// sudah di-decode, sehingga perbedaan spasi atau urutan field JSON tidak
+// dianggap request berbeda.
+func checkoutRequestHash(req models.CheckoutRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("marshal checkout request: %w", err)
+	}
+	return hashToken(string(data)), nil
+}
+
+// idempotencyMismatchError mengembalikan error untuk key yang dipakai ulang dengan body berbeda.
+func idempotencyMismatchError(key string) error {
+	return fmt.Errorf("%w: %q", ErrIdempotencyMismatch, key)
+}
+
+// idempotencyInProgressError mengembalikan error untuk key yang checkout-nya masih berjalan.
+func idempotencyInProgressError(key string) error {
+	return fmt.Errorf("%w: checkout with idempotency key %q is still in progress", ErrConflict, key)
+}
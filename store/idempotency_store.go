@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// ClaimCheckoutKey mengklaim req.IdempotencyKey dalam req.IdempotencyScope
+// sebelum checkout berjalan.
+// Hasil nil berarti key berhasil diklaim dan checkout boleh dilanjutkan. Key
+// yang sudah selesai mengembalikan transaksi yang tersimpan. Key dengan body
+// berbeda menghasilkan ErrIdempotencyMismatch, dan key yang checkout-nya masih
+// berjalan menghasilkan ErrConflict.
+func (s *PostgresStore) ClaimCheckoutKey(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	requestHash, err := checkoutRequestHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Klaim baru, atau ambil alih klaim processing yang sudah kedaluwarsa untuk body yang sama.
+	var claimed string
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO checkout_idempotency_keys (scope, key, request_hash) VALUES ($1, $2, $3)
+		ON CONFLICT (scope, key) DO UPDATE SET claimed_at = CURRENT_TIMESTAMP
+		WHERE checkout_idempotency_keys.status = $4
+			AND checkout_idempotency_keys.request_hash = EXCLUDED.request_hash
+			AND checkout_idempotency_keys.claimed_at < CURRENT_TIMESTAMP - make_interval(secs => $5)
+		RETURNING key
+	`, req.IdempotencyScope, req.IdempotencyKey, requestHash, idempotencyStatusProcessing,
+		int(idempotencyClaimTimeout.Seconds())).Scan(&claimed)
+	if err == nil {
+		log.Printf("[idempotency-store] Checkout key claimed scope=%s key=%q", req.IdempotencyScope, req.IdempotencyKey)
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("[idempotency-store] Error claim checkout key: %v", err)
+		return nil, err
+	}
+
+	var storedHash, status string
+	var transactionID sql.NullInt64
+	err = s.db.QueryRowContext(ctx,
+		"SELECT request_hash, status, transaction_id FROM checkout_idempotency_keys WHERE scope = $1 AND key = $2",
+		req.IdempotencyScope, req.IdempotencyKey).Scan(&storedHash, &status, &transactionID)
+	if err == sql.ErrNoRows {
+		// Klaim lain baru saja dilepas; klien bisa langsung mengulang.
+		return nil, idempotencyInProgressError(req.IdempotencyKey)
+	}
+	if err != nil {
+		log.Printf("[idempotency-store] Error get checkout key: %v", err)
+		return nil, err
+	}
+
+	switch {
+	case storedHash != requestHash:
+		return nil, idempotencyMismatchError(req.IdempotencyKey)
+	case status != idempotencyStatusCompleted:
+		return nil, idempotencyInProgressError(req.IdempotencyKey)
+	case !transactionID.Valid:
+		return nil, fmt.Errorf("%w: transaction for idempotency key %q", ErrNotFound, req.IdempotencyKey)
+	}
+
+	log.Printf("[idempotency-store] Checkout key replayed scope=%s key=%q transaction_id=%d",
+		req.IdempotencyScope, req.IdempotencyKey, transactionID.Int64)
+	return s.GetTransactionByID(ctx, int(transactionID.Int64))
+}
+
+// ReleaseCheckoutKey melepas klaim processing setelah checkout gagal sehingga
+// key yang sama bisa dipakai untuk mencoba lagi.
+func (s *PostgresStore) ReleaseCheckoutKey(ctx context.Context, req models.CheckoutRequest) error {
+	_, err := s.db.ExecContext(ctx,
+		"DELETE FROM checkout_idempotency_keys WHERE scope = $1 AND key = $2 AND status = $3",
+		req.IdempotencyScope, req.IdempotencyKey, idempotencyStatusProcessing)
+	if err != nil {
+		log.Printf("[idempotency-store] Error release checkout key: %v", err)
+		return err
+	}
+	return nil
+}
+
+// completeCheckoutKey menandai key selesai dengan transaksi yang dibuat, dalam
+// database transaction checkout. Key yang tidak lagi diklaim (misalnya sudah
+// diambil alih atau selesai oleh request lain) membatalkan checkout ini.
+func completeCheckoutKey(ctx context.Context, tx *sql.Tx, req models.CheckoutRequest, transactionID int) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE checkout_idempotency_keys
+		SET status = $1, transaction_id = $2, completed_at = CURRENT_TIMESTAMP
+		WHERE scope = $3 AND key = $4 AND status = $5
+	`, idempotencyStatusCompleted, transactionID, req.IdempotencyScope, req.IdempotencyKey, idempotencyStatusProcessing)
+	if err != nil {
+		log.Printf("[idempotency-store] Error complete checkout key: %v", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return idempotencyInProgressError(req.IdempotencyKey)
+	}
+	return nil
+}
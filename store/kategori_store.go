@@ -1,16 +1,16 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
-	"kasir-api/database"
 	"kasir-api/models"
 )
 
 // GetAllKategori mengembalikan semua kategori
-func GetAllKategori() []models.Kategori {
-	rows, err := database.DB.Query("SELECT id, nama, deskripsi FROM kategori ORDER BY id")
+func (s *PostgresStore) GetAllKategori(ctx context.Context) []models.Kategori {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, nama, deskripsi FROM kategori ORDER BY id")
 	if err != nil {
 		log.Printf("[kategori-store] Error GetAllKategori: %v", err)
 		return []models.Kategori{}
@@ -35,9 +35,9 @@ func GetAllKategori() []models.Kategori {
 }
 
 // GetKategoriByID mencari kategori berdasarkan ID
-func GetKategoriByID(id int) (models.Kategori, bool) {
+func (s *PostgresStore) GetKategoriByID(ctx context.Context, id int) (models.Kategori, bool) {
 	var k models.Kategori
-	err := database.DB.QueryRow("SELECT id, nama, deskripsi FROM kategori WHERE id = $1", id).
+	err := s.db.QueryRowContext(ctx, "SELECT id, nama, deskripsi FROM kategori WHERE id = $1", id).
 		Scan(&k.ID, &k.Nama, &k.Deskripsi)
 
 	if err != nil {
@@ -52,8 +52,15 @@ func GetKategoriByID(id int) (models.Kategori, bool) {
 }
 
 // AddKategori menambahkan kategori baru dan mengembalikan kategori dengan ID
-func AddKategori(k models.Kategori) (models.Kategori, error) {
-	err := database.DB.QueryRow(
+func (s *PostgresStore) AddKategori(ctx context.Context, k models.Kategori) (models.Kategori, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[kategori-store] Error begin transaction: %v", err)
+		return models.Kategori{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
 		"INSERT INTO kategori (nama, deskripsi) VALUES ($1, $2) RETURNING id",
 		k.Nama, k.Deskripsi,
 	).Scan(&k.ID)
@@ -63,12 +70,39 @@ func AddKategori(k models.Kategori) (models.Kategori, error) {
 		return models.Kategori{}, err
 	}
 
+	if err := insertAudit(ctx, tx, models.AuditEntityKategori, models.AuditActionCreate, k.ID, nil, k); err != nil {
+		return models.Kategori{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[kategori-store] Error commit AddKategori: %v", err)
+		return models.Kategori{}, err
+	}
+
 	return k, nil
 }
 
 // UpdateKategori mengupdate kategori yang sudah ada
-func UpdateKategori(id int, updated models.Kategori) bool {
-	result, err := database.DB.Exec(
+func (s *PostgresStore) UpdateKategori(ctx context.Context, id int, updated models.Kategori) bool {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[kategori-store] Error begin transaction: %v", err)
+		return false
+	}
+	defer tx.Rollback()
+
+	var before models.Kategori
+	err = tx.QueryRowContext(ctx, "SELECT id, nama, deskripsi FROM kategori WHERE id = $1 FOR UPDATE", id).
+		Scan(&before.ID, &before.Nama, &before.Deskripsi)
+	if err == sql.ErrNoRows {
+		return false
+	}
+	if err != nil {
+		log.Printf("[kategori-store] Error UpdateKategori: %v", err)
+		return false
+	}
+
+	_, err = tx.ExecContext(ctx,
 		"UPDATE kategori SET nama = $1, deskripsi = $2 WHERE id = $3",
 		updated.Nama, updated.Deskripsi, id,
 	)
@@ -78,19 +112,45 @@ func UpdateKategori(id int, updated models.Kategori) bool {
 		return false
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	return rowsAffected > 0
+	updated.ID = id
+	if err := insertAudit(ctx, tx, models.AuditEntityKategori, models.AuditActionUpdate, id, before, updated); err != nil {
+		return false
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[kategori-store] Error commit UpdateKategori: %v", err)
+		return false
+	}
+	return true
 }
 
 // DeleteKategori menghapus kategori berdasarkan ID
-func DeleteKategori(id int) bool {
-	result, err := database.DB.Exec("DELETE FROM kategori WHERE id = $1", id)
+func (s *PostgresStore) DeleteKategori(ctx context.Context, id int) bool {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[kategori-store] Error begin transaction: %v", err)
+		return false
+	}
+	defer tx.Rollback()
 
+	var before models.Kategori
+	err = tx.QueryRowContext(ctx, "DELETE FROM kategori WHERE id = $1 RETURNING id, nama, deskripsi", id).
+		Scan(&before.ID, &before.Nama, &before.Deskripsi)
+	if err == sql.ErrNoRows {
+		return false
+	}
 	if err != nil {
 		log.Printf("[kategori-store] Error DeleteKategori: %v", err)
 		return false
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	return rowsAffected > 0
+	if err := insertAudit(ctx, tx, models.AuditEntityKategori, models.AuditActionDelete, id, before, nil); err != nil {
+		return false
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[kategori-store] Error commit DeleteKategori: %v", err)
+		return false
+	}
+	return true
 }
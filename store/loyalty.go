@@ -0,0 +1,228 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// validateLoyaltySettings memastikan pengaturan poin tidak bernilai negatif.
+func validateLoyaltySettings(ls models.LoyaltySettings) error {
+	if ls.SpendPerPoint < 0 || ls.PointValue < 0 || ls.ExpiryDays < 0 {
+		return fmt.Errorf("%w: spend_per_point, point_value and expiry_days must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateLoyaltyMultiplier memastikan pengali poin berada di rentang 0-100.
+func validateLoyaltyMultiplier(m models.LoyaltyMultiplier) error {
+	if m.KategoriID <= 0 {
+		return fmt.Errorf("%w: kategori_id is required", ErrInvalidInput)
+	}
+	if m.Multiplier < 0 || m.Multiplier > 100 {
+		return fmt.Errorf("%w: multiplier must be between 0 and 100", ErrInvalidInput)
+	}
+	return nil
+}
+
+// pointsPayment menjumlahkan pembayaran dengan metode points lalu mengubahnya
+// menjadi jumlah poin yang harus ditukar. Jumlah rupiah harus kelipatan nilai
+// satu poin dan hanya bisa dipakai oleh pelanggan yang teridentifikasi.
+func pointsPayment(payments []models.CheckoutPayment, customerID int, ls models.LoyaltySettings) (int, int, error) {
+	amount := 0
+	for _, p := range payments {
+		if p.Method == models.PaymentMethodPoints {
+			amount += p.Amount
+		}
+	}
+	if amount <= 0 {
+		return 0, 0, nil
+	}
+	if customerID <= 0 {
+		return 0, 0, fmt.Errorf("%w: points payment requires customer_id", ErrInvalidPayment)
+	}
+	if ls.PointValue <= 0 {
+		return 0, 0, fmt.Errorf("%w: points redemption is disabled", ErrInvalidPayment)
+	}
+	if amount%ls.PointValue != 0 {
+		return 0, 0, fmt.Errorf("%w: points payment must be a multiple of %d", ErrInvalidPayment, ls.PointValue)
+	}
+	return amount, amount / ls.PointValue, nil
+}
+
+// earnPoints menghitung poin yang diperoleh dari detail checkout dan mengisi
+// PointsEarned per baris. Setiap baris ditimbang dari Total dikali pengali
+// kategorinya; bagian belanja yang dibayar dengan poin (pointsAmount) tidak
+// menghasilkan poin. Poin dibagi ke baris secara kumulatif agar jumlahnya tepat
+// sehingga refund bisa membalik poin sebanding dengan barang yang dikembalikan.
+func earnPoints(details []models.TransactionDetail, ls models.LoyaltySettings, multipliers map[int]float64, pointsAmount int) int {
+	for i := range details {
+		details[i].PointsEarned = 0
+	}
+	if ls.SpendPerPoint <= 0 {
+		return 0
+	}
+
+	// Bobot dihitung dalam perseratus agar pengali desimal tidak memakai float.
+	weights := make([]int, len(details))
+	spent, weighted := 0, 0
+	for i, d := range details {
+		multiplier := 1.0
+		if m, ok := multipliers[d.KategoriID]; ok && d.KategoriID > 0 {
+			multiplier = m
+		}
+		weights[i] = d.Total * int(math.Round(multiplier*100))
+		spent += d.Total
+		weighted += weights[i]
+	}
+	if spent <= 0 || weighted <= 0 || pointsAmount >= spent {
+		return 0
+	}
+
+	total := weighted * (spent - pointsAmount) / spent / (ls.SpendPerPoint * 100)
+	cumulative, allocated := 0, 0
+	for i := range details {
+		cumulative += weights[i]
+		share := total*cumulative/weighted - allocated
+		allocated += share
+		details[i].PointsEarned = share
+	}
+	return total
+}
+
+// reversalPoints menghitung poin perolehan yang harus dibalik untuk item
+// reversal. Seperti reversalAmount, nilainya kumulatif dari PointsEarned
+// sehingga semua refund sebuah detail membalik tepat poin yang diperoleh.
+func reversalPoints(details []models.TransactionDetail, items []models.ReversalItem) int {
+	byID := make(map[int]models.TransactionDetail, len(details))
+	for _, d := range details {
+		byID[d.ID] = d
+	}
+
+	points := 0
+	for _, item := range items {
+		d, ok := byID[item.TransactionDetailID]
+		if !ok || d.Quantity == 0 {
+			continue
+		}
+		before := d.PointsEarned * d.RefundedQuantity / d.Quantity
+		after := d.PointsEarned * (d.RefundedQuantity + item.Quantity) / d.Quantity
+		points += after - before
+	}
+	return points
+}
+
+// pointsExpiry mengembalikan batas berlaku lot poin baru, atau nil jika poin
+// tidak kedaluwarsa.
+func pointsExpiry(ls models.LoyaltySettings, now time.Time) *time.Time {
+	if ls.ExpiryDays <= 0 {
+		return nil
+	}
+	expiresAt := now.AddDate(0, 0, ls.ExpiryDays)
+	return &expiresAt
+}
+
+// pointsAccount adalah salinan kerja saldo dan lot poin satu pelanggan selama
+// satu operasi. Perubahan dikumpulkan di entries dan lot yang berubah ditandai
+// di changed, lalu disimpan oleh backend dalam transaksi yang sama.
+type pointsAccount struct {
+	customerID int
+	balance    int
+	lots       []models.PointsEntry // Lot earn/restore dengan Remaining > 0, urut pemakaian.
+	changed    map[int]bool         // Indeks lot yang Remaining-nya berubah.
+	entries    []models.PointsEntry // Entri ledger baru.
+}
+
+// newPointsAccount menyiapkan pointsAccount dan mengurutkan lot dari yang paling
+// cepat kedaluwarsa; lot tanpa batas berlaku dipakai terakhir.
+func newPointsAccount(customerID, balance int, lots []models.PointsEntry) *pointsAccount {
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiresAt, lots[j].ExpiresAt
+		switch {
+		case a == nil || b == nil:
+			return a != nil && b == nil
+		case !a.Equal(*b):
+			return a.Before(*b)
+		default:
+			return lots[i].ID < lots[j].ID
+		}
+	})
+	return &pointsAccount{customerID: customerID, balance: balance, lots: lots, changed: make(map[int]bool)}
+}
+
+// expire menghanguskan sisa lot yang sudah melewati batas berlaku pada now.
+func (a *pointsAccount) expire(now time.Time) {
+	for i := range a.lots {
+		lot := &a.lots[i]
+		if lot.Remaining == 0 || lot.ExpiresAt == nil || now.Before(*lot.ExpiresAt) {
+			continue
+		}
+		a.entries = append(a.entries, models.PointsEntry{
+			CustomerID: a.customerID,
+			Type:       models.PointsTypeExpire,
+			Points:     -lot.Remaining,
+		})
+		a.balance -= lot.Remaining
+		lot.Remaining = 0
+		a.changed[i] = true
+	}
+}
+
+// redeem menukar poin untuk pembayaran. Saldo harus cukup.
+func (a *pointsAccount) redeem(transactionID, points int) error {
+	if points > a.balance {
+		return fmt.Errorf("%w: insufficient points (requested: %d, balance: %d)", ErrInvalidPayment, points, max(a.balance, 0))
+	}
+	a.debit(models.PointsTypeRedeem, transactionID, points)
+	return nil
+}
+
+// debit mengurangi saldo dan memakai lot berurutan. Jika lot tidak cukup
+// (poin perolehan sudah terpakai sebelum refund), saldo boleh menjadi negatif
+// dan ditutup oleh poin berikutnya.
+func (a *pointsAccount) debit(kind string, transactionID, points int) {
+	if points <= 0 {
+		return
+	}
+	a.entries = append(a.entries, models.PointsEntry{
+		CustomerID:    a.customerID,
+		TransactionID: transactionID,
+		Type:          kind,
+		Points:        -points,
+	})
+	a.balance -= points
+
+	for i := range a.lots {
+		if points == 0 {
+			break
+		}
+		used := min(a.lots[i].Remaining, points)
+		if used == 0 {
+			continue
+		}
+		a.lots[i].Remaining -= used
+		points -= used
+		a.changed[i] = true
+	}
+}
+
+// credit menambah saldo sebagai lot baru. Saldo negatif ditutup lebih dulu
+// sehingga Remaining lot hanya berisi poin yang benar-benar bisa dipakai.
+func (a *pointsAccount) credit(kind string, transactionID, points int, expiresAt *time.Time) {
+	if points <= 0 {
+		return
+	}
+	covered := min(points, max(-a.balance, 0))
+	a.entries = append(a.entries, models.PointsEntry{
+		CustomerID:    a.customerID,
+		TransactionID: transactionID,
+		Type:          kind,
+		Points:        points,
+		Remaining:     points - covered,
+		ExpiresAt:     expiresAt,
+	})
+	a.balance += points
+}
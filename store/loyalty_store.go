@@ -0,0 +1,399 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"kasir-api/models"
+)
+
+// pointsEntryColumns adalah kolom loyalty_points sesuai urutan scanPointsEntry.
+const pointsEntryColumns = "id, customer_id, transaction_id, type, points, remaining, expires_at, created_at"
+
+// GetLoyaltySettings mengembalikan pengaturan program poin.
+func (s *PostgresStore) GetLoyaltySettings(ctx context.Context) (models.LoyaltySettings, error) {
+	return getLoyaltySettings(ctx, s.db)
+}
+
+// UpdateLoyaltySettings mengganti pengaturan program poin.
+func (s *PostgresStore) UpdateLoyaltySettings(ctx context.Context, ls models.LoyaltySettings) (models.LoyaltySettings, error) {
+	if err := validateLoyaltySettings(ls); err != nil {
+		return models.LoyaltySettings{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[loyalty-store] Error begin transaction: %v", err)
+		return models.LoyaltySettings{}, err
+	}
+	defer tx.Rollback()
+
+	// Pengaturan lama, jika ada, dicatat sebagai data sebelum perubahan.
+	var before any
+	action := models.AuditActionCreate
+	var old models.LoyaltySettings
+	err = tx.QueryRowContext(ctx,
+		"SELECT spend_per_point, point_value, expiry_days, updated_at FROM loyalty_settings WHERE id = 1 FOR UPDATE",
+	).Scan(&old.SpendPerPoint, &old.PointValue, &old.ExpiryDays, &old.UpdatedAt)
+	switch {
+	case err == nil:
+		before, action = old, models.AuditActionUpdate
+	case err != sql.ErrNoRows:
+		log.Printf("[loyalty-store] Error lock loyalty settings: %v", err)
+		return models.LoyaltySettings{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO loyalty_settings (id, spend_per_point, point_value, expiry_days)
+		VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET spend_per_point = EXCLUDED.spend_per_point, point_value = EXCLUDED.point_value,
+			expiry_days = EXCLUDED.expiry_days, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, ls.SpendPerPoint, ls.PointValue, ls.ExpiryDays).Scan(&ls.UpdatedAt)
+	if err != nil {
+		log.Printf("[loyalty-store] Error UpdateLoyaltySettings: %v", err)
+		return models.LoyaltySettings{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityLoyaltySettings, action, 1, before, ls); err != nil {
+		return models.LoyaltySettings{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[loyalty-store] Error commit UpdateLoyaltySettings: %v", err)
+		return models.LoyaltySettings{}, err
+	}
+
+	return ls, nil
+}
+
+// GetAllLoyaltyMultipliers mengembalikan semua pengali poin urut kategori.
+func (s *PostgresStore) GetAllLoyaltyMultipliers(ctx context.Context) ([]models.LoyaltyMultiplier, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT kategori_id, multiplier, updated_at FROM loyalty_multipliers ORDER BY kategori_id")
+	if err != nil {
+		log.Printf("[loyalty-store] Error GetAllLoyaltyMultipliers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	multipliers := []models.LoyaltyMultiplier{}
+	for rows.Next() {
+		var m models.LoyaltyMultiplier
+		if err := rows.Scan(&m.KategoriID, &m.Multiplier, &m.UpdatedAt); err != nil {
+			log.Printf("[loyalty-store] Error scanning multiplier row: %v", err)
+			continue
+		}
+		multipliers = append(multipliers, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[loyalty-store] Error iterating multiplier rows: %v", err)
+		return nil, err
+	}
+
+	return multipliers, nil
+}
+
+// SetLoyaltyMultiplier menyimpan pengali poin untuk satu kategori, menggantikan
+// pengali sebelumnya jika ada.
+func (s *PostgresStore) SetLoyaltyMultiplier(ctx context.Context, m models.LoyaltyMultiplier) (models.LoyaltyMultiplier, error) {
+	if err := validateLoyaltyMultiplier(m); err != nil {
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[loyalty-store] Error begin transaction: %v", err)
+		return models.LoyaltyMultiplier{}, err
+	}
+	defer tx.Rollback()
+
+	// Pengali lama, jika ada, dicatat sebagai data sebelum perubahan.
+	var before any
+	action := models.AuditActionCreate
+	var old models.LoyaltyMultiplier
+	err = tx.QueryRowContext(ctx,
+		"SELECT kategori_id, multiplier, updated_at FROM loyalty_multipliers WHERE kategori_id = $1 FOR UPDATE", m.KategoriID,
+	).Scan(&old.KategoriID, &old.Multiplier, &old.UpdatedAt)
+	switch {
+	case err == nil:
+		before, action = old, models.AuditActionUpdate
+	case err != sql.ErrNoRows:
+		log.Printf("[loyalty-store] Error lock loyalty multiplier: %v", err)
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO loyalty_multipliers (kategori_id, multiplier)
+		VALUES ($1, $2)
+		ON CONFLICT (kategori_id) DO UPDATE SET multiplier = EXCLUDED.multiplier, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, m.KategoriID, m.Multiplier).Scan(&m.UpdatedAt)
+	if isForeignKeyViolation(err) {
+		return models.LoyaltyMultiplier{}, fmt.Errorf("%w: kategori id %d not found", ErrInvalidInput, m.KategoriID)
+	}
+	if err != nil {
+		log.Printf("[loyalty-store] Error SetLoyaltyMultiplier: %v", err)
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityLoyaltyMultiplier, action, m.KategoriID, before, m); err != nil {
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[loyalty-store] Error commit SetLoyaltyMultiplier: %v", err)
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	return m, nil
+}
+
+// DeleteLoyaltyMultiplier menghapus pengali poin sehingga kategori kembali memakai pengali 1.
+func (s *PostgresStore) DeleteLoyaltyMultiplier(ctx context.Context, kategoriID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[loyalty-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var before models.LoyaltyMultiplier
+	err = tx.QueryRowContext(ctx,
+		"DELETE FROM loyalty_multipliers WHERE kategori_id = $1 RETURNING kategori_id, multiplier, updated_at", kategoriID,
+	).Scan(&before.KategoriID, &before.Multiplier, &before.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: loyalty multiplier for kategori %d", ErrNotFound, kategoriID)
+	}
+	if err != nil {
+		log.Printf("[loyalty-store] Error DeleteLoyaltyMultiplier: %v", err)
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityLoyaltyMultiplier, models.AuditActionDelete, kategoriID, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[loyalty-store] Error commit DeleteLoyaltyMultiplier: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetPointsAccount mengembalikan saldo dan riwayat poin pelanggan. Poin yang
+// sudah melewati batas berlaku dihanguskan lebih dulu agar saldo selalu akurat.
+func (s *PostgresStore) GetPointsAccount(ctx context.Context, customerID int) (models.PointsAccount, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[loyalty-store] Error begin transaction: %v", err)
+		return models.PointsAccount{}, err
+	}
+	defer tx.Rollback()
+
+	account, err := lockPointsAccount(ctx, tx, customerID)
+	if err != nil {
+		return models.PointsAccount{}, err
+	}
+	account.expire(time.Now())
+	if err := savePointsAccount(ctx, tx, account); err != nil {
+		return models.PointsAccount{}, err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		"SELECT "+pointsEntryColumns+" FROM loyalty_points WHERE customer_id = $1 ORDER BY id DESC", customerID)
+	if err != nil {
+		log.Printf("[loyalty-store] Error get points entries: %v", err)
+		return models.PointsAccount{}, err
+	}
+	defer rows.Close()
+
+	result := models.PointsAccount{CustomerID: customerID, Balance: account.balance, Entries: []models.PointsEntry{}}
+	for rows.Next() {
+		e, err := scanPointsEntry(rows)
+		if err != nil {
+			log.Printf("[loyalty-store] Error scanning points entry row: %v", err)
+			continue
+		}
+		result.Entries = append(result.Entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[loyalty-store] Error iterating points entry rows: %v", err)
+		return models.PointsAccount{}, err
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[loyalty-store] Error commit transaction: %v", err)
+		return models.PointsAccount{}, err
+	}
+
+	return result, nil
+}
+
+// getLoyaltySettings mengambil pengaturan program poin. Tanpa baris pengaturan,
+// program poin dianggap nonaktif.
+func getLoyaltySettings(ctx context.Context, q queryer) (models.LoyaltySettings, error) {
+	var ls models.LoyaltySettings
+	err := q.QueryRowContext(ctx,
+		"SELECT spend_per_point, point_value, expiry_days, updated_at FROM loyalty_settings WHERE id = 1",
+	).Scan(&ls.SpendPerPoint, &ls.PointValue, &ls.ExpiryDays, &ls.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.LoyaltySettings{}, nil
+	}
+	if err != nil {
+		log.Printf("[loyalty-store] Error get loyalty settings: %v", err)
+		return models.LoyaltySettings{}, err
+	}
+	return ls, nil
+}
+
+// getLoyaltyMultipliers mengambil pengali poin per kategori.
+func getLoyaltyMultipliers(ctx context.Context, q queryer) (map[int]float64, error) {
+	rows, err := q.QueryContext(ctx, "SELECT kategori_id, multiplier FROM loyalty_multipliers")
+	if err != nil {
+		log.Printf("[loyalty-store] Error get loyalty multipliers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	multipliers := make(map[int]float64)
+	for rows.Next() {
+		var kategoriID int
+		var multiplier float64
+		if err := rows.Scan(&kategoriID, &multiplier); err != nil {
+			log.Printf("[loyalty-store] Error scanning multiplier row: %v", err)
+			continue
+		}
+		multipliers[kategoriID] = multiplier
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[loyalty-store] Error iterating multiplier rows: %v", err)
+		return nil, err
+	}
+
+	return multipliers, nil
+}
+
+// lockPointsAccount mengunci baris pelanggan lalu memuat saldo dan lot poin
+// yang masih tersisa. Kunci ini dipegang sampai transaksi selesai sehingga
+// checkout dan void/refund untuk pelanggan yang sama berjalan bergantian.
+func lockPointsAccount(ctx context.Context, tx *sql.Tx, customerID int) (*pointsAccount, error) {
+	var balance int
+	err := tx.QueryRowContext(ctx, "SELECT points_balance FROM customers WHERE id = $1 FOR UPDATE", customerID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: customer id %d", ErrNotFound, customerID)
+	}
+	if err != nil {
+		log.Printf("[loyalty-store] Error lock customer: %v", err)
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		"SELECT "+pointsEntryColumns+" FROM loyalty_points WHERE customer_id = $1 AND remaining > 0 ORDER BY id",
+		customerID)
+	if err != nil {
+		log.Printf("[loyalty-store] Error get points lots: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []models.PointsEntry
+	for rows.Next() {
+		lot, err := scanPointsEntry(rows)
+		if err != nil {
+			log.Printf("[loyalty-store] Error scanning points lot row: %v", err)
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[loyalty-store] Error iterating points lot rows: %v", err)
+		return nil, err
+	}
+
+	return newPointsAccount(customerID, balance, lots), nil
+}
+
+// savePointsAccount menyimpan sisa lot yang berubah, entri ledger baru, dan
+// saldo pelanggan dalam database transaction pemanggil.
+func savePointsAccount(ctx context.Context, tx *sql.Tx, a *pointsAccount) error {
+	for i := range a.changed {
+		lot := a.lots[i]
+		if _, err := tx.ExecContext(ctx, "UPDATE loyalty_points SET remaining = $1 WHERE id = $2", lot.Remaining, lot.ID); err != nil {
+			log.Printf("[loyalty-store] Error update points lot: %v", err)
+			return err
+		}
+	}
+
+	for i := range a.entries {
+		e := &a.entries[i]
+		var expiresAt sql.NullTime
+		if e.ExpiresAt != nil {
+			expiresAt = sql.NullTime{Time: *e.ExpiresAt, Valid: true}
+		}
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO loyalty_points (customer_id, transaction_id, type, points, remaining, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+			e.CustomerID, nullableID(e.TransactionID), e.Type, e.Points, e.Remaining, expiresAt,
+		).Scan(&e.ID, &e.CreatedAt)
+		if err != nil {
+			log.Printf("[loyalty-store] Error insert points entry: %v", err)
+			return err
+		}
+	}
+
+	if len(a.entries) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE customers SET points_balance = $1 WHERE id = $2", a.balance, a.customerID); err != nil {
+		log.Printf("[loyalty-store] Error update points balance: %v", err)
+		return err
+	}
+	return nil
+}
+
+// scanPointsEntry membaca satu baris ledger poin.
+func scanPointsEntry(row rowScanner) (models.PointsEntry, error) {
+	var e models.PointsEntry
+	var transactionID sql.NullInt64
+	var expiresAt sql.NullTime
+	err := row.Scan(&e.ID, &e.CustomerID, &transactionID, &e.Type, &e.Points, &e.Remaining, &expiresAt, &e.CreatedAt)
+	if err != nil {
+		return e, err
+	}
+	e.TransactionID = int(transactionID.Int64)
+	if expiresAt.Valid {
+		e.ExpiresAt = &expiresAt.Time
+	}
+	return e, nil
+}
+
+// reverseTransactionPoints membalik poin perolehan dan mengembalikan poin yang
+// ditukar saat transaksi di-void atau di-refund, dalam database transaction
+// yang sama dengan reversal.
+func reverseTransactionPoints(ctx context.Context, tx *sql.Tx, customerID, transactionID, reversed, restored int) error {
+	if customerID == 0 || (reversed == 0 && restored == 0) {
+		return nil
+	}
+
+	loyalty, err := getLoyaltySettings(ctx, tx)
+	if err != nil {
+		return err
+	}
+	account, err := lockPointsAccount(ctx, tx, customerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	account.expire(now)
+	account.debit(models.PointsTypeReverse, transactionID, reversed)
+	account.credit(models.PointsTypeRestore, transactionID, restored, pointsExpiry(loyalty, now))
+	return savePointsAccount(ctx, tx, account)
+}
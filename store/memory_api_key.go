@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// CreateAPIKey membuat kunci API baru. Kunci utuh hanya ada di hasil fungsi ini.
+func (s *MemoryStore) CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.APIKey, error) {
+	req, err := prepareAPIKey(req)
+	if err != nil {
+		return nil, err
+	}
+	key, prefix, keyHash, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := models.APIKey{
+		ID:        s.nextAPIKeyID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		Scopes:    req.Scopes,
+		Role:      req.Role,
+		Outlet:    req.Outlet,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: time.Now(),
+	}
+	s.nextAPIKeyID++
+	s.apiKeys[k.ID] = k
+	s.apiKeyHashes[k.ID] = keyHash
+	s.addAuditLocked(ctx, models.AuditEntityAPIKey, models.AuditActionCreate, k.ID, nil, k)
+
+	k.Key = key
+	return &k, nil
+}
+
+// GetAllAPIKeys mengembalikan semua kunci API urut ID, termasuk yang sudah dicabut.
+func (s *MemoryStore) GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]models.APIKey, 0, len(s.apiKeys))
+	for _, k := range s.apiKeys {
+		keys = append(keys, copyAPIKey(k))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// RevokeAPIKey mencabut kunci API sehingga tidak bisa dipakai lagi.
+func (s *MemoryStore) RevokeAPIKey(ctx context.Context, id int) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, err := s.activeAPIKeyLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	before := k
+	now := time.Now()
+	k.RevokedAt = &now
+	s.apiKeys[id] = k
+	s.addAuditLocked(ctx, models.AuditEntityAPIKey, models.AuditActionUpdate, id, before, k)
+
+	k = copyAPIKey(k)
+	return &k, nil
+}
+
+// RotateAPIKey mengganti rahasia kunci API dengan scope dan outlet yang sama.
+func (s *MemoryStore) RotateAPIKey(ctx context.Context, id int) (*models.APIKey, error) {
+	key, prefix, keyHash, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, err := s.activeAPIKeyLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	before := k
+	now := time.Now()
+	k.Prefix, k.RotatedAt = prefix, &now
+	s.apiKeys[id] = k
+	s.apiKeyHashes[id] = keyHash
+	s.addAuditLocked(ctx, models.AuditEntityAPIKey, models.AuditActionUpdate, id, before, k)
+
+	k = copyAPIKey(k)
+	k.Key = key
+	return &k, nil
+}
+
+// AuthenticateAPIKey mengembalikan kunci API yang belum dicabut sekaligus
+// mencatat waktu terakhir dipakai.
+func (s *MemoryStore) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keyHash := hashToken(key)
+	for id, h := range s.apiKeyHashes {
+		k := s.apiKeys[id]
+		if h != keyHash || k.RevokedAt != nil {
+			continue
+		}
+		now := time.Now()
+		k.LastUsedAt = &now
+		s.apiKeys[id] = k
+
+		k = copyAPIKey(k)
+		return &k, nil
+	}
+	return nil, fmt.Errorf("%w: invalid or revoked api key", ErrUnauthorized)
+}
+
+// activeAPIKeyLocked mengambil kunci API yang belum dicabut. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) activeAPIKeyLocked(id int) (models.APIKey, error) {
+	k, ok := s.apiKeys[id]
+	if !ok {
+		return models.APIKey{}, fmt.Errorf("%w: api key id %d", ErrNotFound, id)
+	}
+	if k.RevokedAt != nil {
+		return models.APIKey{}, fmt.Errorf("%w: api key %d is revoked", ErrConflict, id)
+	}
+	return k, nil
+}
+
+// copyAPIKey menyalin kunci API agar slice scope tidak ikut berubah dari luar store.
+func copyAPIKey(k models.APIKey) models.APIKey {
+	k.Scopes = slices.Clone(k.Scopes)
+	return k
+}
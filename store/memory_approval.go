@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kasir-api/models"
+)
+
+// memoryApprovalToken adalah padanan baris approval_tokens di MemoryStore.
+type memoryApprovalToken struct {
+	managerID int
+	action    string
+	expiresAt time.Time
+}
+
+// memoryPINFailure adalah padanan baris approval_pin_failures di MemoryStore.
+type memoryPINFailure struct {
+	failures      int
+	lastFailureAt time.Time
+	lockedUntil   time.Time
+}
+
+// VerifyManagerPIN memeriksa PIN persetujuan lalu mengembalikan manajer/owner
+// aktif pemiliknya. PIN yang salah dihitung dan dikunci seperti PostgresStore.
+func (s *MemoryStore) VerifyManagerPIN(ctx context.Context, username, pin string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	username = normalizeUsername(username)
+	attempt := pinAttemptUsername(username)
+	subjects := pinSubjects(ctx, attempt)
+	now := time.Now()
+	for _, subject := range subjects {
+		if now.Before(s.pinFailures[subject].lockedUntil) {
+			s.addAuditLocked(ctx, models.AuditEntityManagerPIN, models.AuditActionFailed, attempt, nil,
+				models.ManagerPINFailure{Caller: auditSourceFromContext(ctx).actor, Locked: true})
+			return nil, errPINLocked(attempt)
+		}
+	}
+
+	for _, u := range s.users {
+		if u.Username != username {
+			continue
+		}
+		if !verifyPassword(s.pinHashes[u.ID], pin) || !u.Active || !approverRoles[u.Role] {
+			break
+		}
+		for _, subject := range subjects {
+			delete(s.pinFailures, subject)
+		}
+		return &u, nil
+	}
+	return nil, s.recordPINFailureLocked(ctx, attempt, subjects, now)
+}
+
+// recordPINFailureLocked adalah padanan recordPINFailure. Pemanggil harus
+// memegang s.mu.
+func (s *MemoryStore) recordPINFailureLocked(ctx context.Context, username string, subjects []string, now time.Time) error {
+	failure := models.ManagerPINFailure{Caller: auditSourceFromContext(ctx).actor}
+	for i, subject := range subjects {
+		f := s.pinFailures[subject]
+		if f.lastFailureAt.Before(now.Add(-pinLockout)) {
+			f.failures = 0
+		}
+		f.failures++
+		f.lastFailureAt = now
+		if i == 0 {
+			failure.Failures = f.failures
+		}
+		if f.failures >= maxPINFailures {
+			f.failures = 0
+			f.lockedUntil = now.Add(pinLockout)
+			failure.Locked = true
+		}
+		s.pinFailures[subject] = f
+	}
+	s.addAuditLocked(ctx, models.AuditEntityManagerPIN, models.AuditActionFailed, username, nil, failure)
+
+	if failure.Locked {
+		return errPINLocked(username)
+	}
+	return fmt.Errorf("%w: invalid manager pin", ErrUnauthorized)
+}
+
+// CreateApprovalToken membuat token persetujuan sekali pakai untuk action
+// atas nama manajer yang berlaku selama ttl.
+func (s *MemoryStore) CreateApprovalToken(ctx context.Context, managerID int, action string, ttl time.Duration) (*models.ApprovalToken, error) {
+	if err := validateApprovalAction(action); err != nil {
+		return nil, err
+	}
+	token, tokenHash, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	s.approvalTokens[tokenHash] = memoryApprovalToken{managerID: managerID, action: action, expiresAt: expiresAt}
+	return &models.ApprovalToken{Token: token, Action: action, ExpiresAt: expiresAt}, nil
+}
+
+// RedeemApprovalToken memakai token persetujuan untuk action lalu mengembalikan
+// manajer pembuatnya. Token langsung dihapus sehingga tidak bisa dipakai lagi.
+func (s *MemoryStore) RedeemApprovalToken(ctx context.Context, token, action string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokenHash := hashToken(token)
+	t, ok := s.approvalTokens[tokenHash]
+	if !ok || t.action != action || !time.Now().Before(t.expiresAt) {
+		return nil, fmt.Errorf("%w: invalid or expired approval token", ErrUnauthorized)
+	}
+	delete(s.approvalTokens, tokenHash)
+
+	u, ok := s.users[t.managerID]
+	if !ok || !u.Active || !approverRoles[u.Role] {
+		return nil, fmt.Errorf("%w: invalid or expired approval token", ErrUnauthorized)
+	}
+	return &u, nil
+}
+
+// GetAllApprovals mengembalikan catatan persetujuan terbaru lebih dulu.
+func (s *MemoryStore) GetAllApprovals(ctx context.Context, filter ApprovalFilter) ([]models.Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approvals := []models.Approval{}
+	for i := len(s.approvals) - 1; i >= 0; i-- {
+		a := s.approvals[i]
+		if filter.Action != "" && a.Action != filter.Action {
+			continue
+		}
+		if filter.EntityID > 0 && a.EntityID != filter.EntityID {
+			continue
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
+
+// addApprovalLocked mencatat persetujuan untuk entityID, padanan insertApproval.
+// Pemanggil harus memegang s.mu.
+func (s *MemoryStore) addApprovalLocked(a models.Approval, entityID int) {
+	a.ID = s.nextApprovalID
+	a.EntityID = entityID
+	a.CreatedAt = time.Now()
+	s.nextApprovalID++
+	s.approvals = append(s.approvals, a)
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"kasir-api/models"
+)
+
+// GetAuditLog mengembalikan catatan audit dalam rentang waktu [filter.Start,
+// filter.End), terbaru lebih dulu.
+func (s *MemoryStore) GetAuditLog(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []models.AuditEntry{}
+	for i := len(s.auditLog) - 1; i >= 0; i-- {
+		e := s.auditLog[i]
+		if filter.Entity != "" && e.Entity != filter.Entity {
+			continue
+		}
+		if filter.Actor != "" && e.Actor != filter.Actor {
+			continue
+		}
+		if !filter.Start.IsZero() && e.CreatedAt.Before(filter.Start) {
+			continue
+		}
+		if !filter.End.IsZero() && !e.CreatedAt.Before(filter.End) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// addAuditLocked mencatat perubahan master data, padanan insertAudit.
+// Pemanggil harus memegang s.mu.
+func (s *MemoryStore) addAuditLocked(ctx context.Context, entity, action string, entityID any, before, after any) {
+	e, err := newAuditEntry(ctx, entity, action, entityID, before, after)
+	if err != nil {
+		log.Printf("[memory-store] Error build audit entry: %v", err)
+		return
+	}
+	e.ID = s.nextAuditID
+	e.CreatedAt = time.Now()
+	s.nextAuditID++
+	s.auditLog = append(s.auditLog, e)
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// AddCustomer menambahkan pelanggan baru.
+func (s *MemoryStore) AddCustomer(ctx context.Context, c models.Customer) (models.Customer, error) {
+	c, err := prepareCustomer(c)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkCustomerUniqueLocked(0, c); err != nil {
+		return models.Customer{}, err
+	}
+
+	c.ID = s.nextCustomerID
+	c.CreatedAt = time.Now()
+	s.nextCustomerID++
+	s.customers[c.ID] = c
+	s.addAuditLocked(ctx, models.AuditEntityCustomer, models.AuditActionCreate, c.ID, nil, c)
+	return c, nil
+}
+
+// GetAllCustomers mengembalikan semua pelanggan urut ID, atau hanya pelanggan
+// dengan nomor telepon phone jika diisi.
+func (s *MemoryStore) GetAllCustomers(ctx context.Context, phone string) ([]models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	phone = NormalizePhone(phone)
+	customers := make([]models.Customer, 0, len(s.customers))
+	for _, c := range s.customers {
+		if phone != "" && c.Telepon != phone {
+			continue
+		}
+		customers = append(customers, c)
+	}
+	sort.Slice(customers, func(i, j int) bool { return customers[i].ID < customers[j].ID })
+	return customers, nil
+}
+
+// GetCustomerByID mengembalikan satu pelanggan berdasarkan ID.
+func (s *MemoryStore) GetCustomerByID(ctx context.Context, id int) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.customers[id]
+	if !ok {
+		return models.Customer{}, fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	return c, nil
+}
+
+// UpdateCustomer mengganti data pelanggan berdasarkan ID.
+func (s *MemoryStore) UpdateCustomer(ctx context.Context, id int, c models.Customer) (models.Customer, error) {
+	c, err := prepareCustomer(c)
+	if err != nil {
+		return models.Customer{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.customers[id]
+	if !ok {
+		return models.Customer{}, fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	if err := s.checkCustomerUniqueLocked(id, c); err != nil {
+		return models.Customer{}, err
+	}
+
+	c.ID, c.CreatedAt, c.PointsBalance = id, current.CreatedAt, current.PointsBalance
+	s.customers[id] = c
+	s.addAuditLocked(ctx, models.AuditEntityCustomer, models.AuditActionUpdate, id, current, c)
+	return c, nil
+}
+
+// DeleteCustomer menghapus pelanggan berdasarkan ID. Seperti ON DELETE SET NULL,
+// transaksi dan penukaran voucher lama tetap ada tanpa referensi pelanggan,
+// sedangkan ledger poinnya ikut terhapus seperti ON DELETE CASCADE.
+func (s *MemoryStore) DeleteCustomer(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.customers[id]
+	if !ok {
+		return fmt.Errorf("%w: customer id %d", ErrNotFound, id)
+	}
+	delete(s.customers, id)
+	s.addAuditLocked(ctx, models.AuditEntityCustomer, models.AuditActionDelete, id, before, nil)
+
+	for tid, t := range s.transactions {
+		if t.CustomerID == id {
+			t.CustomerID = 0
+			s.transactions[tid] = t
+		}
+	}
+	for i := range s.redemptions {
+		if s.redemptions[i].CustomerID == id {
+			s.redemptions[i].CustomerID = 0
+		}
+	}
+	points := s.points[:0]
+	for _, e := range s.points {
+		if e.CustomerID != id {
+			points = append(points, e)
+		}
+	}
+	s.points = points
+	return nil
+}
+
+// GetCustomerTransactions mengembalikan riwayat transaksi satu pelanggan, terbaru lebih dulu.
+func (s *MemoryStore) GetCustomerTransactions(ctx context.Context, id int) ([]models.Transaction, error) {
+	if _, err := s.GetCustomerByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetAllTransactions(ctx, TransactionFilter{CustomerID: id})
+}
+
+// checkCustomerUniqueLocked memastikan telepon dan nomor member c belum dipakai
+// pelanggan lain, seperti unique index di database.
+func (s *MemoryStore) checkCustomerUniqueLocked(id int, c models.Customer) error {
+	for _, other := range s.customers {
+		if other.ID == id {
+			continue
+		}
+		if (c.Telepon != "" && other.Telepon == c.Telepon) || (c.NomorMember != "" && other.NomorMember == c.NomorMember) {
+			return fmt.Errorf("%w: telepon or nomor_member already registered", ErrConflict)
+		}
+	}
+	return nil
+}
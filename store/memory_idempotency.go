@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kasir-api/models"
+)
+
+// memoryIdempotencyKey adalah klaim Idempotency-Key checkout di MemoryStore.
+type memoryIdempotencyKey struct {
+	requestHash   string
+	status        string
+	transactionID int
+	claimedAt     time.Time
+}
+
+// memoryIdempotencyID adalah primary key checkout_idempotency_keys: key berlaku per scope.
+type memoryIdempotencyID struct {
+	scope string
+	key   string
+}
+
+// ClaimCheckoutKey mengklaim req.IdempotencyKey sebelum checkout berjalan,
+// padanan versi PostgresStore.
+func (s *MemoryStore) ClaimCheckoutKey(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	requestHash, err := checkoutRequestHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := memoryIdempotencyID{scope: req.IdempotencyScope, key: req.IdempotencyKey}
+	k, ok := s.idempotencyKeys[id]
+	switch {
+	case !ok, k.status == idempotencyStatusProcessing && k.requestHash == requestHash &&
+		time.Since(k.claimedAt) > idempotencyClaimTimeout:
+		s.idempotencyKeys[id] = memoryIdempotencyKey{
+			requestHash: requestHash,
+			status:      idempotencyStatusProcessing,
+			claimedAt:   time.Now(),
+		}
+		return nil, nil
+	case k.requestHash != requestHash:
+		return nil, idempotencyMismatchError(req.IdempotencyKey)
+	case k.status != idempotencyStatusCompleted:
+		return nil, idempotencyInProgressError(req.IdempotencyKey)
+	}
+
+	t, ok := s.transactions[k.transactionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: transaction for idempotency key %q", ErrNotFound, req.IdempotencyKey)
+	}
+	result := copyTransaction(t)
+	return &result, nil
+}
+
+// ReleaseCheckoutKey melepas klaim processing setelah checkout gagal.
+func (s *MemoryStore) ReleaseCheckoutKey(ctx context.Context, req models.CheckoutRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := memoryIdempotencyID{scope: req.IdempotencyScope, key: req.IdempotencyKey}
+	if k, ok := s.idempotencyKeys[id]; ok && k.status == idempotencyStatusProcessing {
+		delete(s.idempotencyKeys, id)
+	}
+	return nil
+}
+
+// checkCheckoutKeyLocked memastikan key masih diklaim processing sebelum
+// checkout mengubah data. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) checkCheckoutKeyLocked(req models.CheckoutRequest) error {
+	id := memoryIdempotencyID{scope: req.IdempotencyScope, key: req.IdempotencyKey}
+	if k, ok := s.idempotencyKeys[id]; !ok || k.status != idempotencyStatusProcessing {
+		return idempotencyInProgressError(req.IdempotencyKey)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// GetLoyaltySettings mengembalikan pengaturan program poin.
+func (s *MemoryStore) GetLoyaltySettings(ctx context.Context) (models.LoyaltySettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loyalty, nil
+}
+
+// UpdateLoyaltySettings mengganti pengaturan program poin.
+func (s *MemoryStore) UpdateLoyaltySettings(ctx context.Context, ls models.LoyaltySettings) (models.LoyaltySettings, error) {
+	if err := validateLoyaltySettings(ls); err != nil {
+		return models.LoyaltySettings{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Pengaturan yang belum pernah disimpan masih bernilai nol.
+	var before any
+	action := models.AuditActionCreate
+	if !s.loyalty.UpdatedAt.IsZero() {
+		before, action = s.loyalty, models.AuditActionUpdate
+	}
+
+	ls.UpdatedAt = time.Now()
+	s.loyalty = ls
+	s.addAuditLocked(ctx, models.AuditEntityLoyaltySettings, action, 1, before, ls)
+	return ls, nil
+}
+
+// GetAllLoyaltyMultipliers mengembalikan semua pengali poin urut kategori.
+func (s *MemoryStore) GetAllLoyaltyMultipliers(ctx context.Context) ([]models.LoyaltyMultiplier, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	multipliers := make([]models.LoyaltyMultiplier, 0, len(s.multipliers))
+	for _, m := range s.multipliers {
+		multipliers = append(multipliers, m)
+	}
+	sort.Slice(multipliers, func(i, j int) bool { return multipliers[i].KategoriID < multipliers[j].KategoriID })
+	return multipliers, nil
+}
+
+// SetLoyaltyMultiplier menyimpan pengali poin untuk satu kategori.
+func (s *MemoryStore) SetLoyaltyMultiplier(ctx context.Context, m models.LoyaltyMultiplier) (models.LoyaltyMultiplier, error) {
+	if err := validateLoyaltyMultiplier(m); err != nil {
+		return models.LoyaltyMultiplier{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.kategori[m.KategoriID]; !ok {
+		return models.LoyaltyMultiplier{}, fmt.Errorf("%w: kategori id %d not found", ErrInvalidInput, m.KategoriID)
+	}
+
+	var before any
+	action := models.AuditActionCreate
+	if old, ok := s.multipliers[m.KategoriID]; ok {
+		before, action = old, models.AuditActionUpdate
+	}
+
+	m.UpdatedAt = time.Now()
+	s.multipliers[m.KategoriID] = m
+	s.addAuditLocked(ctx, models.AuditEntityLoyaltyMultiplier, action, m.KategoriID, before, m)
+	return m, nil
+}
+
+// DeleteLoyaltyMultiplier menghapus pengali poin untuk satu kategori.
+func (s *MemoryStore) DeleteLoyaltyMultiplier(ctx context.Context, kategoriID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.multipliers[kategoriID]
+	if !ok {
+		return fmt.Errorf("%w: loyalty multiplier for kategori %d", ErrNotFound, kategoriID)
+	}
+	delete(s.multipliers, kategoriID)
+	s.addAuditLocked(ctx, models.AuditEntityLoyaltyMultiplier, models.AuditActionDelete, kategoriID, before, nil)
+	return nil
+}
+
+// GetPointsAccount mengembalikan saldo dan riwayat poin pelanggan setelah poin
+// yang kedaluwarsa dihanguskan.
+func (s *MemoryStore) GetPointsAccount(ctx context.Context, customerID int) (models.PointsAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.customers[customerID]; !ok {
+		return models.PointsAccount{}, fmt.Errorf("%w: customer id %d", ErrNotFound, customerID)
+	}
+	account := s.pointsAccountLocked(customerID)
+	account.expire(time.Now())
+	s.savePointsAccountLocked(account)
+
+	result := models.PointsAccount{CustomerID: customerID, Balance: account.balance, Entries: []models.PointsEntry{}}
+	for i := len(s.points) - 1; i >= 0; i-- {
+		if s.points[i].CustomerID == customerID {
+			result.Entries = append(result.Entries, s.points[i])
+		}
+	}
+	return result, nil
+}
+
+// multiplierRatesLocked mengembalikan pengali poin per kategori. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) multiplierRatesLocked() map[int]float64 {
+	rates := make(map[int]float64, len(s.multipliers))
+	for kategoriID, m := range s.multipliers {
+		rates[kategoriID] = m.Multiplier
+	}
+	return rates
+}
+
+// pointsAccountLocked menyalin saldo dan lot poin pelanggan yang masih tersisa,
+// padanan lockPointsAccount. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) pointsAccountLocked(customerID int) *pointsAccount {
+	var lots []models.PointsEntry
+	for _, e := range s.points {
+		if e.CustomerID == customerID && e.Remaining > 0 {
+			lots = append(lots, e)
+		}
+	}
+	return newPointsAccount(customerID, s.customers[customerID].PointsBalance, lots)
+}
+
+// savePointsAccountLocked menyimpan perubahan pointsAccount ke ledger dan saldo
+// pelanggan, padanan savePointsAccount. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) savePointsAccountLocked(a *pointsAccount) {
+	for i := range a.changed {
+		lot := a.lots[i]
+		for j := range s.points {
+			if s.points[j].ID == lot.ID {
+				s.points[j].Remaining = lot.Remaining
+			}
+		}
+	}
+
+	for _, e := range a.entries {
+		e.ID = s.nextPointsID
+		e.CreatedAt = time.Now()
+		s.nextPointsID++
+		s.points = append(s.points, e)
+	}
+
+	c := s.customers[a.customerID]
+	c.PointsBalance = a.balance
+	s.customers[a.customerID] = c
+}
+
+// reverseTransactionPointsLocked membalik poin perolehan dan mengembalikan poin
+// yang ditukar untuk void/refund. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) reverseTransactionPointsLocked(customerID, transactionID, reversed, restored int) {
+	if _, ok := s.customers[customerID]; !ok || (reversed == 0 && restored == 0) {
+		return
+	}
+
+	now := time.Now()
+	account := s.pointsAccountLocked(customerID)
+	account.expire(now)
+	account.debit(models.PointsTypeReverse, transactionID, reversed)
+	account.credit(models.PointsTypeRestore, transactionID, restored, pointsExpiry(s.loyalty, now))
+	s.savePointsAccountLocked(account)
+}
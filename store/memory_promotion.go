@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// AddPromotion menambahkan promosi baru.
+func (s *MemoryStore) AddPromotion(ctx context.Context, p models.Promotion) (models.Promotion, error) {
+	if err := validatePromotion(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkPromotionTargetLocked(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	p.ID = s.nextPromotionID
+	p.CreatedAt = time.Now()
+	s.nextPromotionID++
+	s.promotions[p.ID] = p
+	s.addAuditLocked(ctx, models.AuditEntityPromotion, models.AuditActionCreate, p.ID, nil, p)
+	return p, nil
+}
+
+// GetAllPromotions mengembalikan semua promosi urut ID.
+func (s *MemoryStore) GetAllPromotions(ctx context.Context) ([]models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedPromotionsLocked(), nil
+}
+
+// GetPromotionByID mengembalikan satu promosi berdasarkan ID.
+func (s *MemoryStore) GetPromotionByID(ctx context.Context, id int) (models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.promotions[id]
+	if !ok {
+		return models.Promotion{}, fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	return p, nil
+}
+
+// UpdatePromotion mengganti aturan promosi berdasarkan ID.
+func (s *MemoryStore) UpdatePromotion(ctx context.Context, id int, p models.Promotion) (models.Promotion, error) {
+	if err := validatePromotion(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.promotions[id]
+	if !ok {
+		return models.Promotion{}, fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	if err := s.checkPromotionTargetLocked(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	p.ID, p.CreatedAt = id, current.CreatedAt
+	s.promotions[id] = p
+	s.addAuditLocked(ctx, models.AuditEntityPromotion, models.AuditActionUpdate, id, current, p)
+	return p, nil
+}
+
+// DeletePromotion menghapus promosi berdasarkan ID. Seperti ON DELETE SET NULL,
+// catatan promosi pada transaksi lama tetap ada tanpa referensi promosi.
+func (s *MemoryStore) DeletePromotion(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.promotions[id]
+	if !ok {
+		return fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	delete(s.promotions, id)
+	s.addAuditLocked(ctx, models.AuditEntityPromotion, models.AuditActionDelete, id, before, nil)
+
+	for _, t := range s.transactions {
+		for i := range t.Promotions {
+			if t.Promotions[i].PromotionID == id {
+				t.Promotions[i].PromotionID = 0
+			}
+		}
+	}
+	return nil
+}
+
+// checkPromotionTargetLocked memastikan produk/kategori target promosi ada,
+// seperti foreign key di database.
+func (s *MemoryStore) checkPromotionTargetLocked(p models.Promotion) error {
+	if _, ok := s.produk[p.ProductID]; p.ProductID > 0 && !ok {
+		return fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if _, ok := s.kategori[p.KategoriID]; p.KategoriID > 0 && !ok {
+		return fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	return nil
+}
+
+// sortedPromotionsLocked mengembalikan salinan semua promosi urut ID, sama
+// seperti urutan yang dievaluasi PostgresStore saat checkout.
+func (s *MemoryStore) sortedPromotionsLocked() []models.Promotion {
+	promotions := make([]models.Promotion, 0, len(s.promotions))
+	for _, p := range s.promotions {
+		promotions = append(promotions, p)
+	}
+	sort.Slice(promotions, func(i, j int) bool { return promotions[i].ID < promotions[j].ID })
+	return promotions
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kasir-api/models"
+)
+
+// VoidTransaction membatalkan seluruh transaksi pada hari yang sama dan
+// mengembalikan semua barang ke stok. Void butuh persetujuan manajer.
+func (s *MemoryStore) VoidTransaction(ctx context.Context, id int, req models.VoidRequest) (*models.Reversal, error) {
+	if err := validateReversalActor(req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	approval, err := requireApproval(ctx, models.ApprovalActionVoid, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transactions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: transaction id %d", ErrNotFound, id)
+	}
+	if t.Status != models.TransactionStatusCompleted {
+		return nil, fmt.Errorf("%w: transaction %d is %s, only completed transactions can be voided",
+			ErrReversalNotAllowed, id, t.Status)
+	}
+	if !sameDay(t.CreatedAt, time.Now()) {
+		return nil, fmt.Errorf("%w: transaction %d was not made today, use refund instead", ErrReversalNotAllowed, id)
+	}
+	if shift, ok := s.shifts[t.ShiftID]; ok && shift.Status != models.ShiftStatusOpen {
+		return nil, fmt.Errorf("%w: shift %d is closed", ErrReversalNotAllowed, t.ShiftID)
+	}
+
+	items, _, err := planReversal(t.Details, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pointsReversed, pointsRestored := 0, 0
+	if t.CustomerID > 0 {
+		pointsReversed, pointsRestored = reversalPoints(t.Details, items), t.PointsRedeemed
+	}
+	s.reverseTransactionPointsLocked(t.CustomerID, id, pointsReversed, pointsRestored)
+	s.releaseVoucherLocked(id)
+	reversal := s.applyReversal(ctx, &t, models.Reversal{
+		TransactionID:  id,
+		Type:           models.ReversalTypeVoid,
+		ShiftID:        t.ShiftID,
+		Amount:         t.TotalAmount,
+		PointsAmount:   memoryPointsPaid(t),
+		PointsReversed: pointsReversed,
+		PointsRestored: pointsRestored,
+		Reason:         req.Reason,
+		Operator:       req.Operator,
+		Items:          items,
+	})
+	t.Status = models.TransactionStatusVoided
+	s.transactions[id] = t
+	s.addApprovalLocked(approval, id)
+
+	return &reversal, nil
+}
+
+// RefundTransaction mengembalikan sebagian atau seluruh barang dari transaksi
+// dan menambah stok kembali.
+func (s *MemoryStore) RefundTransaction(ctx context.Context, id int, req models.RefundRequest) (*models.Reversal, error) {
+	if err := validateReversalActor(req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	if err := validateRefundRegister(req.Register); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transactions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: transaction id %d", ErrNotFound, id)
+	}
+	if t.Status != models.TransactionStatusCompleted && t.Status != models.TransactionStatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: transaction %d is %s", ErrReversalNotAllowed, id, t.Status)
+	}
+	shiftID, err := s.openShiftLocked(req.Register)
+	if errors.Is(err, ErrConflict) {
+		return nil, fmt.Errorf("%w: no open shift on register %q", ErrReversalNotAllowed, req.Register)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, amount, err := planReversal(t.Details, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	pointsAmount, pointsRestored := refundPointsShare(t.Details, amount, memoryPointsPaid(t), t.PointsRedeemed)
+	pointsReversed := 0
+	if t.CustomerID > 0 {
+		pointsReversed = reversalPoints(t.Details, items)
+	} else {
+		pointsRestored = 0
+	}
+	s.reverseTransactionPointsLocked(t.CustomerID, id, pointsReversed, pointsRestored)
+
+	status := statusAfterRefund(t.Details, items)
+	reversal := s.applyReversal(ctx, &t, models.Reversal{
+		TransactionID:  id,
+		Type:           models.ReversalTypeRefund,
+		ShiftID:        shiftID,
+		Amount:         amount,
+		PointsAmount:   pointsAmount,
+		PointsReversed: pointsReversed,
+		PointsRestored: pointsRestored,
+		Reason:         req.Reason,
+		Operator:       req.Operator,
+		Items:          items,
+	})
+	t.Status = status
+	s.transactions[id] = t
+
+	return &reversal, nil
+}
+
+// applyReversal memberi ID pada reversal, mengembalikan barang ke stok, dan
+// mencatat jumlah yang dikembalikan pada detail transaksi. Pemanggil harus
+// memegang s.mu.
+func (s *MemoryStore) applyReversal(ctx context.Context, t *models.Transaction, r models.Reversal) models.Reversal {
+	r.ID = s.nextReversalID
+	r.CreatedAt = time.Now()
+	s.nextReversalID++
+
+	details := append([]models.TransactionDetail(nil), t.Details...)
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.ID = s.nextReversalItem
+		item.ReversalID = r.ID
+		s.nextReversalItem++
+
+		reason := models.StockReasonRefund
+		if r.Type == models.ReversalTypeVoid {
+			reason = models.StockReasonVoid
+		}
+		if _, ok := s.produk[item.ProductID]; ok {
+			s.adjustStockLocked(ctx, models.StockMovement{
+				ProductID:   item.ProductID,
+				Delta:       item.Quantity,
+				Reason:      reason,
+				ReferenceID: &r.ID,
+				Operator:    r.Operator,
+				Note:        r.Reason,
+			})
+		}
+		for j := range details {
+			if details[j].ID == item.TransactionDetailID {
+				details[j].RefundedQuantity += item.Quantity
+			}
+		}
+	}
+
+	t.Details = details
+	t.Reversals = append(t.Reversals, r)
+
+	result := r
+	result.Items = append([]models.ReversalItem(nil), r.Items...)
+	return result
+}
+
+// sameDay melaporkan apakah a dan b jatuh pada tanggal kalender yang sama.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// memoryPointsPaid mengembalikan jumlah rupiah transaksi yang dibayar dengan poin.
+func memoryPointsPaid(t models.Transaction) int {
+	amount := 0
+	for _, p := range t.Payments {
+		if p.Method == models.PaymentMethodPoints {
+			amount += p.Amount
+		}
+	}
+	return amount
+}
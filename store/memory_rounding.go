@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kasir-api/models"
+)
+
+// GetAllRoundingPolicies mengembalikan semua kebijakan pembulatan urut metode.
+func (s *MemoryStore) GetAllRoundingPolicies(ctx context.Context) ([]models.RoundingPolicy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]models.RoundingPolicy, 0, len(s.rounding))
+	for _, method := range sortedPolicyMethods(s.rounding) {
+		list = append(list, s.rounding[method])
+	}
+	return list, nil
+}
+
+// SetRoundingPolicy menyimpan kebijakan pembulatan untuk satu metode pembayaran.
+func (s *MemoryStore) SetRoundingPolicy(ctx context.Context, p models.RoundingPolicy) (models.RoundingPolicy, error) {
+	if err := validateRoundingPolicy(p); err != nil {
+		return models.RoundingPolicy{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var before any
+	action := models.AuditActionCreate
+	if old, ok := s.rounding[p.Method]; ok {
+		before, action = old, models.AuditActionUpdate
+	}
+
+	p.UpdatedAt = time.Now()
+	s.rounding[p.Method] = p
+	s.addAuditLocked(ctx, models.AuditEntityRoundingPolicy, action, p.Method, before, p)
+	return p, nil
+}
+
+// DeleteRoundingPolicy menghapus kebijakan pembulatan untuk satu metode pembayaran.
+func (s *MemoryStore) DeleteRoundingPolicy(ctx context.Context, method string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.rounding[method]
+	if !ok {
+		return fmt.Errorf("%w: rounding policy for %s", ErrNotFound, method)
+	}
+	delete(s.rounding, method)
+	s.addAuditLocked(ctx, models.AuditEntityRoundingPolicy, models.AuditActionDelete, method, before, nil)
+	return nil
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// OpenShift membuka shift baru pada register. Satu register hanya boleh punya satu shift terbuka.
+func (s *MemoryStore) OpenShift(ctx context.Context, req models.OpenShiftRequest) (*models.Shift, error) {
+	req, err := prepareOpenShift(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shift := range s.shifts {
+		if shift.Register == req.Register && shift.Status == models.ShiftStatusOpen {
+			return nil, fmt.Errorf("%w: register %q already has an open shift", ErrConflict, req.Register)
+		}
+	}
+
+	shift := models.Shift{
+		ID:           s.nextShiftID,
+		Register:     req.Register,
+		Cashier:      req.Cashier,
+		Status:       models.ShiftStatusOpen,
+		OpeningFloat: req.OpeningFloat,
+		OpenedAt:     time.Now(),
+	}
+	s.nextShiftID++
+	s.shifts[shift.ID] = shift
+
+	return s.shiftLocked(shift.ID)
+}
+
+// GetAllShifts mengembalikan shift sesuai filter tanpa laporan, terbaru lebih dulu.
+func (s *MemoryStore) GetAllShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shifts := make([]models.Shift, 0, len(s.shifts))
+	for _, shift := range s.shifts {
+		if filter.Register != "" && shift.Register != filter.Register {
+			continue
+		}
+		if filter.Status != "" && shift.Status != filter.Status {
+			continue
+		}
+		shifts = append(shifts, shift)
+	}
+	sort.Slice(shifts, func(i, j int) bool { return shifts[i].ID > shifts[j].ID })
+	return shifts, nil
+}
+
+// GetShift mengembalikan satu shift beserta rekonsiliasi per metode pembayaran.
+func (s *MemoryStore) GetShift(ctx context.Context, id int) (*models.Shift, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.shiftLocked(id)
+}
+
+// CloseShift menutup shift dengan hasil hitung laci, lalu membekukan uang tunai
+// yang seharusnya ada.
+func (s *MemoryStore) CloseShift(ctx context.Context, id int, req models.CloseShiftRequest) (*models.Shift, error) {
+	counts, err := shiftCounts(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shift, ok := s.shifts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: shift id %d", ErrNotFound, id)
+	}
+	if shift.Status != models.ShiftStatusOpen {
+		return nil, fmt.Errorf("%w: shift %d is already %s", ErrConflict, id, shift.Status)
+	}
+
+	s.shiftCounts[id] = counts
+	closed, err := s.shiftLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	closedAt := time.Now()
+	closed.Status, closed.Note, closed.ClosedBy, closed.ClosedAt = models.ShiftStatusClosed, req.Note, req.Operator, &closedAt
+
+	stored := *closed
+	stored.Report, stored.CashMovements, stored.NoSales = nil, nil, nil
+	s.shifts[id] = stored
+
+	return closed, nil
+}
+
+// RecordCashMovement mencatat pay-in/pay-out pada shift yang terbuka di register.
+func (s *MemoryStore) RecordCashMovement(ctx context.Context, req models.CashMovementRequest) (*models.CashMovement, error) {
+	req, err := prepareCashMovement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shiftID, err := s.openShiftLocked(req.Register)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.CashMovement{
+		ID:        s.nextCashMovementID,
+		ShiftID:   shiftID,
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		Operator:  req.Operator,
+		CreatedAt: time.Now(),
+	}
+	s.nextCashMovementID++
+	s.cashMovements = append(s.cashMovements, m)
+
+	return &m, nil
+}
+
+// RecordNoSale mencatat laci yang dibuka tanpa penjualan pada shift yang
+// terbuka di register. Aksi ini butuh persetujuan manajer.
+func (s *MemoryStore) RecordNoSale(ctx context.Context, req models.NoSaleRequest) (*models.NoSale, error) {
+	req, err := prepareNoSale(req)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := requireApproval(ctx, models.ApprovalActionNoSale, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shiftID, err := s.openShiftLocked(req.Register)
+	if err != nil {
+		return nil, err
+	}
+
+	n := models.NoSale{ID: s.nextNoSaleID, ShiftID: shiftID, Reason: req.Reason, Operator: req.Operator, CreatedAt: time.Now()}
+	s.nextNoSaleID++
+	s.noSales = append(s.noSales, n)
+	s.addApprovalLocked(approval, n.ID)
+
+	return &n, nil
+}
+
+// shiftLocked menyusun shift beserta laporannya dari transaksi, reversal, dan
+// pay-in/pay-out yang tercatat pada shift tersebut. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) shiftLocked(id int) (*models.Shift, error) {
+	shift, ok := s.shifts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: shift id %d", ErrNotFound, id)
+	}
+
+	var sales []shiftSale
+	var reversals []models.Reversal
+	for _, t := range s.transactions {
+		if t.ShiftID == id {
+			sales = append(sales, shiftSale{TransactionID: t.ID, Breakdown: paymentBreakdown(t.Payments, t.ChangeAmount)})
+		}
+		for _, r := range t.Reversals {
+			if r.ShiftID == id {
+				reversals = append(reversals, r)
+			}
+		}
+	}
+
+	for _, m := range s.cashMovements {
+		if m.ShiftID == id {
+			shift.CashMovements = append(shift.CashMovements, m)
+		}
+	}
+	for _, n := range s.noSales {
+		if n.ShiftID == id {
+			shift.NoSales = append(shift.NoSales, n)
+		}
+	}
+
+	applyShiftReport(&shift, shiftReport(shift.OpeningFloat, sales, reversals, shift.CashMovements, s.shiftCounts[id]))
+	return &shift, nil
+}
+
+// openShiftLocked mencari shift yang terbuka pada register, padanan
+// lockOpenShift. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) openShiftLocked(register string) (int, error) {
+	register = strings.TrimSpace(register)
+	if register == "" {
+		return 0, fmt.Errorf("%w: register is required", ErrInvalidInput)
+	}
+	for _, shift := range s.shifts {
+		if shift.Register == register && shift.Status == models.ShiftStatusOpen {
+			return shift.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no open shift on register %q", ErrConflict, register)
+}
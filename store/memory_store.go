@@ -0,0 +1,675 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kasir-api/models"
+)
+
+// MemoryStore mengimplementasikan semua store di memori, dipakai untuk test
+// handler tanpa PostgreSQL. Semua data dilindungi satu mutex agar checkout
+// tetap atomik seperti database transaction.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	produk          map[int]models.Produk
+	kategori        map[int]models.Kategori
+	transactions    map[int]models.Transaction
+	movements       []models.StockMovement
+	promotions      map[int]models.Promotion
+	vouchers        map[int]models.Voucher
+	redemptions     []models.VoucherRedemption
+	taxRules        map[int]models.TaxRule
+	rounding        map[string]models.RoundingPolicy
+	customers       map[int]models.Customer
+	loyalty         models.LoyaltySettings
+	multipliers     map[int]models.LoyaltyMultiplier
+	points          []models.PointsEntry
+	shifts          map[int]models.Shift
+	shiftCounts     map[int]map[string]int
+	cashMovements   []models.CashMovement
+	users           map[int]models.User
+	passwordHashes  map[int]string
+	sessions        map[string]memorySession
+	pinHashes       map[int]string
+	approvalTokens  map[string]memoryApprovalToken
+	pinFailures     map[string]memoryPINFailure
+	approvals       []models.Approval
+	noSales         []models.NoSale
+	apiKeys         map[int]models.APIKey
+	apiKeyHashes    map[int]string
+	auditLog        []models.AuditEntry
+	idempotencyKeys map[memoryIdempotencyID]memoryIdempotencyKey
+
+	nextProdukID       int
+	nextKategoriID     int
+	nextTransactionID  int
+	nextDetailID       int
+	nextPaymentID      int
+	nextReversalID     int
+	nextReversalItem   int
+	nextMovementID     int
+	nextPromotionID    int
+	nextAppliedPromo   int
+	nextVoucherID      int
+	nextRedemptionID   int
+	nextTaxRuleID      int
+	nextAppliedTax     int
+	nextCustomerID     int
+	nextPointsID       int
+	nextShiftID        int
+	nextCashMovementID int
+	nextUserID         int
+	nextApprovalID     int
+	nextNoSaleID       int
+	nextAPIKeyID       int
+	nextAuditID        int
+}
+
+// NewMemoryStore membuat MemoryStore kosong.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		produk:             make(map[int]models.Produk),
+		kategori:           make(map[int]models.Kategori),
+		transactions:       make(map[int]models.Transaction),
+		promotions:         make(map[int]models.Promotion),
+		vouchers:           make(map[int]models.Voucher),
+		taxRules:           make(map[int]models.TaxRule),
+		rounding:           make(map[string]models.RoundingPolicy),
+		customers:          make(map[int]models.Customer),
+		multipliers:        make(map[int]models.LoyaltyMultiplier),
+		shifts:             make(map[int]models.Shift),
+		shiftCounts:        make(map[int]map[string]int),
+		users:              make(map[int]models.User),
+		passwordHashes:     make(map[int]string),
+		sessions:           make(map[string]memorySession),
+		pinHashes:          make(map[int]string),
+		approvalTokens:     make(map[string]memoryApprovalToken),
+		pinFailures:        make(map[string]memoryPINFailure),
+		apiKeys:            make(map[int]models.APIKey),
+		apiKeyHashes:       make(map[int]string),
+		idempotencyKeys:    make(map[memoryIdempotencyID]memoryIdempotencyKey),
+		nextProdukID:       1,
+		nextKategoriID:     1,
+		nextTransactionID:  1,
+		nextDetailID:       1,
+		nextPaymentID:      1,
+		nextReversalID:     1,
+		nextReversalItem:   1,
+		nextMovementID:     1,
+		nextPromotionID:    1,
+		nextAppliedPromo:   1,
+		nextVoucherID:      1,
+		nextRedemptionID:   1,
+		nextTaxRuleID:      1,
+		nextAppliedTax:     1,
+		nextCustomerID:     1,
+		nextPointsID:       1,
+		nextShiftID:        1,
+		nextCashMovementID: 1,
+		nextUserID:         1,
+		nextApprovalID:     1,
+		nextNoSaleID:       1,
+		nextAPIKeyID:       1,
+		nextAuditID:        1,
+	}
+}
+
+// GetAll mengembalikan semua data produk dengan filter nama (opsional).
+func (s *MemoryStore) GetAll(ctx context.Context, nameFilter string) []models.Produk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := strings.ToLower(nameFilter)
+	produk := make([]models.Produk, 0, len(s.produk))
+	for _, p := range s.produk {
+		if filter != "" && !strings.Contains(strings.ToLower(p.Nama), filter) {
+			continue
+		}
+		produk = append(produk, p)
+	}
+	sort.Slice(produk, func(i, j int) bool { return produk[i].ID < produk[j].ID })
+
+	return produk
+}
+
+// GetByID mengembalikan satu produk berdasarkan ID.
+func (s *MemoryStore) GetByID(ctx context.Context, id int) (models.Produk, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.produk[id]
+	return p, ok
+}
+
+// Add menambahkan produk baru ke penyimpanan dan mengembalikan produk dengan ID
+func (s *MemoryStore) Add(ctx context.Context, p models.Produk) (models.Produk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.ID = s.nextProdukID
+	s.nextProdukID++
+	stok := p.Stok
+	p.Stok = 0
+	s.produk[p.ID] = p
+
+	// Stok awal dicatat sebagai adjustment di ledger.
+	s.adjustStockLocked(ctx, models.StockMovement{
+		ProductID: p.ID,
+		Delta:     stok,
+		Reason:    models.StockReasonAdjustment,
+		Note:      "stok awal produk",
+	})
+	s.addAuditLocked(ctx, models.AuditEntityProduk, models.AuditActionCreate, p.ID, nil, s.produk[p.ID])
+
+	return s.produk[p.ID], nil
+}
+
+// Update mengganti data produk berdasarkan ID. Field stok di body diabaikan,
+// sama seperti PostgresStore.
+func (s *MemoryStore) Update(ctx context.Context, id int, p models.Produk) (models.Produk, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.produk[id]
+	if !ok {
+		return models.Produk{}, fmt.Errorf("%w: product id %d", ErrNotFound, id)
+	}
+
+	p.ID = id
+	p.Stok = current.Stok
+	s.produk[id] = p
+	if a, ok := approvalFor(ctx, models.ApprovalActionProdukUpdate); ok {
+		s.addApprovalLocked(a, id)
+	}
+	s.addAuditLocked(ctx, models.AuditEntityProduk, models.AuditActionUpdate, id, current, s.produk[id])
+
+	return s.produk[id], nil
+}
+
+// Delete menghapus produk berdasarkan ID.
+func (s *MemoryStore) Delete(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.produk[id]
+	if !ok {
+		return fmt.Errorf("%w: product id %d", ErrNotFound, id)
+	}
+
+	delete(s.produk, id)
+	if a, ok := approvalFor(ctx, models.ApprovalActionProdukDelete); ok {
+		s.addApprovalLocked(a, id)
+	}
+	s.addAuditLocked(ctx, models.AuditEntityProduk, models.AuditActionDelete, id, before, nil)
+
+	// Sama seperti ON DELETE SET NULL, riwayat stok tetap ada tanpa referensi produk.
+	for i := range s.movements {
+		if s.movements[i].ProductID == id {
+			s.movements[i].ProductID = 0
+		}
+	}
+
+	// Promosi dan aturan pajak khusus produk ini ikut terhapus.
+	for promoID, p := range s.promotions {
+		if p.ProductID == id {
+			delete(s.promotions, promoID)
+		}
+	}
+	for ruleID, r := range s.taxRules {
+		if r.ProductID == id {
+			delete(s.taxRules, ruleID)
+		}
+	}
+
+	// Sama seperti ON DELETE SET NULL, riwayat penjualan tetap ada tanpa referensi produk.
+	for _, t := range s.transactions {
+		for i := range t.Details {
+			if t.Details[i].ProductID == id {
+				t.Details[i].ProductID = 0
+			}
+		}
+		for i := range t.Reversals {
+			for j := range t.Reversals[i].Items {
+				if t.Reversals[i].Items[j].ProductID == id {
+					t.Reversals[i].Items[j].ProductID = 0
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetAllKategori mengembalikan semua kategori
+func (s *MemoryStore) GetAllKategori(ctx context.Context) []models.Kategori {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kategori := make([]models.Kategori, 0, len(s.kategori))
+	for _, k := range s.kategori {
+		kategori = append(kategori, k)
+	}
+	sort.Slice(kategori, func(i, j int) bool { return kategori[i].ID < kategori[j].ID })
+
+	return kategori
+}
+
+// GetKategoriByID mencari kategori berdasarkan ID
+func (s *MemoryStore) GetKategoriByID(ctx context.Context, id int) (models.Kategori, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.kategori[id]
+	return k, ok
+}
+
+// AddKategori menambahkan kategori baru dan mengembalikan kategori dengan ID
+func (s *MemoryStore) AddKategori(ctx context.Context, k models.Kategori) (models.Kategori, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k.ID = s.nextKategoriID
+	s.nextKategoriID++
+	s.kategori[k.ID] = k
+	s.addAuditLocked(ctx, models.AuditEntityKategori, models.AuditActionCreate, k.ID, nil, k)
+
+	return k, nil
+}
+
+// UpdateKategori mengupdate kategori yang sudah ada
+func (s *MemoryStore) UpdateKategori(ctx context.Context, id int, updated models.Kategori) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.kategori[id]
+	if !ok {
+		return false
+	}
+
+	updated.ID = id
+	s.kategori[id] = updated
+	s.addAuditLocked(ctx, models.AuditEntityKategori, models.AuditActionUpdate, id, before, updated)
+	return true
+}
+
+// DeleteKategori menghapus kategori berdasarkan ID. Sama seperti ON DELETE SET NULL
+// di database, produk yang memakai kategori ini dilepas dari kategorinya.
+func (s *MemoryStore) DeleteKategori(ctx context.Context, id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.kategori[id]
+	if !ok {
+		return false
+	}
+
+	delete(s.kategori, id)
+	s.addAuditLocked(ctx, models.AuditEntityKategori, models.AuditActionDelete, id, before, nil)
+	for pid, p := range s.produk {
+		if p.KategoriID == id {
+			p.KategoriID = 0
+			s.produk[pid] = p
+		}
+	}
+
+	// Sama seperti ON DELETE CASCADE, promosi, aturan pajak, dan pengali poin untuk kategori ini ikut terhapus.
+	for promoID, p := range s.promotions {
+		if p.KategoriID == id {
+			delete(s.promotions, promoID)
+		}
+	}
+	for ruleID, r := range s.taxRules {
+		if r.KategoriID == id {
+			delete(s.taxRules, ruleID)
+		}
+	}
+	delete(s.multipliers, id)
+	return true
+}
+
+// CreateTransaction membuat transaksi baru beserta detailnya secara atomik.
+func (s *MemoryStore) CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	items, lines, err := normalizeCheckoutItems(req.Items, req.MaxLineQuantity)
+	if err != nil {
+		return nil, err
+	}
+	req.Items = items
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shiftID, err := s.openShiftLocked(req.Register)
+	if err != nil {
+		return nil, err
+	}
+	if req.IdempotencyKey != "" {
+		if err := s.checkCheckoutKeyLocked(req); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := s.customers[req.CustomerID]; req.CustomerID > 0 && !ok {
+		return nil, fmt.Errorf("%w: customer id %d not found", ErrInvalidInput, req.CustomerID)
+	}
+
+	details := make([]models.TransactionDetail, 0)
+	var itemsErr CheckoutItemsError
+	var approvalReasons []string
+
+	// Validasi semua item dulu sebelum stok diubah, seperti rollback di database.
+	for i, item := range req.Items {
+		p, ok := s.produk[item.ProductID]
+		if !ok {
+			log.Printf("[memory-store] Product not found id=%d", item.ProductID)
+			itemsErr.add(ErrInvalidInput, lines[i], item.ProductID, "product_id", "product not found")
+			continue
+		}
+
+		if p.Stok < item.Quantity {
+			itemsErr.add(ErrInsufficientStock, lines[i], item.ProductID, "quantity",
+				fmt.Sprintf("insufficient stock for product %s (requested: %d, available: %d)", p.Nama, item.Quantity, p.Stok))
+			continue
+		}
+
+		unitPrice, reason, err := itemPrice(item, p.Harga)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			approvalReasons = append(approvalReasons, reason)
+		}
+
+		// Simpan nama, kategori, dan harga saat ini sebagai snapshot di detail.
+		details = append(details, models.TransactionDetail{
+			ProductID:    item.ProductID,
+			ProductName:  p.Nama,
+			KategoriID:   p.KategoriID,
+			KategoriNama: s.kategori[p.KategoriID].Nama,
+			Quantity:     item.Quantity,
+			UnitPrice:    unitPrice,
+			CostPrice:    p.HargaBeli,
+		})
+	}
+	if err := itemsErr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	// Terapkan promosi yang aktif lalu hitung total setelah potongan.
+	applied := applyPromotions(details, s.sortedPromotionsLocked(), time.Now())
+
+	var voucher *models.Voucher
+	voucherAmount := 0
+	if req.VoucherCode != "" {
+		voucher, voucherAmount, err = s.applyVoucherLocked(req.VoucherCode, req.CustomerID, details)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reason, err := applyManualDiscount(details, req)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		approvalReasons = append(approvalReasons, reason)
+	}
+	approval, err := checkoutApproval(ctx, approvalReasons)
+	if err != nil {
+		return nil, err
+	}
+
+	subtotalAmount, lineDiscount, cartDiscount, _ := sumDetails(details)
+	cartDiscount -= voucherAmount + req.ManualDiscount
+
+	appliedTaxes := applyTaxes(details, s.sortedTaxRulesLocked())
+	taxAmount, serviceCharge, taxIncluded, totalAmount := sumTaxes(details)
+	rounding := roundingAdjustment(totalAmount, req.Payments, s.rounding)
+	totalAmount += rounding
+
+	paidAmount, changeAmount, err := settlePayments(totalAmount, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hitung dan validasi poin sebelum data apa pun diubah. ID transaksi berikutnya
+	// sudah diketahui sehingga entri ledger bisa langsung merujuknya.
+	pointsAmount, pointsRedeemed, err := pointsPayment(req.Payments, req.CustomerID, s.loyalty)
+	if err != nil {
+		return nil, err
+	}
+	var account *pointsAccount
+	pointsEarned := 0
+	if req.CustomerID > 0 {
+		now := time.Now()
+		pointsEarned = earnPoints(details, s.loyalty, s.multiplierRatesLocked(), pointsAmount)
+		account = s.pointsAccountLocked(req.CustomerID)
+		account.expire(now)
+		if err := account.redeem(s.nextTransactionID, pointsRedeemed); err != nil {
+			return nil, err
+		}
+		account.credit(models.PointsTypeEarn, s.nextTransactionID, pointsEarned, pointsExpiry(s.loyalty, now))
+	}
+
+	transaction := models.Transaction{
+		ID:                 s.nextTransactionID,
+		Status:             models.TransactionStatusCompleted,
+		CustomerID:         req.CustomerID,
+		ShiftID:            shiftID,
+		SubtotalAmount:     subtotalAmount,
+		LineDiscount:       lineDiscount,
+		CartDiscount:       cartDiscount,
+		VoucherDiscount:    voucherAmount,
+		ManualDiscount:     req.ManualDiscount,
+		TaxAmount:          taxAmount,
+		ServiceCharge:      serviceCharge,
+		TaxIncluded:        taxIncluded,
+		RoundingAdjustment: rounding,
+		TotalAmount:        totalAmount,
+		PaidAmount:         paidAmount,
+		ChangeAmount:       changeAmount,
+		PointsEarned:       pointsEarned,
+		PointsRedeemed:     pointsRedeemed,
+		CreatedAt:          time.Now(),
+	}
+	s.nextTransactionID++
+
+	for _, p := range req.Payments {
+		transaction.Payments = append(transaction.Payments, models.TransactionPayment{
+			ID:            s.nextPaymentID,
+			TransactionID: transaction.ID,
+			Method:        p.Method,
+			Amount:        p.Amount,
+			Reference:     p.Reference,
+		})
+		s.nextPaymentID++
+	}
+	transaction.PaymentBreakdown = paymentBreakdown(transaction.Payments, changeAmount)
+
+	for i := range details {
+		details[i].ID = s.nextDetailID
+		details[i].TransactionID = transaction.ID
+		s.nextDetailID++
+
+		s.adjustStockLocked(ctx, models.StockMovement{
+			ProductID:   details[i].ProductID,
+			Delta:       -details[i].Quantity,
+			Reason:      models.StockReasonSale,
+			ReferenceID: &transaction.ID,
+		})
+	}
+	transaction.Details = details
+
+	for _, a := range applied {
+		ap := models.AppliedPromotion{
+			ID:            s.nextAppliedPromo,
+			TransactionID: transaction.ID,
+			PromotionID:   a.Promotion.ID,
+			PromotionName: a.Promotion.Name,
+			Amount:        a.Amount,
+		}
+		s.nextAppliedPromo++
+		if a.Line >= 0 {
+			ap.TransactionDetailID = details[a.Line].ID
+		}
+		transaction.Promotions = append(transaction.Promotions, ap)
+	}
+
+	for _, a := range appliedTaxes {
+		transaction.Taxes = append(transaction.Taxes, models.AppliedTax{
+			ID:                  s.nextAppliedTax,
+			TransactionID:       transaction.ID,
+			TransactionDetailID: details[a.Line].ID,
+			TaxRuleID:           a.Rule.ID,
+			Name:                a.Rule.Name,
+			Type:                a.Rule.Type,
+			Rate:                a.Rule.Rate,
+			Inclusive:           a.Rule.Inclusive,
+			Base:                a.Base,
+			Amount:              a.Amount,
+		})
+		s.nextAppliedTax++
+	}
+
+	if voucher != nil {
+		transaction.VoucherCode = voucher.Code
+		s.redeemVoucherLocked(models.VoucherRedemption{
+			VoucherID:     voucher.ID,
+			TransactionID: transaction.ID,
+			CustomerID:    req.CustomerID,
+			Amount:        voucherAmount,
+		})
+	}
+	if account != nil {
+		s.savePointsAccountLocked(account)
+	}
+	if approval != nil {
+		s.addApprovalLocked(*approval, transaction.ID)
+	}
+	if req.IdempotencyKey != "" {
+		id := memoryIdempotencyID{scope: req.IdempotencyScope, key: req.IdempotencyKey}
+		k := s.idempotencyKeys[id]
+		k.status, k.transactionID = idempotencyStatusCompleted, transaction.ID
+		s.idempotencyKeys[id] = k
+	}
+	s.transactions[transaction.ID] = transaction
+
+	result := copyTransaction(transaction)
+	return &result, nil
+}
+
+// GetTransactionByID mengembalikan satu transaksi berdasarkan ID beserta detailnya.
+func (s *MemoryStore) GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transactions[id]
+	if !ok {
+		return nil, fmt.Errorf("transaction id %d not found", id)
+	}
+
+	result := copyTransaction(t)
+	return &result, nil
+}
+
+// GetAllTransactions mengembalikan semua transaksi sesuai filter (tanpa detail untuk performa).
+func (s *MemoryStore) GetAllTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transactions := make([]models.Transaction, 0, len(s.transactions))
+	for _, t := range s.transactions {
+		if filter.PaymentMethod != "" && !hasPaymentMethod(t.Payments, filter.PaymentMethod) {
+			continue
+		}
+		if filter.CustomerID > 0 && t.CustomerID != filter.CustomerID {
+			continue
+		}
+		if filter.ShiftID > 0 && t.ShiftID != filter.ShiftID {
+			continue
+		}
+		t.Details = nil
+		t.Payments = nil
+		t.PaymentBreakdown = nil
+		t.Reversals = nil
+		t.Promotions = nil
+		t.Taxes = nil
+		transactions = append(transactions, t)
+	}
+	sort.Slice(transactions, func(i, j int) bool { return transactions[i].ID > transactions[j].ID })
+
+	return transactions, nil
+}
+
+// GetStockHistory mengembalikan riwayat pergerakan stok satu produk, terbaru lebih dulu.
+func (s *MemoryStore) GetStockHistory(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	movements := []models.StockMovement{}
+	for i := len(s.movements) - 1; i >= 0; i-- {
+		if s.movements[i].ProductID == productID {
+			movements = append(movements, s.movements[i])
+		}
+	}
+	return movements, nil
+}
+
+// adjustStockLocked mengubah stok produk dan mencatatnya di ledger, padanan
+// adjustStock untuk backend memori. Pemanggil harus memegang s.mu.
+func (s *MemoryStore) adjustStockLocked(ctx context.Context, m models.StockMovement) models.StockMovement {
+	if m.Delta == 0 {
+		return m
+	}
+	if m.Operator == "" {
+		m.Operator = auditSourceFromContext(ctx).actor
+	}
+
+	p := s.produk[m.ProductID]
+	p.Stok += m.Delta
+	s.produk[m.ProductID] = p
+
+	m.ID = s.nextMovementID
+	s.nextMovementID++
+	m.ProductName = p.Nama
+	m.StokAfter = p.Stok
+	m.CreatedAt = time.Now()
+	if m.ReferenceID != nil {
+		ref := *m.ReferenceID
+		m.ReferenceID = &ref
+	}
+	s.movements = append(s.movements, m)
+	return m
+}
+
+// copyTransaction menyalin transaksi beserta slice detailnya agar pemanggil
+// tidak bisa mengubah data yang disimpan.
+func copyTransaction(t models.Transaction) models.Transaction {
+	t.Details = append([]models.TransactionDetail(nil), t.Details...)
+	t.Payments = append([]models.TransactionPayment(nil), t.Payments...)
+	t.PaymentBreakdown = append([]models.PaymentBreakdown(nil), t.PaymentBreakdown...)
+	t.Promotions = append([]models.AppliedPromotion(nil), t.Promotions...)
+	t.Taxes = append([]models.AppliedTax(nil), t.Taxes...)
+	if t.Reversals != nil {
+		reversals := make([]models.Reversal, len(t.Reversals))
+		for i, r := range t.Reversals {
+			r.Items = append([]models.ReversalItem(nil), r.Items...)
+			reversals[i] = r
+		}
+		t.Reversals = reversals
+	}
+	return t
+}
+
+// hasPaymentMethod melaporkan apakah salah satu pembayaran memakai method.
+func hasPaymentMethod(payments []models.TransactionPayment, method string) bool {
+	for _, p := range payments {
+		if p.Method == method {
+			return true
+		}
+	}
+	return false
+}
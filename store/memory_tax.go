@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// AddTaxRule menambahkan aturan pajak atau service charge baru.
+func (s *MemoryStore) AddTaxRule(ctx context.Context, r models.TaxRule) (models.TaxRule, error) {
+	if err := validateTaxRule(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTaxRuleTargetLocked(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	r.ID = s.nextTaxRuleID
+	r.CreatedAt = time.Now()
+	s.nextTaxRuleID++
+	s.taxRules[r.ID] = r
+	s.addAuditLocked(ctx, models.AuditEntityTaxRule, models.AuditActionCreate, r.ID, nil, r)
+	return r, nil
+}
+
+// GetAllTaxRules mengembalikan semua aturan pajak urut ID.
+func (s *MemoryStore) GetAllTaxRules(ctx context.Context) ([]models.TaxRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedTaxRulesLocked(), nil
+}
+
+// GetTaxRuleByID mengembalikan satu aturan pajak berdasarkan ID.
+func (s *MemoryStore) GetTaxRuleByID(ctx context.Context, id int) (models.TaxRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.taxRules[id]
+	if !ok {
+		return models.TaxRule{}, fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	return r, nil
+}
+
+// UpdateTaxRule mengganti aturan pajak berdasarkan ID.
+func (s *MemoryStore) UpdateTaxRule(ctx context.Context, id int, r models.TaxRule) (models.TaxRule, error) {
+	if err := validateTaxRule(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.taxRules[id]
+	if !ok {
+		return models.TaxRule{}, fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	if err := s.checkTaxRuleTargetLocked(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	r.ID, r.CreatedAt = id, current.CreatedAt
+	s.taxRules[id] = r
+	s.addAuditLocked(ctx, models.AuditEntityTaxRule, models.AuditActionUpdate, id, current, r)
+	return r, nil
+}
+
+// DeleteTaxRule menghapus aturan pajak berdasarkan ID. Seperti ON DELETE SET
+// NULL, catatan pajak pada transaksi lama tetap ada tanpa referensi aturan.
+func (s *MemoryStore) DeleteTaxRule(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.taxRules[id]
+	if !ok {
+		return fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	delete(s.taxRules, id)
+	s.addAuditLocked(ctx, models.AuditEntityTaxRule, models.AuditActionDelete, id, before, nil)
+
+	for _, t := range s.transactions {
+		for i := range t.Taxes {
+			if t.Taxes[i].TaxRuleID == id {
+				t.Taxes[i].TaxRuleID = 0
+			}
+		}
+	}
+	return nil
+}
+
+// checkTaxRuleTargetLocked memastikan produk/kategori target aturan pajak ada,
+// seperti foreign key di database.
+func (s *MemoryStore) checkTaxRuleTargetLocked(r models.TaxRule) error {
+	if _, ok := s.produk[r.ProductID]; r.ProductID > 0 && !ok {
+		return fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if _, ok := s.kategori[r.KategoriID]; r.KategoriID > 0 && !ok {
+		return fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	return nil
+}
+
+// sortedTaxRulesLocked mengembalikan salinan semua aturan pajak urut ID, sama
+// seperti urutan yang dievaluasi PostgresStore saat checkout.
+func (s *MemoryStore) sortedTaxRulesLocked() []models.TaxRule {
+	rules := make([]models.TaxRule, 0, len(s.taxRules))
+	for _, r := range s.taxRules {
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// memorySession adalah padanan baris user_sessions di MemoryStore.
+type memorySession struct {
+	userID    int
+	expiresAt time.Time
+}
+
+// CreateUser membuat pengguna baru dengan password yang di-hash.
+func (s *MemoryStore) CreateUser(ctx context.Context, req models.CreateUserRequest) (models.User, error) {
+	req, err := prepareUser(req)
+	if err != nil {
+		return models.User{}, err
+	}
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Username == req.Username {
+			return models.User{}, fmt.Errorf("%w: username %q already exists", ErrConflict, req.Username)
+		}
+	}
+
+	now := time.Now()
+	u := models.User{ID: s.nextUserID, Username: req.Username, Role: req.Role, Active: true, CreatedAt: now, UpdatedAt: now}
+	s.nextUserID++
+	s.users[u.ID] = u
+	s.passwordHashes[u.ID] = passwordHash
+	s.addAuditLocked(ctx, models.AuditEntityUser, models.AuditActionCreate, u.ID, nil, u)
+	return u, nil
+}
+
+// GetAllUsers mengembalikan semua pengguna urut ID.
+func (s *MemoryStore) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]models.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// UpdateUser mengubah role, status aktif, password, atau PIN pengguna.
+func (s *MemoryStore) UpdateUser(ctx context.Context, id int, req models.UpdateUserRequest) (models.User, error) {
+	if err := validateUserUpdate(req); err != nil {
+		return models.User{}, err
+	}
+	passwordHash := ""
+	if req.Password != nil {
+		var err error
+		if passwordHash, err = hashPassword(*req.Password); err != nil {
+			return models.User{}, err
+		}
+	}
+	pinHash, err := hashPIN(req.PIN)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return models.User{}, fmt.Errorf("%w: user id %d", ErrNotFound, id)
+	}
+	owners := 0
+	for _, other := range s.users {
+		if other.Role == models.RoleOwner && other.Active {
+			owners++
+		}
+	}
+	if revokesOwner(u, req) && owners <= 1 {
+		return models.User{}, fmt.Errorf("%w: user %d is the last active owner", ErrConflict, id)
+	}
+
+	before := u
+	u = applyUserUpdate(u, req)
+	if err := validateUserPIN(u, req); err != nil {
+		return models.User{}, err
+	}
+	u.UpdatedAt = time.Now()
+	s.users[id] = u
+	if passwordHash != "" {
+		s.passwordHashes[id] = passwordHash
+	}
+	if req.PIN != nil {
+		s.pinHashes[id] = pinHash
+	}
+	if !u.Active || req.Password != nil {
+		for tokenHash, session := range s.sessions {
+			if session.userID == id {
+				delete(s.sessions, tokenHash)
+			}
+		}
+	}
+	s.addAuditLocked(ctx, models.AuditEntityUser, models.AuditActionUpdate, id, before, u)
+	return u, nil
+}
+
+// Login memeriksa username dan password lalu membuat sesi baru yang berlaku selama ttl.
+func (s *MemoryStore) Login(ctx context.Context, req models.LoginRequest, ttl time.Duration) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	username := normalizeUsername(req.Username)
+	for _, u := range s.users {
+		if u.Username != username {
+			continue
+		}
+		if !verifyPassword(s.passwordHashes[u.ID], req.Password) || !u.Active {
+			break
+		}
+
+		token, tokenHash, err := newSessionToken()
+		if err != nil {
+			return nil, err
+		}
+		expiresAt := time.Now().Add(ttl)
+		s.sessions[tokenHash] = memorySession{userID: u.ID, expiresAt: expiresAt}
+		return &models.Session{Token: token, ExpiresAt: expiresAt, User: u}, nil
+	}
+	return nil, fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
+}
+
+// Authenticate mengembalikan pengguna aktif pemilik token sesi yang belum kedaluwarsa.
+func (s *MemoryStore) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[hashToken(token)]
+	if !ok || !time.Now().Before(session.expiresAt) || !s.users[session.userID].Active {
+		return nil, fmt.Errorf("%w: invalid or expired session", ErrUnauthorized)
+	}
+	u := s.users[session.userID]
+	return &u, nil
+}
+
+// Logout menghapus sesi milik token.
+func (s *MemoryStore) Logout(ctx context.Context, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, hashToken(token))
+	return nil
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"kasir-api/models"
+)
+
+// AddVoucher menambahkan voucher baru. Kode disimpan dalam huruf besar.
+func (s *MemoryStore) AddVoucher(ctx context.Context, v models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(v); err != nil {
+		return models.Voucher{}, err
+	}
+	v.Code = normalizeVoucherCode(v.Code)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.voucherByCodeLocked(v.Code); ok {
+		return models.Voucher{}, fmt.Errorf("%w: voucher code %s already exists", ErrConflict, v.Code)
+	}
+
+	v.ID = s.nextVoucherID
+	v.UsedCount = 0
+	v.CreatedAt = time.Now()
+	s.nextVoucherID++
+	s.vouchers[v.ID] = v
+	s.addAuditLocked(ctx, models.AuditEntityVoucher, models.AuditActionCreate, v.ID, nil, v)
+	return v, nil
+}
+
+// GetAllVouchers mengembalikan semua voucher urut ID.
+func (s *MemoryStore) GetAllVouchers(ctx context.Context) ([]models.Voucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vouchers := make([]models.Voucher, 0, len(s.vouchers))
+	for _, v := range s.vouchers {
+		vouchers = append(vouchers, v)
+	}
+	sort.Slice(vouchers, func(i, j int) bool { return vouchers[i].ID < vouchers[j].ID })
+	return vouchers, nil
+}
+
+// GetVoucherByID mengembalikan satu voucher berdasarkan ID.
+func (s *MemoryStore) GetVoucherByID(ctx context.Context, id int) (models.Voucher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vouchers[id]
+	if !ok {
+		return models.Voucher{}, fmt.Errorf("%w: voucher id %d", ErrNotFound, id)
+	}
+	return v, nil
+}
+
+// UpdateVoucher mengganti aturan voucher berdasarkan ID tanpa mengubah UsedCount.
+func (s *MemoryStore) UpdateVoucher(ctx context.Context, id int, v models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(v); err != nil {
+		return models.Voucher{}, err
+	}
+	v.Code = normalizeVoucherCode(v.Code)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.vouchers[id]
+	if !ok {
+		return models.Voucher{}, fmt.Errorf("%w: voucher id %d", ErrNotFound, id)
+	}
+	if other, ok := s.voucherByCodeLocked(v.Code); ok && other.ID != id {
+		return models.Voucher{}, fmt.Errorf("%w: voucher code %s already exists", ErrConflict, v.Code)
+	}
+	if v.UsageLimit > 0 && current.UsedCount > v.UsageLimit {
+		return models.Voucher{}, fmt.Errorf("%w: usage_limit is below the current used_count", ErrInvalidInput)
+	}
+
+	v.ID, v.UsedCount, v.CreatedAt = id, current.UsedCount, current.CreatedAt
+	s.vouchers[id] = v
+	s.addAuditLocked(ctx, models.AuditEntityVoucher, models.AuditActionUpdate, id, current, v)
+	return v, nil
+}
+
+// voucherByCodeLocked mencari voucher berdasarkan kode yang sudah dinormalisasi.
+func (s *MemoryStore) voucherByCodeLocked(code string) (models.Voucher, bool) {
+	for _, v := range s.vouchers {
+		if v.Code == code {
+			return v, true
+		}
+	}
+	return models.Voucher{}, false
+}
+
+// applyVoucherLocked memeriksa apakah voucher bisa ditukar lalu membagi
+// potongannya ke details, seperti applyVoucher pada PostgresStore.
+func (s *MemoryStore) applyVoucherLocked(code string, customerID int, details []models.TransactionDetail) (*models.Voucher, int, error) {
+	code = normalizeVoucherCode(code)
+	v, ok := s.voucherByCodeLocked(code)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: voucher %s not found", ErrInvalidVoucher, code)
+	}
+
+	customerUsed := 0
+	if customerID > 0 {
+		for _, r := range s.redemptions {
+			if r.VoucherID == v.ID && r.CustomerID == customerID && r.Status == models.VoucherRedemptionRedeemed {
+				customerUsed++
+			}
+		}
+	}
+
+	_, _, _, base := sumDetails(details)
+	amount, err := voucherDiscount(v, time.Now(), base, customerID, customerUsed)
+	if err != nil {
+		return nil, 0, err
+	}
+	allocateCartDiscount(details, amount)
+	return &v, amount, nil
+}
+
+// redeemVoucherLocked mencatat penukaran voucher dan menambah UsedCount.
+func (s *MemoryStore) redeemVoucherLocked(r models.VoucherRedemption) {
+	r.ID = s.nextRedemptionID
+	r.Status = models.VoucherRedemptionRedeemed
+	r.CreatedAt = time.Now()
+	s.nextRedemptionID++
+	s.redemptions = append(s.redemptions, r)
+
+	v := s.vouchers[r.VoucherID]
+	v.UsedCount++
+	s.vouchers[r.VoucherID] = v
+}
+
+// releaseVoucherLocked melepas penukaran voucher milik transaksi yang di-void.
+func (s *MemoryStore) releaseVoucherLocked(transactionID int) {
+	for i := range s.redemptions {
+		r := &s.redemptions[i]
+		if r.TransactionID != transactionID || r.Status != models.VoucherRedemptionRedeemed {
+			continue
+		}
+		now := time.Now()
+		r.Status, r.ReleasedAt = models.VoucherRedemptionReleased, &now
+
+		v := s.vouchers[r.VoucherID]
+		v.UsedCount--
+		s.vouchers[r.VoucherID] = v
+	}
+}
@@ -0,0 +1,444 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"kasir-api/models"
+)
+
+// OpenOpname membuka sesi stock opname baru dan menyimpan snapshot stok semua produk.
+func (s *PostgresStore) OpenOpname(ctx context.Context, req models.OpenOpnameRequest) (*models.StockOpname, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[opname-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO stock_opname (note, opened_by) VALUES ($1, $2) RETURNING id",
+		req.Note, req.Operator).Scan(&id)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: another stock opname session is still open", ErrConflict)
+	}
+	if err != nil {
+		log.Printf("[opname-store] Error insert opname: %v", err)
+		return nil, err
+	}
+
+	// ID stock_movements berasal dari SERIAL sehingga urut insert, bukan urut
+	// commit. Kunci FOR SHARE menunggu penjualan yang sedang berjalan selesai,
+	// jadi snapshot berikutnya melihat stok dan ledger yang sudah commit bersama.
+	_, err = tx.ExecContext(ctx, "SELECT id FROM produk ORDER BY id FOR SHARE")
+	if err != nil {
+		log.Printf("[opname-store] Error lock products: %v", err)
+		return nil, err
+	}
+
+	// Snapshot stok dan posisi ledger terakhir setiap produk.
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stock_opname_items (opname_id, product_id, product_name, snapshot_stok, snapshot_movement_id)
+		SELECT $1, p.id, p.nama, p.stok,
+			COALESCE((SELECT MAX(m.id) FROM stock_movements m WHERE m.product_id = p.id), 0)
+		FROM produk p
+	`, id)
+	if err != nil {
+		log.Printf("[opname-store] Error snapshot stock: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[opname-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[opname-store] Opname opened id=%d by=%s", id, req.Operator)
+	return s.GetOpname(ctx, id)
+}
+
+// ListOpname mengembalikan semua sesi opname tanpa laporan per produk, terbaru lebih dulu.
+func (s *PostgresStore) ListOpname(ctx context.Context) ([]models.StockOpname, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, status, note, opened_by, started_at, closed_by, closed_at
+		FROM stock_opname
+		ORDER BY id DESC
+	`)
+	if err != nil {
+		log.Printf("[opname-store] Error ListOpname: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.StockOpname{}
+	for rows.Next() {
+		o, err := scanOpname(rows)
+		if err != nil {
+			log.Printf("[opname-store] Error scanning opname row: %v", err)
+			continue
+		}
+		sessions = append(sessions, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[opname-store] Error iterating opname rows: %v", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// GetOpname mengembalikan satu sesi opname beserta laporan selisih per produk.
+func (s *PostgresStore) GetOpname(ctx context.Context, id int) (*models.StockOpname, error) {
+	return getOpname(ctx, s.db, id)
+}
+
+// RecordOpnameCounts menyimpan satu batch hasil hitung dari seorang penghitung.
+func (s *PostgresStore) RecordOpnameCounts(ctx context.Context, id int, req models.OpnameCountRequest) (*models.StockOpname, error) {
+	if strings.TrimSpace(req.Counter) == "" {
+		return nil, fmt.Errorf("%w: counter is required", ErrInvalidInput)
+	}
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: items cannot be empty", ErrInvalidInput)
+	}
+	for _, item := range req.Items {
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("%w: quantity for product %d cannot be negative", ErrInvalidInput, item.ProductID)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[opname-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOpname(ctx, tx, id, "FOR SHARE"); err != nil {
+		return nil, err
+	}
+
+	// Kunci FOR SHARE produk yang dihitung (urut ID) agar posisi ledger diambil
+	// setelah penjualan yang sedang berjalan untuk produk itu commit.
+	productIDs := make([]int64, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = int64(item.ProductID)
+	}
+	_, err = tx.ExecContext(ctx,
+		"SELECT id FROM produk WHERE id = ANY($1) ORDER BY id FOR SHARE", pq.Array(productIDs))
+	if err != nil {
+		log.Printf("[opname-store] Error lock products: %v", err)
+		return nil, err
+	}
+
+	for _, item := range req.Items {
+		// Simpan posisi ledger saat barang dihitung; hitung ulang oleh penghitung yang sama menimpa hitungan lama.
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO stock_opname_counts (item_id, counter, quantity, last_movement_id)
+			SELECT i.id, $3, $4,
+				COALESCE((SELECT MAX(m.id) FROM stock_movements m WHERE m.product_id = i.product_id), 0)
+			FROM stock_opname_items i
+			WHERE i.opname_id = $1 AND i.product_id = $2
+			ON CONFLICT (item_id, counter) DO UPDATE
+			SET quantity = EXCLUDED.quantity,
+				last_movement_id = EXCLUDED.last_movement_id,
+				counted_at = CURRENT_TIMESTAMP
+		`, id, item.ProductID, req.Counter, item.Quantity)
+		if err != nil {
+			log.Printf("[opname-store] Error insert count: %v", err)
+			return nil, err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return nil, fmt.Errorf("%w: product %d is not part of opname %d", ErrInvalidInput, item.ProductID, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[opname-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[opname-store] Counts recorded opname_id=%d counter=%s items=%d", id, req.Counter, len(req.Items))
+	return s.GetOpname(ctx, id)
+}
+
+// FinalizeOpname menutup sesi opname, memposting selisih setiap produk yang
+// dihitung sebagai pergerakan stok opname, dan membekukan laporan selisihnya.
+func (s *PostgresStore) FinalizeOpname(ctx context.Context, id int, req models.CloseOpnameRequest) (*models.StockOpname, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[opname-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOpname(ctx, tx, id, "FOR UPDATE"); err != nil {
+		return nil, err
+	}
+
+	// Kunci produk yang dihitung (urut ID) agar tidak ada penjualan di tengah finalisasi.
+	_, err = tx.ExecContext(ctx, `
+		SELECT p.id FROM produk p
+		WHERE p.id IN (
+			SELECT i.product_id FROM stock_opname_items i
+			WHERE i.opname_id = $1 AND EXISTS (SELECT 1 FROM stock_opname_counts c WHERE c.item_id = i.id)
+		)
+		ORDER BY p.id
+		FOR UPDATE
+	`, id)
+	if err != nil {
+		log.Printf("[opname-store] Error lock products: %v", err)
+		return nil, err
+	}
+
+	lines, err := getOpnameLines(ctx, tx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		// Produk yang sudah dihapus tidak punya stok lagi untuk disesuaikan.
+		if !line.Counted || line.ProductID == 0 {
+			continue
+		}
+
+		if line.Variance != 0 {
+			_, err := adjustStock(ctx, tx, models.StockMovement{
+				ProductID:   line.ProductID,
+				Delta:       line.Variance,
+				Reason:      models.StockReasonOpname,
+				ReferenceID: &id,
+				Operator:    req.Operator,
+				Note:        fmt.Sprintf("selisih stock opname #%d", id),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Bekukan hasil agar laporan tidak berubah oleh pergerakan stok setelah finalisasi.
+		_, err := tx.ExecContext(ctx, `
+			UPDATE stock_opname_items
+			SET counted_quantity = $3, sold_during_session = $4, moved_after_count = $5, system_stok = $6, variance = $7
+			WHERE opname_id = $1 AND product_id = $2
+		`, id, line.ProductID, line.CountedQuantity, line.SoldDuringSession, line.MovedAfterCount, line.SystemStok, line.Variance)
+		if err != nil {
+			log.Printf("[opname-store] Error freeze opname line: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := closeOpname(ctx, tx, id, models.OpnameStatusFinalized, req.Operator); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[opname-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[opname-store] Opname finalized id=%d by=%s lines=%d", id, req.Operator, len(lines))
+	return s.GetOpname(ctx, id)
+}
+
+// CancelOpname membatalkan sesi opname tanpa mengubah stok.
+func (s *PostgresStore) CancelOpname(ctx context.Context, id int, req models.CloseOpnameRequest) (*models.StockOpname, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[opname-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOpname(ctx, tx, id, "FOR UPDATE"); err != nil {
+		return nil, err
+	}
+	if err := closeOpname(ctx, tx, id, models.OpnameStatusCancelled, req.Operator); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[opname-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[opname-store] Opname cancelled id=%d by=%s", id, req.Operator)
+	return s.GetOpname(ctx, id)
+}
+
+// lockOpenOpname mengunci baris sesi opname dan memastikan statusnya masih open.
+func lockOpenOpname(ctx context.Context, tx *sql.Tx, id int, lockClause string) error {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM stock_opname WHERE id = $1 "+lockClause, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: stock opname id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[opname-store] Error lock opname: %v", err)
+		return err
+	}
+	if status != models.OpnameStatusOpen {
+		return fmt.Errorf("%w: stock opname %d is %s", ErrConflict, id, status)
+	}
+	return nil
+}
+
+// closeOpname mengubah status sesi opname menjadi status akhir.
+func closeOpname(ctx context.Context, tx *sql.Tx, id int, status, operator string) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE stock_opname SET status = $1, closed_by = $2, closed_at = CURRENT_TIMESTAMP WHERE id = $3",
+		status, operator, id)
+	if err != nil {
+		log.Printf("[opname-store] Error close opname: %v", err)
+	}
+	return err
+}
+
+// getOpname mengambil header sesi opname beserta laporan selisihnya.
+func getOpname(ctx context.Context, q queryer, id int) (*models.StockOpname, error) {
+	row := q.QueryRowContext(ctx, `
+		SELECT id, status, note, opened_by, started_at, closed_by, closed_at
+		FROM stock_opname
+		WHERE id = $1
+	`, id)
+	o, err := scanOpname(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: stock opname id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[opname-store] Error get opname: %v", err)
+		return nil, err
+	}
+
+	lines, err := getOpnameLines(ctx, q, id, o.Status == models.OpnameStatusFinalized)
+	if err != nil {
+		return nil, err
+	}
+	o.Lines = lines
+	for _, line := range lines {
+		o.TotalVariance += line.Variance
+	}
+
+	return &o, nil
+}
+
+// rowScanner adalah bagian dari *sql.Row dan *sql.Rows yang dipakai fungsi scan.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOpname membaca satu baris header sesi opname.
+func scanOpname(row rowScanner) (models.StockOpname, error) {
+	var o models.StockOpname
+	var closedAt sql.NullTime
+	if err := row.Scan(&o.ID, &o.Status, &o.Note, &o.OpenedBy, &o.StartedAt, &o.ClosedBy, &closedAt); err != nil {
+		return models.StockOpname{}, err
+	}
+	if closedAt.Valid {
+		o.ClosedAt = &closedAt.Time
+	}
+	return o, nil
+}
+
+// getOpnameLines menyusun laporan selisih per produk. Untuk sesi yang sudah
+// difinalisasi (frozen), angka diambil dari hasil yang dibekukan; selain itu
+// dihitung dari kondisi stok dan ledger saat ini.
+func getOpnameLines(ctx context.Context, q queryer, id int, frozen bool) ([]models.OpnameLine, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT COALESCE(i.product_id, 0), COALESCE(p.nama, i.product_name), i.snapshot_stok, COALESCE(p.stok, 0),
+			COALESCE(sold.qty, 0),
+			counts.qty,
+			COALESCE(after_count.delta, 0),
+			i.counted_quantity, i.sold_during_session, i.moved_after_count, i.system_stok
+		FROM stock_opname_items i
+		LEFT JOIN produk p ON p.id = i.product_id
+		LEFT JOIN LATERAL (
+			SELECT SUM(-m.delta) AS qty FROM stock_movements m
+			WHERE m.product_id = i.product_id AND m.id > i.snapshot_movement_id AND m.reason = 'sale'
+		) sold ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT SUM(c.quantity) AS qty, MAX(c.last_movement_id) AS position FROM stock_opname_counts c
+			WHERE c.item_id = i.id
+		) counts ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT SUM(m.delta) AS delta FROM stock_movements m
+			WHERE m.product_id = i.product_id AND m.id > counts.position AND m.reason <> 'opname'
+		) after_count ON TRUE
+		WHERE i.opname_id = $1
+		ORDER BY i.product_id, i.id
+	`, id)
+	if err != nil {
+		log.Printf("[opname-store] Error get opname lines: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.OpnameLine{}
+	for rows.Next() {
+		var line models.OpnameLine
+		var counted, frozenCounted, frozenSold, frozenMoved, frozenSystem sql.NullInt64
+		err := rows.Scan(&line.ProductID, &line.ProductName, &line.SnapshotStok, &line.SystemStok,
+			&line.SoldDuringSession, &counted, &line.MovedAfterCount,
+			&frozenCounted, &frozenSold, &frozenMoved, &frozenSystem)
+		if err != nil {
+			log.Printf("[opname-store] Error scanning opname line: %v", err)
+			continue
+		}
+
+		if frozen {
+			counted = frozenCounted
+			if frozenCounted.Valid {
+				line.SoldDuringSession = int(frozenSold.Int64)
+				line.MovedAfterCount = int(frozenMoved.Int64)
+				line.SystemStok = int(frozenSystem.Int64)
+			}
+		}
+		if counted.Valid {
+			line.Counted = true
+			line.CountedQuantity = int(counted.Int64)
+		}
+		lines = append(lines, finishOpnameLine(line))
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[opname-store] Error iterating opname lines: %v", err)
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// finishOpnameLine menghitung hasil hitung yang disesuaikan dan selisihnya.
+// Pergerakan stok setelah barang dihitung (misalnya penjualan) ditambahkan ke
+// hasil hitung, sehingga selisih dibandingkan terhadap stok sistem saat ini.
+// Produk yang belum dihitung tidak punya selisih.
+func finishOpnameLine(line models.OpnameLine) models.OpnameLine {
+	if !line.Counted {
+		line.MovedAfterCount = 0
+		line.AdjustedCount = 0
+		line.Variance = 0
+		return line
+	}
+
+	line.AdjustedCount = line.CountedQuantity + line.MovedAfterCount
+	line.Variance = line.AdjustedCount - line.SystemStok
+	return line
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestFinishOpnameLineVariance(t *testing.T) {
+	tests := []struct {
+		name                       string
+		line                       models.OpnameLine
+		wantAdjusted, wantVariance int
+	}{
+		// Hitungan sama dengan stok sistem.
+		{"match", models.OpnameLine{Counted: true, CountedQuantity: 20, SystemStok: 20}, 20, 0},
+		// Dua barang hilang.
+		{"shortage", models.OpnameLine{Counted: true, CountedQuantity: 18, SystemStok: 20}, 18, -2},
+		// Ditemukan barang lebih banyak dari catatan.
+		{"surplus", models.OpnameLine{Counted: true, CountedQuantity: 23, SystemStok: 20}, 23, 3},
+		// Dihitung 20, lalu 5 terjual: stok sistem 15 dan hasil hitung disesuaikan menjadi 15.
+		{"sold after count", models.OpnameLine{Counted: true, CountedQuantity: 20, MovedAfterCount: -5, SystemStok: 15}, 15, 0},
+		// Dihitung 20, lalu 10 diterima dari supplier: hasil hitung menjadi 30, stok sistem 32.
+		{"received after count", models.OpnameLine{Counted: true, CountedQuantity: 20, MovedAfterCount: 10, SystemStok: 32}, 30, -2},
+		// Produk yang belum dihitung tidak punya selisih walau ada pergerakan.
+		{"not counted", models.OpnameLine{MovedAfterCount: -5, AdjustedCount: 7, SystemStok: 15}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := finishOpnameLine(tt.line)
+			if got.AdjustedCount != tt.wantAdjusted || got.Variance != tt.wantVariance {
+				t.Errorf("adjusted/variance = %d/%d, want %d/%d", got.AdjustedCount, got.Variance, tt.wantAdjusted, tt.wantVariance)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"fmt"
+
+	"kasir-api/models"
+)
+
+// validPaymentMethods berisi metode pembayaran yang diterima saat checkout.
+var validPaymentMethods = map[string]bool{
+	models.PaymentMethodCash:    true,
+	models.PaymentMethodDebit:   true,
+	models.PaymentMethodQRIS:    true,
+	models.PaymentMethodEWallet: true,
+	models.PaymentMethodPoints:  true,
+}
+
+// IsValidPaymentMethod melaporkan apakah method adalah metode pembayaran yang dikenal.
+func IsValidPaymentMethod(method string) bool {
+	return validPaymentMethods[method]
+}
+
+// settlePayments memvalidasi satu atau beberapa baris pembayaran terhadap total
+// belanja lalu mengembalikan jumlah bayar dan kembalian. Pembayaran non-tunai
+// tidak boleh melebihi total, karena kembalian selalu diberikan dalam bentuk
+// uang tunai; kelebihan bayar hanya boleh berasal dari baris tunai. Penukaran
+// poin termasuk non-tunai.
+func settlePayments(total int, payments []models.CheckoutPayment) (int, int, error) {
+	if len(payments) == 0 {
+		return 0, 0, fmt.Errorf("%w: at least one payment is required", ErrInvalidPayment)
+	}
+
+	paid, nonCash := 0, 0
+	for i, p := range payments {
+		if !validPaymentMethods[p.Method] {
+			return 0, 0, fmt.Errorf("%w: payment %d has unknown method %q", ErrInvalidPayment, i+1, p.Method)
+		}
+		if p.Amount <= 0 {
+			return 0, 0, fmt.Errorf("%w: payment %d amount must be greater than zero", ErrInvalidPayment, i+1)
+		}
+		paid += p.Amount
+		if p.Method != models.PaymentMethodCash {
+			nonCash += p.Amount
+		}
+	}
+
+	if paid < total {
+		return 0, 0, fmt.Errorf("%w (paid: %d, total: %d)", ErrUnderpaid, paid, total)
+	}
+	if nonCash > total {
+		return 0, 0, fmt.Errorf("%w: non-cash payments exceed total amount (non-cash: %d, total: %d)",
+			ErrInvalidPayment, nonCash, total)
+	}
+
+	return paid, paid - total, nil
+}
+
+// paymentBreakdown merangkum pembayaran per metode sesuai urutan kemunculan.
+// Kembalian dikurangkan dari porsi tunai sehingga jumlah Amount sama dengan total belanja.
+func paymentBreakdown(payments []models.TransactionPayment, change int) []models.PaymentBreakdown {
+	breakdown := make([]models.PaymentBreakdown, 0)
+	index := make(map[string]int)
+	for _, p := range payments {
+		i, ok := index[p.Method]
+		if !ok {
+			i = len(breakdown)
+			index[p.Method] = i
+			breakdown = append(breakdown, models.PaymentBreakdown{Method: p.Method})
+		}
+		breakdown[i].Tendered += p.Amount
+		breakdown[i].Amount += p.Amount
+	}
+
+	if i, ok := index[models.PaymentMethodCash]; ok {
+		breakdown[i].Amount -= change
+	}
+
+	return breakdown
+}
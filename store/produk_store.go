@@ -1,16 +1,17 @@
 package store
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
 
-	"kasir-api/database"
 	"kasir-api/models"
 )
 
 // GetAll mengembalikan semua data produk dengan filter nama (opsional).
-func GetAll(nameFilter string) []models.Produk {
-	query := "SELECT id, nama, harga, stok, kategori_id FROM produk"
+func (s *PostgresStore) GetAll(ctx context.Context, nameFilter string) []models.Produk {
+	query := "SELECT id, nama, harga, harga_beli, stok, kategori_id FROM produk"
 	args := []interface{}{}
 
 	if nameFilter != "" {
@@ -20,7 +21,7 @@ func GetAll(nameFilter string) []models.Produk {
 
 	query += " ORDER BY id"
 
-	rows, err := database.DB.Query(query, args...)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("[produk-store] Error GetAll: %v", err)
 		return []models.Produk{}
@@ -30,7 +31,7 @@ func GetAll(nameFilter string) []models.Produk {
 	var produk []models.Produk
 	for rows.Next() {
 		var p models.Produk
-		if err := rows.Scan(&p.ID, &p.Nama, &p.Harga, &p.Stok, &p.KategoriID); err != nil {
+		if err := rows.Scan(&p.ID, &p.Nama, &p.Harga, &p.HargaBeli, &p.Stok, &p.KategoriID); err != nil {
 			log.Printf("[produk-store] Error scanning row: %v", err)
 			continue
 		}
@@ -45,10 +46,10 @@ func GetAll(nameFilter string) []models.Produk {
 }
 
 // GetByID mengembalikan satu produk berdasarkan ID.
-func GetByID(id int) (models.Produk, bool) {
+func (s *PostgresStore) GetByID(ctx context.Context, id int) (models.Produk, bool) {
 	var p models.Produk
-	err := database.DB.QueryRow("SELECT id, nama, harga, stok, kategori_id FROM produk WHERE id = $1", id).
-		Scan(&p.ID, &p.Nama, &p.Harga, &p.Stok, &p.KategoriID)
+	err := s.db.QueryRowContext(ctx, "SELECT id, nama, harga, harga_beli, stok, kategori_id FROM produk WHERE id = $1", id).
+		Scan(&p.ID, &p.Nama, &p.Harga, &p.HargaBeli, &p.Stok, &p.KategoriID)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -61,11 +62,19 @@ func GetByID(id int) (models.Produk, bool) {
 	return p, true
 }
 
-// Add menambahkan produk baru ke penyimpanan dan mengembalikan produk dengan ID
-func Add(p models.Produk) (models.Produk, error) {
-	err := database.DB.QueryRow(
-		"INSERT INTO produk (nama, harga, stok, kategori_id) VALUES ($1, $2, $3, $4) RETURNING id",
-		p.Nama, p.Harga, p.Stok, p.KategoriID,
+// Add menambahkan produk baru ke penyimpanan dan mengembalikan produk dengan ID.
+// Stok awal dicatat sebagai adjustment di ledger stock_movements.
+func (s *PostgresStore) Add(ctx context.Context, p models.Produk) (models.Produk, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[produk-store] Error begin transaction: %v", err)
+		return models.Produk{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO produk (nama, harga, harga_beli, stok, kategori_id) VALUES ($1, $2, $3, 0, $4) RETURNING id",
+		p.Nama, p.Harga, p.HargaBeli, p.KategoriID,
 	).Scan(&p.ID)
 
 	if err != nil {
@@ -73,39 +82,122 @@ func Add(p models.Produk) (models.Produk, error) {
 		return models.Produk{}, err
 	}
 
+	_, err = adjustStock(ctx, tx, models.StockMovement{
+		ProductID: p.ID,
+		Delta:     p.Stok,
+		Reason:    models.StockReasonAdjustment,
+		Note:      "stok awal produk",
+	})
+	if err != nil {
+		return models.Produk{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityProduk, models.AuditActionCreate, p.ID, nil, p); err != nil {
+		return models.Produk{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[produk-store] Error commit Add: %v", err)
+		return models.Produk{}, err
+	}
+
 	return p, nil
 }
 
-// Update mengganti data produk berdasarkan ID.
-func Update(id int, p models.Produk) (models.Produk, bool) {
-	result, err := database.DB.Exec(
-		"UPDATE produk SET nama = $1, harga = $2, stok = $3, kategori_id = $4 WHERE id = $5",
-		p.Nama, p.Harga, p.Stok, p.KategoriID, id,
-	)
+// Update mengganti data produk berdasarkan ID. Field stok di body diabaikan:
+// stok hanya berubah lewat ledger (penjualan, pembelian, stock opname), jadi
+// klien yang mengirim data lama tidak bisa mengembalikan unit yang sudah terjual.
+func (s *PostgresStore) Update(ctx context.Context, id int, p models.Produk) (models.Produk, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[produk-store] Error begin transaction: %v", err)
+		return models.Produk{}, err
+	}
+	defer tx.Rollback()
+
+	// Kunci baris produk agar data sebelum perubahan untuk audit tetap akurat.
+	var before models.Produk
+	err = tx.QueryRowContext(ctx, "SELECT id, nama, harga, harga_beli, stok, kategori_id FROM produk WHERE id = $1 FOR UPDATE", id).
+		Scan(&before.ID, &before.Nama, &before.Harga, &before.HargaBeli, &before.Stok, &before.KategoriID)
+	if err == sql.ErrNoRows {
+		return models.Produk{}, fmt.Errorf("%w: product id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[produk-store] Error Update: %v", err)
+		return models.Produk{}, err
+	}
 
+	_, err = tx.ExecContext(ctx,
+		"UPDATE produk SET nama = $1, harga = $2, harga_beli = $3, kategori_id = $4 WHERE id = $5",
+		p.Nama, p.Harga, p.HargaBeli, p.KategoriID, id,
+	)
+	if isForeignKeyViolation(err) {
+		return models.Produk{}, fmt.Errorf("%w: kategori id %d not found", ErrInvalidInput, p.KategoriID)
+	}
 	if err != nil {
 		log.Printf("[produk-store] Error Update: %v", err)
-		return models.Produk{}, false
+		return models.Produk{}, err
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected > 0 {
-		p.ID = id
-		return p, true
+	// Catat manajer yang menyetujui perubahan, jika ada, bersama perubahannya.
+	if a, ok := approvalFor(ctx, models.ApprovalActionProdukUpdate); ok {
+		if err := insertApproval(ctx, tx, a, id); err != nil {
+			return models.Produk{}, err
+		}
+	}
+
+	p.ID = id
+	p.Stok = before.Stok
+	if err := insertAudit(ctx, tx, models.AuditEntityProduk, models.AuditActionUpdate, id, before, p); err != nil {
+		return models.Produk{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[produk-store] Error commit Update: %v", err)
+		return models.Produk{}, err
 	}
 
-	return models.Produk{}, false
+	return p, nil
 }
 
-// Delete menghapus produk berdasarkan ID.
-func Delete(id int) bool {
-	result, err := database.DB.Exec("DELETE FROM produk WHERE id = $1", id)
+// Delete menghapus produk berdasarkan ID. Persetujuan manajer di context, jika
+// ada, dicatat dalam database transaction yang sama.
+func (s *PostgresStore) Delete(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[produk-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
 
+	var before models.Produk
+	err = tx.QueryRowContext(ctx, "DELETE FROM produk WHERE id = $1 RETURNING id, nama, harga, harga_beli, stok, kategori_id", id).
+		Scan(&before.ID, &before.Nama, &before.Harga, &before.HargaBeli, &before.Stok, &before.KategoriID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: product id %d", ErrNotFound, id)
+	}
+	// Purchase order tetap mereferensikan produknya, jadi produk yang pernah dipesan tidak bisa dihapus.
+	if isForeignKeyViolation(err) {
+		return fmt.Errorf("%w: product id %d is used by purchase orders", ErrConflict, id)
+	}
 	if err != nil {
 		log.Printf("[produk-store] Error Delete: %v", err)
-		return false
+		return err
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	return rowsAffected > 0
+	if a, ok := approvalFor(ctx, models.ApprovalActionProdukDelete); ok {
+		if err := insertApproval(ctx, tx, a, id); err != nil {
+			return err
+		}
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityProduk, models.AuditActionDelete, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[produk-store] Error commit Delete: %v", err)
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// appliedDiscount adalah promosi yang terpilih saat checkout sebelum detail
+// transaksi punya ID. Line bernilai -1 untuk promosi keranjang.
+type appliedDiscount struct {
+	Line      int
+	Promotion models.Promotion
+	Amount    int
+}
+
+// validatePromotion memastikan aturan promosi lengkap dan konsisten.
+func validatePromotion(p models.Promotion) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if p.ProductID > 0 && p.KategoriID > 0 {
+		return fmt.Errorf("%w: choose either product_id or kategori_id, not both", ErrInvalidInput)
+	}
+
+	switch p.Type {
+	case models.PromotionTypeDiscount, models.PromotionTypeMinSpend:
+		switch p.DiscountType {
+		case models.DiscountTypePercentage:
+			if p.Value <= 0 || p.Value > 100 {
+				return fmt.Errorf("%w: percentage value must be between 1 and 100", ErrInvalidInput)
+			}
+		case models.DiscountTypeFixed:
+			if p.Value <= 0 {
+				return fmt.Errorf("%w: fixed value must be greater than zero", ErrInvalidInput)
+			}
+		default:
+			return fmt.Errorf("%w: unknown discount_type %q", ErrInvalidInput, p.DiscountType)
+		}
+		if p.Type == models.PromotionTypeMinSpend {
+			if p.MinSpend <= 0 {
+				return fmt.Errorf("%w: min_spend must be greater than zero", ErrInvalidInput)
+			}
+			if p.ProductID > 0 || p.KategoriID > 0 {
+				return fmt.Errorf("%w: min_spend promotions apply to the whole cart", ErrInvalidInput)
+			}
+		}
+	case models.PromotionTypeBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return fmt.Errorf("%w: buy_quantity and get_quantity must be greater than zero", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown promotion type %q", ErrInvalidInput, p.Type)
+	}
+
+	if p.StartAt != nil && p.EndAt != nil && !p.EndAt.After(*p.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidInput)
+	}
+	if (p.HourStart == "") != (p.HourEnd == "") {
+		return fmt.Errorf("%w: hour_start and hour_end must be set together", ErrInvalidInput)
+	}
+	if p.HourStart != "" {
+		for _, h := range []string{p.HourStart, p.HourEnd} {
+			if _, err := time.Parse("15:04", h); err != nil {
+				return fmt.Errorf("%w: invalid hour %q, use HH:MM", ErrInvalidInput, h)
+			}
+		}
+		if p.HourStart == p.HourEnd {
+			return fmt.Errorf("%w: hour_start and hour_end must differ", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// promotionActive melaporkan apakah promosi berlaku pada waktu now.
+func promotionActive(p models.Promotion, now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartAt != nil && now.Before(*p.StartAt) {
+		return false
+	}
+	if p.EndAt != nil && !now.Before(*p.EndAt) {
+		return false
+	}
+	if p.HourStart == "" {
+		return true
+	}
+
+	// Format HH:MM bisa dibandingkan sebagai string.
+	clock := now.Format("15:04")
+	if p.HourStart < p.HourEnd {
+		return clock >= p.HourStart && clock < p.HourEnd
+	}
+	return clock >= p.HourStart || clock < p.HourEnd
+}
+
+// promotionMatches melaporkan apakah promosi baris berlaku untuk detail d.
+func promotionMatches(p models.Promotion, d models.TransactionDetail) bool {
+	switch {
+	case p.ProductID > 0:
+		return p.ProductID == d.ProductID
+	case p.KategoriID > 0:
+		return p.KategoriID == d.KategoriID
+	default:
+		return true
+	}
+}
+
+// lineDiscount menghitung potongan promosi baris untuk detail d.
+func lineDiscount(p models.Promotion, d models.TransactionDetail) int {
+	gross := d.UnitPrice * d.Quantity
+	discount := 0
+	switch p.Type {
+	case models.PromotionTypeDiscount:
+		if p.DiscountType == models.DiscountTypePercentage {
+			discount = gross * p.Value / 100
+		} else {
+			discount = p.Value * d.Quantity
+		}
+	case models.PromotionTypeBuyXGetY:
+		free := d.Quantity / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+		discount = free * d.UnitPrice
+	}
+	return min(discount, gross)
+}
+
+// applyPromotions mengevaluasi promosi yang aktif pada waktu now terhadap detail
+// checkout. Setiap baris mendapat satu promosi baris dengan potongan terbesar,
+// lalu satu promosi keranjang (min_spend) dengan potongan terbesar diterapkan
+// pada total setelah potongan baris. Potongan keranjang dibagi ke setiap baris
+// secara proporsional sehingga refund mengembalikan jumlah yang benar-benar
+// dibayar. Discount, CartDiscount, dan Subtotal pada details diisi ulang.
+func applyPromotions(details []models.TransactionDetail, promotions []models.Promotion, now time.Time) []appliedDiscount {
+	var applied []appliedDiscount
+
+	active := make([]models.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if promotionActive(p, now) {
+			active = append(active, p)
+		}
+	}
+
+	base := 0
+	for i := range details {
+		d := &details[i]
+		d.Discount, d.CartDiscount = 0, 0
+
+		var best *models.Promotion
+		for j := range active {
+			p := &active[j]
+			if p.Type == models.PromotionTypeMinSpend || !promotionMatches(*p, *d) {
+				continue
+			}
+			if amount := lineDiscount(*p, *d); amount > d.Discount {
+				d.Discount, best = amount, p
+			}
+		}
+		if best != nil {
+			applied = append(applied, appliedDiscount{Line: i, Promotion: *best, Amount: d.Discount})
+		}
+
+		d.Subtotal = d.UnitPrice*d.Quantity - d.Discount
+		base += d.Subtotal
+	}
+
+	var cart *models.Promotion
+	cartAmount := 0
+	for j := range active {
+		p := &active[j]
+		if p.Type != models.PromotionTypeMinSpend || base < p.MinSpend {
+			continue
+		}
+		amount := p.Value
+		if p.DiscountType == models.DiscountTypePercentage {
+			amount = base * p.Value / 100
+		}
+		if amount = min(amount, base); amount > cartAmount {
+			cartAmount, cart = amount, p
+		}
+	}
+	if cart == nil {
+		return applied
+	}
+	applied = append(applied, appliedDiscount{Line: -1, Promotion: *cart, Amount: cartAmount})
+	allocateCartDiscount(details, cartAmount)
+
+	return applied
+}
+
+// allocateCartDiscount membagi potongan keranjang ke setiap baris sebanding
+// dengan Subtotal-nya, lalu menambahkannya ke CartDiscount dan mengurangi
+// Subtotal. Pembagian dihitung kumulatif agar jumlahnya tepat tanpa selisih
+// pembulatan. amount tidak boleh melebihi jumlah Subtotal.
+func allocateCartDiscount(details []models.TransactionDetail, amount int) {
+	base := 0
+	for _, d := range details {
+		base += d.Subtotal
+	}
+	if amount <= 0 || base <= 0 {
+		return
+	}
+
+	cumulative, allocated := 0, 0
+	for i := range details {
+		cumulative += details[i].Subtotal
+		share := amount*cumulative/base - allocated
+		allocated += share
+		details[i].CartDiscount += share
+		details[i].Subtotal -= share
+	}
+}
+
+// sumDetails menjumlahkan harga sebelum potongan, potongan baris, potongan
+// keranjang, dan subtotal setelah potongan dari semua detail.
+func sumDetails(details []models.TransactionDetail) (gross, lineTotal, cartTotal, net int) {
+	for _, d := range details {
+		gross += d.UnitPrice * d.Quantity
+		lineTotal += d.Discount
+		cartTotal += d.CartDiscount
+		net += d.Subtotal
+	}
+	return gross, lineTotal, cartTotal, net
+}
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// promotionColumns adalah kolom promotions sesuai urutan scanPromotion.
+const promotionColumns = `id, name, type, discount_type, value, product_id, kategori_id, buy_quantity, get_quantity,
+	min_spend, start_at, end_at, hour_start, hour_end, active, created_at`
+
+// AddPromotion menambahkan promosi baru.
+func (s *PostgresStore) AddPromotion(ctx context.Context, p models.Promotion) (models.Promotion, error) {
+	if err := validatePromotion(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[promotion-store] Error begin transaction: %v", err)
+		return models.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO promotions (name, type, discount_type, value, product_id, kategori_id, buy_quantity, get_quantity,
+			min_spend, start_at, end_at, hour_start, hour_end, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at
+	`, p.Name, p.Type, p.DiscountType, p.Value, nullableID(p.ProductID), nullableID(p.KategoriID), p.BuyQuantity,
+		p.GetQuantity, p.MinSpend, p.StartAt, p.EndAt, p.HourStart, p.HourEnd, p.Active,
+	).Scan(&p.ID, &p.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return models.Promotion{}, fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if err != nil {
+		log.Printf("[promotion-store] Error AddPromotion: %v", err)
+		return models.Promotion{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityPromotion, models.AuditActionCreate, p.ID, nil, p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[promotion-store] Error commit AddPromotion: %v", err)
+		return models.Promotion{}, err
+	}
+
+	return p, nil
+}
+
+// GetAllPromotions mengembalikan semua promosi.
+func (s *PostgresStore) GetAllPromotions(ctx context.Context) ([]models.Promotion, error) {
+	return getPromotions(ctx, s.db, false)
+}
+
+// GetPromotionByID mengembalikan satu promosi berdasarkan ID.
+func (s *PostgresStore) GetPromotionByID(ctx context.Context, id int) (models.Promotion, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id)
+	p, err := scanPromotion(row)
+	if err == sql.ErrNoRows {
+		return models.Promotion{}, fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[promotion-store] Error GetPromotionByID: %v", err)
+		return models.Promotion{}, err
+	}
+	return p, nil
+}
+
+// UpdatePromotion mengganti aturan promosi berdasarkan ID. Transaksi lama tidak
+// berubah karena nama dan potongan promosi sudah disimpan saat checkout.
+func (s *PostgresStore) UpdatePromotion(ctx context.Context, id int, p models.Promotion) (models.Promotion, error) {
+	if err := validatePromotion(p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[promotion-store] Error begin transaction: %v", err)
+		return models.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanPromotion(tx.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.Promotion{}, fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[promotion-store] Error lock promotion: %v", err)
+		return models.Promotion{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE promotions
+		SET name = $1, type = $2, discount_type = $3, value = $4, product_id = $5, kategori_id = $6,
+			buy_quantity = $7, get_quantity = $8, min_spend = $9, start_at = $10, end_at = $11,
+			hour_start = $12, hour_end = $13, active = $14
+		WHERE id = $15
+		RETURNING id, created_at
+	`, p.Name, p.Type, p.DiscountType, p.Value, nullableID(p.ProductID), nullableID(p.KategoriID), p.BuyQuantity,
+		p.GetQuantity, p.MinSpend, p.StartAt, p.EndAt, p.HourStart, p.HourEnd, p.Active, id,
+	).Scan(&p.ID, &p.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return models.Promotion{}, fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if err != nil {
+		log.Printf("[promotion-store] Error UpdatePromotion: %v", err)
+		return models.Promotion{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityPromotion, models.AuditActionUpdate, id, before, p); err != nil {
+		return models.Promotion{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[promotion-store] Error commit UpdatePromotion: %v", err)
+		return models.Promotion{}, err
+	}
+
+	return p, nil
+}
+
+// DeletePromotion menghapus promosi berdasarkan ID.
+func (s *PostgresStore) DeletePromotion(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[promotion-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := scanPromotion(tx.QueryRowContext(ctx, "DELETE FROM promotions WHERE id = $1 RETURNING "+promotionColumns, id))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: promotion id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[promotion-store] Error DeletePromotion: %v", err)
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityPromotion, models.AuditActionDelete, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[promotion-store] Error commit DeletePromotion: %v", err)
+		return err
+	}
+	return nil
+}
+
+// getPromotions mengambil promosi urut ID, hanya yang aktif jika activeOnly.
+func getPromotions(ctx context.Context, q queryer, activeOnly bool) ([]models.Promotion, error) {
+	query := "SELECT " + promotionColumns + " FROM promotions"
+	if activeOnly {
+		query += " WHERE active"
+	}
+	query += " ORDER BY id"
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("[promotion-store] Error get promotions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			log.Printf("[promotion-store] Error scanning promotion row: %v", err)
+			continue
+		}
+		promotions = append(promotions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[promotion-store] Error iterating promotion rows: %v", err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// scanPromotion membaca satu baris promosi.
+func scanPromotion(row rowScanner) (models.Promotion, error) {
+	var p models.Promotion
+	var productID, kategoriID sql.NullInt64
+	var startAt, endAt sql.NullTime
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.DiscountType, &p.Value, &productID, &kategoriID,
+		&p.BuyQuantity, &p.GetQuantity, &p.MinSpend, &startAt, &endAt, &p.HourStart, &p.HourEnd, &p.Active, &p.CreatedAt)
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	p.ProductID = int(productID.Int64)
+	p.KategoriID = int(kategoriID.Int64)
+	if startAt.Valid {
+		p.StartAt = &startAt.Time
+	}
+	if endAt.Valid {
+		p.EndAt = &endAt.Time
+	}
+	return p, nil
+}
+
+// insertAppliedPromotions menyimpan promosi yang diterapkan pada transaksi dan
+// mengembalikan catatannya. details harus sudah punya ID.
+func insertAppliedPromotions(ctx context.Context, tx *sql.Tx, transactionID int, details []models.TransactionDetail, applied []appliedDiscount) ([]models.AppliedPromotion, error) {
+	promotions := make([]models.AppliedPromotion, 0, len(applied))
+	for _, a := range applied {
+		ap := models.AppliedPromotion{
+			TransactionID: transactionID,
+			PromotionID:   a.Promotion.ID,
+			PromotionName: a.Promotion.Name,
+			Amount:        a.Amount,
+		}
+		if a.Line >= 0 {
+			ap.TransactionDetailID = details[a.Line].ID
+		}
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO transaction_promotions (transaction_id, transaction_detail_id, promotion_id, promotion_name, amount)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id
+		`, transactionID, nullableID(ap.TransactionDetailID), ap.PromotionID, ap.PromotionName, ap.Amount).Scan(&ap.ID)
+		if err != nil {
+			log.Printf("[promotion-store] Error insert applied promotion: %v", err)
+			return nil, err
+		}
+		promotions = append(promotions, ap)
+	}
+	return promotions, nil
+}
+
+// getAppliedPromotions mengambil promosi yang diterapkan pada satu transaksi.
+func getAppliedPromotions(ctx context.Context, q queryer, transactionID int) ([]models.AppliedPromotion, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, transaction_id, transaction_detail_id, promotion_id, promotion_name, amount
+		FROM transaction_promotions
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		log.Printf("[promotion-store] Error get applied promotions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []models.AppliedPromotion
+	for rows.Next() {
+		var ap models.AppliedPromotion
+		var detailID, promotionID sql.NullInt64
+		if err := rows.Scan(&ap.ID, &ap.TransactionID, &detailID, &promotionID, &ap.PromotionName, &ap.Amount); err != nil {
+			log.Printf("[promotion-store] Error scanning applied promotion row: %v", err)
+			continue
+		}
+		ap.TransactionDetailID = int(detailID.Int64)
+		ap.PromotionID = int(promotionID.Int64)
+		promotions = append(promotions, ap)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[promotion-store] Error iterating applied promotion rows: %v", err)
+		return nil, err
+	}
+
+	return promotions, nil
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// validatePurchaseOrder memastikan request pembuatan PO lengkap dan valid.
+func validatePurchaseOrder(req models.CreatePurchaseOrderRequest) error {
+	if req.SupplierID <= 0 {
+		return fmt.Errorf("%w: supplier_id is required", ErrInvalidInput)
+	}
+	if strings.TrimSpace(req.Operator) == "" {
+		return fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+	if len(req.Items) == 0 {
+		return fmt.Errorf("%w: items cannot be empty", ErrInvalidInput)
+	}
+	for i, item := range req.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: item %d quantity must be greater than zero", ErrInvalidInput, i+1)
+		}
+		if item.CostPrice < 0 {
+			return fmt.Errorf("%w: item %d cost_price cannot be negative", ErrInvalidInput, i+1)
+		}
+	}
+	return nil
+}
+
+// planReceipt menyusun item penerimaan dari permintaan terhadap item PO.
+// Items kosong berarti semua sisa barang diterima.
+func planReceipt(items []models.PurchaseOrderItem, req []models.ReceiveItem) ([]models.PurchaseReceiptItem, error) {
+	// Gabungkan permintaan untuk item yang sama.
+	requested := make(map[int]int)
+	if len(req) == 0 {
+		for _, item := range items {
+			if remaining := item.Quantity - item.ReceivedQuantity; remaining > 0 {
+				requested[item.ID] = remaining
+			}
+		}
+	}
+	for _, r := range req {
+		if r.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for item %d must be greater than zero", ErrInvalidInput, r.ItemID)
+		}
+		requested[r.ItemID] += r.Quantity
+	}
+
+	byID := make(map[int]bool, len(items))
+	for _, item := range items {
+		byID[item.ID] = true
+	}
+	for itemID := range requested {
+		if !byID[itemID] {
+			return nil, fmt.Errorf("%w: item %d does not belong to this purchase order", ErrInvalidInput, itemID)
+		}
+	}
+
+	// Susun item mengikuti urutan PO agar hasilnya stabil.
+	receiptItems := make([]models.PurchaseReceiptItem, 0, len(requested))
+	for _, item := range items {
+		qty, ok := requested[item.ID]
+		if !ok {
+			continue
+		}
+		if remaining := item.Quantity - item.ReceivedQuantity; qty > remaining {
+			return nil, fmt.Errorf("%w: item %d only has %d unit(s) left to receive (requested: %d)",
+				ErrInvalidInput, item.ID, remaining, qty)
+		}
+		receiptItems = append(receiptItems, models.PurchaseReceiptItem{
+			PurchaseOrderItemID: item.ID,
+			ProductID:           item.ProductID,
+			Quantity:            qty,
+			CostPrice:           item.CostPrice,
+		})
+	}
+
+	if len(receiptItems) == 0 {
+		return nil, fmt.Errorf("%w: nothing left to receive", ErrConflict)
+	}
+
+	return receiptItems, nil
+}
+
+// statusAfterReceipt menentukan status PO setelah penerimaan diterapkan pada items.
+func statusAfterReceipt(items []models.PurchaseOrderItem, received []models.PurchaseReceiptItem) string {
+	got := make(map[int]int, len(received))
+	for _, r := range received {
+		got[r.PurchaseOrderItemID] += r.Quantity
+	}
+
+	for _, item := range items {
+		if item.ReceivedQuantity+got[item.ID] < item.Quantity {
+			return models.PurchaseOrderStatusPartiallyReceived
+		}
+	}
+	return models.PurchaseOrderStatusReceived
+}
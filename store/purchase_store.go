@@ -0,0 +1,615 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"kasir-api/models"
+)
+
+// AddSupplier menambahkan supplier baru.
+func (s *PostgresStore) AddSupplier(ctx context.Context, sup models.Supplier) (models.Supplier, error) {
+	if strings.TrimSpace(sup.Nama) == "" {
+		return models.Supplier{}, fmt.Errorf("%w: nama is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[purchase-store] Error begin transaction: %v", err)
+		return models.Supplier{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO supplier (nama, kontak, alamat) VALUES ($1, $2, $3) RETURNING id, created_at",
+		sup.Nama, sup.Kontak, sup.Alamat,
+	).Scan(&sup.ID, &sup.CreatedAt)
+	if err != nil {
+		log.Printf("[purchase-store] Error AddSupplier: %v", err)
+		return models.Supplier{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntitySupplier, models.AuditActionCreate, sup.ID, nil, sup); err != nil {
+		return models.Supplier{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[purchase-store] Error commit AddSupplier: %v", err)
+		return models.Supplier{}, err
+	}
+
+	return sup, nil
+}
+
+// GetAllSuppliers mengembalikan semua supplier.
+func (s *PostgresStore) GetAllSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, nama, kontak, alamat, created_at FROM supplier ORDER BY id")
+	if err != nil {
+		log.Printf("[purchase-store] Error GetAllSuppliers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		var sup models.Supplier
+		if err := rows.Scan(&sup.ID, &sup.Nama, &sup.Kontak, &sup.Alamat, &sup.CreatedAt); err != nil {
+			log.Printf("[purchase-store] Error scanning supplier row: %v", err)
+			continue
+		}
+		suppliers = append(suppliers, sup)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[purchase-store] Error iterating supplier rows: %v", err)
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+// CreatePurchaseOrder membuat PO berstatus draft beserta itemnya. Setiap
+// perubahan PO dicatat di audit log dalam database transaction yang sama.
+func (s *PostgresStore) CreatePurchaseOrder(ctx context.Context, req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if err := validatePurchaseOrder(req); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[purchase-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM supplier WHERE id = $1)", req.SupplierID).Scan(&exists)
+	if err != nil {
+		log.Printf("[purchase-store] Error check supplier: %v", err)
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: supplier id %d not found", ErrInvalidInput, req.SupplierID)
+	}
+
+	var id int
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO purchase_orders (supplier_id, note, created_by) VALUES ($1, $2, $3) RETURNING id",
+		req.SupplierID, req.Note, req.Operator).Scan(&id)
+	if err != nil {
+		log.Printf("[purchase-store] Error insert purchase order: %v", err)
+		return nil, err
+	}
+
+	for i, item := range req.Items {
+		err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM produk WHERE id = $1)", item.ProductID).Scan(&exists)
+		if err != nil {
+			log.Printf("[purchase-store] Error check product: %v", err)
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: item %d product id %d not found", ErrInvalidInput, i+1, item.ProductID)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity, cost_price) VALUES ($1, $2, $3, $4)",
+			id, item.ProductID, item.Quantity, item.CostPrice)
+		if err != nil {
+			log.Printf("[purchase-store] Error insert purchase order item: %v", err)
+			return nil, err
+		}
+	}
+
+	po, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAudit(ctx, tx, models.AuditEntityPurchaseOrder, models.AuditActionCreate, id, nil, po); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[purchase-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[purchase-store] Purchase order created id=%d supplier_id=%d items=%d", id, req.SupplierID, len(req.Items))
+	return po, nil
+}
+
+// GetPurchaseOrder mengembalikan satu PO beserta item dan riwayat penerimaannya.
+func (s *PostgresStore) GetPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	return getPurchaseOrder(ctx, s.db, id)
+}
+
+// getPurchaseOrder membaca satu PO lewat q, dipakai juga di dalam database
+// transaction untuk data audit sebelum dan sesudah perubahan.
+func getPurchaseOrder(ctx context.Context, q queryer, id int) (*models.PurchaseOrder, error) {
+	row := q.QueryRowContext(ctx, `
+		SELECT id, supplier_id, status, note, created_by, approved_by, approved_at, cancelled_by, cancelled_at, created_at
+		FROM purchase_orders
+		WHERE id = $1
+	`, id)
+	po, err := scanPurchaseOrder(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: purchase order id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[purchase-store] Error get purchase order: %v", err)
+		return nil, err
+	}
+
+	items, err := getPurchaseOrderItems(ctx, q, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	po.Items = items[id]
+	for _, item := range po.Items {
+		po.TotalCost += item.Quantity * item.CostPrice
+	}
+
+	receipts, err := getPurchaseReceipts(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	po.Receipts = receipts
+
+	return &po, nil
+}
+
+// GetAllPurchaseOrders mengembalikan daftar PO beserta itemnya, terbaru lebih dulu.
+func (s *PostgresStore) GetAllPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	query := `
+		SELECT id, supplier_id, status, note, created_by, approved_by, approved_at, cancelled_by, cancelled_at, created_at
+		FROM purchase_orders`
+	var conditions []string
+	var args []interface{}
+
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Outstanding {
+		conditions = append(conditions, fmt.Sprintf("status IN ('%s', '%s')",
+			models.PurchaseOrderStatusApproved, models.PurchaseOrderStatusPartiallyReceived))
+	}
+	if filter.SupplierID > 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("supplier_id = $%d", len(args)))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("[purchase-store] Error GetAllPurchaseOrders: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.PurchaseOrder{}
+	var ids []int
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			log.Printf("[purchase-store] Error scanning purchase order row: %v", err)
+			continue
+		}
+		orders = append(orders, po)
+		ids = append(ids, po.ID)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[purchase-store] Error iterating purchase order rows: %v", err)
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return orders, nil
+	}
+
+	items, err := getPurchaseOrderItems(ctx, s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+		for _, item := range orders[i].Items {
+			orders[i].TotalCost += item.Quantity * item.CostPrice
+		}
+	}
+
+	return orders, nil
+}
+
+// ApprovePurchaseOrder menyetujui PO draft sehingga barangnya bisa diterima.
+func (s *PostgresStore) ApprovePurchaseOrder(ctx context.Context, id int, req models.PurchaseOrderActionRequest) (*models.PurchaseOrder, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[purchase-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrderStatusDraft {
+		return nil, fmt.Errorf("%w: purchase order %d is %s, only draft orders can be approved", ErrConflict, id, status)
+	}
+	before, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE purchase_orders SET status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP WHERE id = $3",
+		models.PurchaseOrderStatusApproved, req.Operator, id)
+	if err != nil {
+		log.Printf("[purchase-store] Error approve purchase order: %v", err)
+		return nil, err
+	}
+
+	after, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAudit(ctx, tx, models.AuditEntityPurchaseOrder, models.AuditActionUpdate, id, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[purchase-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[purchase-store] Purchase order approved id=%d by=%s", id, req.Operator)
+	return after, nil
+}
+
+// ReceivePurchaseOrder mencatat penerimaan barang (boleh sebagian), menambah
+// stok lewat ledger, dan memperbarui harga beli terakhir produk dalam satu
+// database transaction. Perubahan PO dan harga beli produk dicatat di audit log.
+func (s *PostgresStore) ReceivePurchaseOrder(ctx context.Context, id int, req models.ReceivePurchaseOrderRequest) (*models.PurchaseReceipt, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[purchase-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Kunci PO agar penerimaan bersamaan tidak melebihi jumlah yang dipesan.
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrderStatusApproved && status != models.PurchaseOrderStatusPartiallyReceived {
+		return nil, fmt.Errorf("%w: purchase order %d is %s", ErrConflict, id, status)
+	}
+
+	before, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	receiptItems, err := planReceipt(before.Items, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := models.PurchaseReceipt{PurchaseOrderID: id, ReceivedBy: req.Operator, Note: req.Note}
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO purchase_receipts (purchase_order_id, received_by, note) VALUES ($1, $2, $3) RETURNING id, created_at",
+		id, req.Operator, req.Note).Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		log.Printf("[purchase-store] Error insert receipt: %v", err)
+		return nil, err
+	}
+
+	for i := range receiptItems {
+		item := &receiptItems[i]
+		item.ReceiptID = receipt.ID
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO purchase_receipt_items (receipt_id, purchase_order_item_id, product_id, quantity, cost_price)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			receipt.ID, item.PurchaseOrderItemID, item.ProductID, item.Quantity, item.CostPrice,
+		).Scan(&item.ID)
+		if err != nil {
+			log.Printf("[purchase-store] Error insert receipt item: %v", err)
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE purchase_order_items SET received_quantity = received_quantity + $1 WHERE id = $2",
+			item.Quantity, item.PurchaseOrderItemID); err != nil {
+			log.Printf("[purchase-store] Error update received quantity: %v", err)
+			return nil, err
+		}
+	}
+
+	// Tambah stok lewat ledger dengan referensi penerimaan, urut ID produk seperti
+	// checkout agar keduanya mengunci baris produk dengan urutan yang sama.
+	for _, i := range stockOrder(len(receiptItems), func(i int) int { return receiptItems[i].ProductID }) {
+		item := receiptItems[i]
+		_, err := adjustStock(ctx, tx, models.StockMovement{
+			ProductID:   item.ProductID,
+			Delta:       item.Quantity,
+			Reason:      models.StockReasonPurchase,
+			ReferenceID: &receipt.ID,
+			Operator:    req.Operator,
+			Note:        fmt.Sprintf("penerimaan PO #%d", id),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := updateProductCost(ctx, tx, item.ProductID, item.CostPrice); err != nil {
+			return nil, err
+		}
+	}
+	receipt.Items = receiptItems
+
+	newStatus := statusAfterReceipt(before.Items, receiptItems)
+	if _, err := tx.ExecContext(ctx, "UPDATE purchase_orders SET status = $1 WHERE id = $2", newStatus, id); err != nil {
+		log.Printf("[purchase-store] Error update purchase order status: %v", err)
+		return nil, err
+	}
+
+	after, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAudit(ctx, tx, models.AuditEntityPurchaseOrder, models.AuditActionUpdate, id, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[purchase-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[purchase-store] Purchase order received id=%d receipt_id=%d items=%d status=%s",
+		id, receipt.ID, len(receiptItems), newStatus)
+	return &receipt, nil
+}
+
+// CancelPurchaseOrder membatalkan PO yang belum diterima penuh. Barang yang
+// sudah diterima tetap di stok; sisa pesanan tidak lagi ditunggu.
+func (s *PostgresStore) CancelPurchaseOrder(ctx context.Context, id int, req models.PurchaseOrderActionRequest) (*models.PurchaseOrder, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[purchase-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == models.PurchaseOrderStatusReceived || status == models.PurchaseOrderStatusCancelled {
+		return nil, fmt.Errorf("%w: purchase order %d is already %s", ErrConflict, id, status)
+	}
+	before, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE purchase_orders SET status = $1, cancelled_by = $2, cancelled_at = CURRENT_TIMESTAMP WHERE id = $3",
+		models.PurchaseOrderStatusCancelled, req.Operator, id)
+	if err != nil {
+		log.Printf("[purchase-store] Error cancel purchase order: %v", err)
+		return nil, err
+	}
+
+	after, err := getPurchaseOrder(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertAudit(ctx, tx, models.AuditEntityPurchaseOrder, models.AuditActionUpdate, id, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[purchase-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[purchase-store] Purchase order cancelled id=%d by=%s", id, req.Operator)
+	return after, nil
+}
+
+// updateProductCost memperbarui harga beli terakhir produk dari penerimaan PO
+// dan mencatat perubahannya di audit log. Harga yang sama tidak dicatat.
+func updateProductCost(ctx context.Context, tx *sql.Tx, productID, costPrice int) error {
+	var before models.Produk
+	err := tx.QueryRowContext(ctx, "SELECT id, nama, harga, harga_beli, stok, kategori_id FROM produk WHERE id = $1 FOR UPDATE", productID).
+		Scan(&before.ID, &before.Nama, &before.Harga, &before.HargaBeli, &before.Stok, &before.KategoriID)
+	if err != nil {
+		log.Printf("[purchase-store] Error get product cost: %v", err)
+		return err
+	}
+	if before.HargaBeli == costPrice {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE produk SET harga_beli = $1 WHERE id = $2", costPrice, productID); err != nil {
+		log.Printf("[purchase-store] Error update product cost: %v", err)
+		return err
+	}
+
+	after := before
+	after.HargaBeli = costPrice
+	return insertAudit(ctx, tx, models.AuditEntityProduk, models.AuditActionUpdate, productID, before, after)
+}
+
+// lockPurchaseOrder mengunci baris PO dan mengembalikan statusnya.
+func lockPurchaseOrder(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: purchase order id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[purchase-store] Error lock purchase order: %v", err)
+		return "", err
+	}
+	return status, nil
+}
+
+// scanPurchaseOrder membaca satu baris header PO.
+func scanPurchaseOrder(row rowScanner) (models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	var approvedAt, cancelledAt sql.NullTime
+	err := row.Scan(&po.ID, &po.SupplierID, &po.Status, &po.Note, &po.CreatedBy,
+		&po.ApprovedBy, &approvedAt, &po.CancelledBy, &cancelledAt, &po.CreatedAt)
+	if err != nil {
+		return models.PurchaseOrder{}, err
+	}
+	if approvedAt.Valid {
+		po.ApprovedAt = &approvedAt.Time
+	}
+	if cancelledAt.Valid {
+		po.CancelledAt = &cancelledAt.Time
+	}
+	return po, nil
+}
+
+// getPurchaseOrderItems mengambil item untuk beberapa PO sekaligus, dikelompokkan per ID PO.
+func getPurchaseOrderItems(ctx context.Context, q queryer, ids []int) (map[int][]models.PurchaseOrderItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT i.id, i.purchase_order_id, i.product_id, p.nama, i.quantity, i.cost_price, i.received_quantity
+		FROM purchase_order_items i
+		JOIN produk p ON p.id = i.product_id
+		WHERE i.purchase_order_id = ANY($1)
+		ORDER BY i.id
+	`, pq.Array(ids))
+	if err != nil {
+		log.Printf("[purchase-store] Error get purchase order items: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[int][]models.PurchaseOrderItem, len(ids))
+	for rows.Next() {
+		var item models.PurchaseOrderItem
+		err := rows.Scan(&item.ID, &item.PurchaseOrderID, &item.ProductID, &item.ProductName,
+			&item.Quantity, &item.CostPrice, &item.ReceivedQuantity)
+		if err != nil {
+			log.Printf("[purchase-store] Error scanning purchase order item row: %v", err)
+			continue
+		}
+		items[item.PurchaseOrderID] = append(items[item.PurchaseOrderID], item)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[purchase-store] Error iterating purchase order item rows: %v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// getPurchaseReceipts mengambil semua penerimaan untuk satu PO beserta itemnya.
+func getPurchaseReceipts(ctx context.Context, q queryer, purchaseOrderID int) ([]models.PurchaseReceipt, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, purchase_order_id, received_by, note, created_at
+		FROM purchase_receipts
+		WHERE purchase_order_id = $1
+		ORDER BY id
+	`, purchaseOrderID)
+	if err != nil {
+		log.Printf("[purchase-store] Error get receipts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []models.PurchaseReceipt
+	index := make(map[int]int)
+	for rows.Next() {
+		var r models.PurchaseReceipt
+		if err := rows.Scan(&r.ID, &r.PurchaseOrderID, &r.ReceivedBy, &r.Note, &r.CreatedAt); err != nil {
+			log.Printf("[purchase-store] Error scanning receipt row: %v", err)
+			continue
+		}
+		index[r.ID] = len(receipts)
+		receipts = append(receipts, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[purchase-store] Error iterating receipt rows: %v", err)
+		return nil, err
+	}
+
+	if len(receipts) == 0 {
+		return receipts, nil
+	}
+
+	itemRows, err := q.QueryContext(ctx, `
+		SELECT ri.id, ri.receipt_id, ri.purchase_order_item_id, ri.product_id, ri.quantity, ri.cost_price
+		FROM purchase_receipt_items ri
+		JOIN purchase_receipts r ON r.id = ri.receipt_id
+		WHERE r.purchase_order_id = $1
+		ORDER BY ri.id
+	`, purchaseOrderID)
+	if err != nil {
+		log.Printf("[purchase-store] Error get receipt items: %v", err)
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item models.PurchaseReceiptItem
+		if err := itemRows.Scan(&item.ID, &item.ReceiptID, &item.PurchaseOrderItemID, &item.ProductID, &item.Quantity, &item.CostPrice); err != nil {
+			log.Printf("[purchase-store] Error scanning receipt item row: %v", err)
+			continue
+		}
+		if i, ok := index[item.ReceiptID]; ok {
+			receipts[i].Items = append(receipts[i].Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		log.Printf("[purchase-store] Error iterating receipt item rows: %v", err)
+		return nil, err
+	}
+
+	return receipts, nil
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"kasir-api/models"
+)
+
+// GetSalesSummary menghitung penjualan kotor dan bersih untuk rentang tanggal
+// [start, end). Void dan refund dihitung pada tanggal reversal dibuat, sehingga
+// periode yang sudah ditutup tidak berubah ketika barang dikembalikan belakangan.
+func (s *PostgresStore) GetSalesSummary(ctx context.Context, start, end time.Time) (models.SalesSummary, error) {
+	summary := models.SalesSummary{
+		StartDate: start.Format(time.DateOnly),
+		EndDate:   end.AddDate(0, 0, -1).Format(time.DateOnly),
+	}
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(total_amount), 0), COALESCE(SUM(rounding_adjustment), 0)
+		FROM transactions
+		WHERE created_at >= $1::date AND created_at < $2::date
+	`, startDate, endDate).Scan(&summary.TransactionCount, &summary.GrossSales, &summary.RoundingAdjustment)
+	if err != nil {
+		log.Printf("[report-store] Error get gross sales: %v", err)
+		return models.SalesSummary{}, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT type, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM transaction_reversals
+		WHERE created_at >= $1::date AND created_at < $2::date
+		GROUP BY type
+	`, startDate, endDate)
+	if err != nil {
+		log.Printf("[report-store] Error get reversals: %v", err)
+		return models.SalesSummary{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reversalType string
+		var count, amount int
+		if err := rows.Scan(&reversalType, &count, &amount); err != nil {
+			log.Printf("[report-store] Error scanning reversal summary row: %v", err)
+			return models.SalesSummary{}, err
+		}
+		switch reversalType {
+		case models.ReversalTypeVoid:
+			summary.VoidCount, summary.VoidedAmount = count, amount
+		case models.ReversalTypeRefund:
+			summary.RefundCount, summary.RefundedAmount = count, amount
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[report-store] Error iterating reversal summary rows: %v", err)
+		return models.SalesSummary{}, err
+	}
+
+	summary.NetSales = summary.GrossSales - summary.VoidedAmount - summary.RefundedAmount
+	return summary, nil
+}
+
+// reversedItemsCTE adalah CTE "reversed" berisi setiap item void/refund beserta
+// jumlah kumulatif barang yang sudah dikembalikan dari detailnya (qty_after).
+// Dipakai bersama reversedShare agar nilai yang dikurangkan laporan sama persis
+// dengan pembagian kumulatif pada reversalAmount.
+const reversedItemsCTE = `reversed AS (
+			SELECT ri.transaction_detail_id, ri.quantity, r.created_at,
+				SUM(ri.quantity) OVER (PARTITION BY ri.transaction_detail_id ORDER BY ri.id) AS qty_after
+			FROM transaction_reversal_items ri
+			JOIN transaction_reversals r ON r.id = ri.reversal_id
+		)`
+
+// reversedShare mengembalikan ekspresi SQL bagian nilai expr (milik detail td)
+// yang dikembalikan oleh item reversal rv, dihitung kumulatif seperti reversalAmount.
+func reversedShare(expr string) string {
+	return fmt.Sprintf("(%[1]s) * rv.qty_after / td.quantity - (%[1]s) * (rv.qty_after - rv.quantity) / td.quantity", expr)
+}
+
+// marginGroups berisi potongan query untuk setiap pengelompokan laporan margin:
+// kolom key, kolom nama, klausa GROUP BY, dan ORDER BY.
+var marginGroups = map[string]struct {
+	key, name, groupBy, orderBy string
+}{
+	models.MarginGroupProduct: {
+		key:     "COALESCE(l.product_id::text, '')",
+		name:    "l.product_name",
+		groupBy: "l.product_id, l.product_name",
+		orderBy: "l.product_id NULLS LAST, l.product_name",
+	},
+	models.MarginGroupKategori: {
+		key:     "COALESCE(l.kategori_id::text, '')",
+		name:    "COALESCE(NULLIF(l.kategori_nama, ''), 'Tanpa kategori')",
+		groupBy: "l.kategori_id, l.kategori_nama",
+		orderBy: "l.kategori_id NULLS LAST, l.kategori_nama",
+	},
+	models.MarginGroupDay: {
+		key:     "to_char(l.sold_at::date, 'YYYY-MM-DD')",
+		name:    "to_char(l.sold_at::date, 'YYYY-MM-DD')",
+		groupBy: "l.sold_at::date",
+		orderBy: "l.sold_at::date",
+	},
+}
+
+// IsValidMarginGroup melaporkan apakah groupBy adalah pengelompokan laporan margin yang dikenal.
+func IsValidMarginGroup(groupBy string) bool {
+	_, ok := marginGroups[groupBy]
+	return ok
+}
+
+// GetMarginReport menghitung laba kotor untuk rentang tanggal [start, end)
+// dikelompokkan per produk, kategori, atau hari. Nama, kategori, pendapatan,
+// dan HPP diambil dari snapshot di transaction_details saat transaksi, sehingga
+// perubahan produk setelahnya tidak mengubah laba historis. Pendapatan tidak
+// termasuk pajak dan service charge. Seperti GetSalesSummary, void dan refund
+// dikurangkan pada tanggal reversal dibuat.
+func (s *PostgresStore) GetMarginReport(ctx context.Context, start, end time.Time, groupBy string) (models.MarginReport, error) {
+	group, ok := marginGroups[groupBy]
+	if !ok {
+		return models.MarginReport{}, fmt.Errorf("%w: unknown group_by %q", ErrInvalidInput, groupBy)
+	}
+
+	report := models.MarginReport{
+		StartDate: start.Format(time.DateOnly),
+		EndDate:   end.AddDate(0, 0, -1).Format(time.DateOnly),
+		GroupBy:   groupBy,
+		Lines:     []models.MarginLine{},
+	}
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+
+	query := fmt.Sprintf(`
+		WITH %s,
+		lines AS (
+			SELECT t.created_at AS sold_at, td.product_id, td.product_name, td.kategori_id, td.kategori_nama,
+				td.quantity AS qty, td.subtotal - td.tax_included AS revenue, td.cost_price * td.quantity AS cost
+			FROM transaction_details td
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE t.created_at >= $1::date AND t.created_at < $2::date
+			UNION ALL
+			SELECT rv.created_at, td.product_id, td.product_name, td.kategori_id, td.kategori_nama,
+				-rv.quantity, -(%s), -(td.cost_price * rv.quantity)
+			FROM reversed rv
+			JOIN transaction_details td ON td.id = rv.transaction_detail_id
+			WHERE rv.created_at >= $1::date AND rv.created_at < $2::date
+		)
+		SELECT %s, %s, SUM(l.qty), SUM(l.revenue), SUM(l.cost)
+		FROM lines l
+		GROUP BY %s
+		ORDER BY %s
+	`, reversedItemsCTE, reversedShare("td.subtotal - td.tax_included"), group.key, group.name, group.groupBy, group.orderBy)
+
+	rows, err := s.db.QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		log.Printf("[report-store] Error get margin report: %v", err)
+		return models.MarginReport{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.MarginLine
+		if err := rows.Scan(&line.Key, &line.Name, &line.Quantity, &line.Revenue, &line.Cost); err != nil {
+			log.Printf("[report-store] Error scanning margin row: %v", err)
+			return models.MarginReport{}, err
+		}
+		report.Lines = append(report.Lines, finishMarginLine(line))
+
+		report.Total.Quantity += line.Quantity
+		report.Total.Revenue += line.Revenue
+		report.Total.Cost += line.Cost
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[report-store] Error iterating margin rows: %v", err)
+		return models.MarginReport{}, err
+	}
+
+	report.Total.Key, report.Total.Name = "total", "Total"
+	report.Total = finishMarginLine(report.Total)
+	return report, nil
+}
+
+// finishMarginLine menghitung laba kotor dan persentasenya dari pendapatan dan HPP.
+func finishMarginLine(line models.MarginLine) models.MarginLine {
+	line.GrossMargin = line.Revenue - line.Cost
+	if line.Revenue != 0 {
+		line.MarginPercent = math.Round(float64(line.GrossMargin)*10000/float64(line.Revenue)) / 100
+	}
+	return line
+}
+
+// GetTaxReport merangkum pajak dan service charge untuk rentang tanggal
+// [start, end) per aturan (nama, jenis, tarif, dan sifat inklusif saat
+// transaksi). Void dan refund mengurangi dasar pengenaan dan pajak pada tanggal
+// reversal dibuat, sebanding dengan jumlah barang yang dikembalikan.
+func (s *PostgresStore) GetTaxReport(ctx context.Context, start, end time.Time) (models.TaxReport, error) {
+	report := models.TaxReport{
+		StartDate: start.Format(time.DateOnly),
+		EndDate:   end.AddDate(0, 0, -1).Format(time.DateOnly),
+		Lines:     []models.TaxReportLine{},
+	}
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+
+	query := fmt.Sprintf(`
+		WITH %s,
+		lines AS (
+			SELECT tt.name, tt.type, tt.rate, tt.inclusive, tt.base, tt.amount
+			FROM transaction_taxes tt
+			JOIN transactions t ON t.id = tt.transaction_id
+			WHERE t.created_at >= $1::date AND t.created_at < $2::date
+			UNION ALL
+			SELECT tt.name, tt.type, tt.rate, tt.inclusive, -(%s), -(%s)
+			FROM reversed rv
+			JOIN transaction_details td ON td.id = rv.transaction_detail_id
+			JOIN transaction_taxes tt ON tt.transaction_detail_id = td.id
+			WHERE rv.created_at >= $1::date AND rv.created_at < $2::date
+		)
+		SELECT name, type, rate, inclusive, SUM(base), SUM(amount)
+		FROM lines
+		GROUP BY type, name, rate, inclusive
+		ORDER BY type, name, rate, inclusive
+	`, reversedItemsCTE, reversedShare("tt.base"), reversedShare("tt.amount"))
+
+	rows, err := s.db.QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		log.Printf("[report-store] Error get tax report: %v", err)
+		return models.TaxReport{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.TaxReportLine
+		if err := rows.Scan(&line.Name, &line.Type, &line.Rate, &line.Inclusive, &line.Base, &line.Amount); err != nil {
+			log.Printf("[report-store] Error scanning tax row: %v", err)
+			return models.TaxReport{}, err
+		}
+		report.Lines = append(report.Lines, line)
+
+		if line.Type == models.TaxTypeService {
+			report.TotalService += line.Amount
+		} else {
+			report.TotalTax += line.Amount
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[report-store] Error iterating tax rows: %v", err)
+		return models.TaxReport{}, err
+	}
+
+	return report, nil
+}
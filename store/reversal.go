@@ -0,0 +1,128 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// validateReversalActor memastikan alasan dan petugas void/refund terisi.
+func validateReversalActor(reason, operator string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: reason is required", ErrInvalidReversal)
+	}
+	if strings.TrimSpace(operator) == "" {
+		return fmt.Errorf("%w: operator is required", ErrInvalidReversal)
+	}
+	return nil
+}
+
+// planReversal menyusun item reversal dari permintaan refund terhadap detail
+// transaksi. RefundedQuantity pada details harus berisi jumlah yang sudah
+// dikembalikan sebelumnya. Items kosong berarti semua sisa barang dikembalikan.
+func planReversal(details []models.TransactionDetail, items []models.RefundItem) ([]models.ReversalItem, int, error) {
+	// Gabungkan permintaan untuk detail yang sama.
+	requested := make(map[int]int)
+	if len(items) == 0 {
+		for _, d := range details {
+			if remaining := d.Quantity - d.RefundedQuantity; remaining > 0 {
+				requested[d.ID] = remaining
+			}
+		}
+	}
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, 0, fmt.Errorf("%w: quantity for detail %d must be greater than zero", ErrInvalidReversal, item.DetailID)
+		}
+		requested[item.DetailID] += item.Quantity
+	}
+
+	byID := make(map[int]models.TransactionDetail, len(details))
+	for _, d := range details {
+		byID[d.ID] = d
+	}
+	for detailID := range requested {
+		if _, ok := byID[detailID]; !ok {
+			return nil, 0, fmt.Errorf("%w: detail %d does not belong to this transaction", ErrInvalidReversal, detailID)
+		}
+	}
+
+	// Susun item mengikuti urutan detail agar hasilnya stabil.
+	reversalItems := make([]models.ReversalItem, 0, len(requested))
+	total := 0
+	for _, d := range details {
+		qty, ok := requested[d.ID]
+		if !ok {
+			continue
+		}
+		if remaining := d.Quantity - d.RefundedQuantity; qty > remaining {
+			return nil, 0, fmt.Errorf("%w: detail %d only has %d item(s) left to refund (requested: %d)",
+				ErrInvalidReversal, d.ID, remaining, qty)
+		}
+
+		amount := reversalAmount(d, qty)
+		total += amount
+		reversalItems = append(reversalItems, models.ReversalItem{
+			TransactionDetailID: d.ID,
+			ProductID:           d.ProductID,
+			Quantity:            qty,
+			Amount:              amount,
+		})
+	}
+
+	if len(reversalItems) == 0 {
+		return nil, 0, fmt.Errorf("%w: nothing left to refund", ErrReversalNotAllowed)
+	}
+
+	return reversalItems, total, nil
+}
+
+// reversalAmount menghitung nilai uang untuk qty barang dari satu detail. Nilai
+// dihitung secara kumulatif dari Total (termasuk pajak dan service) sehingga
+// jumlah semua refund sebuah detail selalu sama persis dengan yang dibayar,
+// tanpa selisih pembulatan.
+func reversalAmount(d models.TransactionDetail, qty int) int {
+	before := d.Total * d.RefundedQuantity / d.Quantity
+	after := d.Total * (d.RefundedQuantity + qty) / d.Quantity
+	return after - before
+}
+
+// refundPointsShare menghitung bagian refund senilai amount yang dulu dibayar
+// dengan poin, sebanding dengan porsi pembayaran poin pada transaksi. Bagian itu
+// dihitung kumulatif dari nilai yang sudah di-refund (RefundedQuantity pada
+// details) agar semua refund satu transaksi mengembalikan tepat pointsAmount
+// rupiah dan pointsRedeemed poin tanpa selisih pembulatan. Mengembalikan nilai
+// rupiah yang tidak dibayar dari laci dan jumlah poin yang dikembalikan.
+func refundPointsShare(details []models.TransactionDetail, amount, pointsAmount, pointsRedeemed int) (int, int) {
+	total, refunded := 0, 0
+	for _, d := range details {
+		total += d.Total
+		if d.Quantity > 0 {
+			refunded += d.Total * d.RefundedQuantity / d.Quantity
+		}
+	}
+	if total <= 0 || (pointsAmount == 0 && pointsRedeemed == 0) {
+		return 0, 0
+	}
+
+	after := min(refunded+amount, total)
+	value := pointsAmount*after/total - pointsAmount*refunded/total
+	points := pointsRedeemed*after/total - pointsRedeemed*refunded/total
+	return min(value, amount), points
+}
+
+// statusAfterRefund menentukan status transaksi setelah refund diterapkan pada details.
+func statusAfterRefund(details []models.TransactionDetail, items []models.ReversalItem) string {
+	refunded := make(map[int]int, len(items))
+	for _, item := range items {
+		refunded[item.TransactionDetailID] += item.Quantity
+	}
+
+	for _, d := range details {
+		if d.RefundedQuantity+refunded[d.ID] < d.Quantity {
+			return models.TransactionStatusPartiallyRefunded
+		}
+	}
+	return models.TransactionStatusRefunded
+}
@@ -0,0 +1,371 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// VoidTransaction membatalkan seluruh transaksi pada hari yang sama, mengembalikan
+// semua barang ke stok, dan mencatat reversal dalam satu database transaction.
+// Void butuh persetujuan manajer yang dicatat bersama reversal-nya.
+func (s *PostgresStore) VoidTransaction(ctx context.Context, id int, req models.VoidRequest) (*models.Reversal, error) {
+	if err := validateReversalActor(req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	approval, err := requireApproval(ctx, models.ApprovalActionVoid, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[reversal-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Kunci baris transaksi agar void/refund bersamaan tidak saling menimpa.
+	var status string
+	var totalAmount, pointsRedeemed int
+	var sameDay bool
+	var customerID, shiftID sql.NullInt64
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, total_amount, created_at::date = CURRENT_DATE, customer_id, shift_id, points_redeemed
+		FROM transactions WHERE id = $1 FOR UPDATE
+	`, id).Scan(&status, &totalAmount, &sameDay, &customerID, &shiftID, &pointsRedeemed)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: transaction id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[reversal-store] Error get transaction: %v", err)
+		return nil, err
+	}
+
+	if status != models.TransactionStatusCompleted {
+		return nil, fmt.Errorf("%w: transaction %d is %s, only completed transactions can be voided",
+			ErrReversalNotAllowed, id, status)
+	}
+	if !sameDay {
+		return nil, fmt.Errorf("%w: transaction %d was not made today, use refund instead", ErrReversalNotAllowed, id)
+	}
+
+	// Uang void keluar dari laci shift asal transaksi, jadi shift itu harus masih terbuka.
+	if shiftID.Valid {
+		if err := lockShiftForReversal(ctx, tx, int(shiftID.Int64)); err != nil {
+			return nil, err
+		}
+	}
+
+	details, err := getTransactionDetails(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	items, _, err := planReversal(details, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Balik poin perolehan dan kembalikan poin yang ditukar. Pelanggan dikunci
+	// sebelum voucher dan stok, urutan kunci yang sama dengan checkout.
+	pointsReversed, pointsRestored := 0, 0
+	if customerID.Valid {
+		pointsReversed, pointsRestored = reversalPoints(details, items), pointsRedeemed
+	}
+	err = reverseTransactionPoints(ctx, tx, int(customerID.Int64), id, pointsReversed, pointsRestored)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lepas penukaran voucher sebelum stok dikembalikan, urutan kunci yang sama dengan checkout.
+	if err := releaseVoucherRedemption(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	pointsAmount, err := pointsPaid(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Void mengembalikan seluruh uang yang dibayar untuk transaksi.
+	reversal, err := insertReversal(ctx, tx, models.Reversal{
+		TransactionID:  id,
+		Type:           models.ReversalTypeVoid,
+		ShiftID:        int(shiftID.Int64),
+		Amount:         totalAmount,
+		PointsAmount:   pointsAmount,
+		PointsReversed: pointsReversed,
+		PointsRestored: pointsRestored,
+		Reason:         req.Reason,
+		Operator:       req.Operator,
+		Items:          items,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := insertApproval(ctx, tx, approval, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE transactions SET status = $1 WHERE id = $2",
+		models.TransactionStatusVoided, id); err != nil {
+		log.Printf("[reversal-store] Error update transaction status: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[reversal-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[reversal-store] Transaction voided id=%d reversal_id=%d amount=%d", id, reversal.ID, reversal.Amount)
+	return reversal, nil
+}
+
+// RefundTransaction mengembalikan sebagian atau seluruh barang dari transaksi,
+// menambah stok kembali, dan mencatat reversal dalam satu database transaction.
+func (s *PostgresStore) RefundTransaction(ctx context.Context, id int, req models.RefundRequest) (*models.Reversal, error) {
+	if err := validateReversalActor(req.Reason, req.Operator); err != nil {
+		return nil, err
+	}
+	if err := validateRefundRegister(req.Register); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[reversal-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Kunci baris transaksi agar refund bersamaan tidak melebihi jumlah yang dibeli.
+	var status string
+	var customerID sql.NullInt64
+	var pointsRedeemed int
+	err = tx.QueryRowContext(ctx, "SELECT status, customer_id, points_redeemed FROM transactions WHERE id = $1 FOR UPDATE", id).
+		Scan(&status, &customerID, &pointsRedeemed)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: transaction id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[reversal-store] Error get transaction: %v", err)
+		return nil, err
+	}
+
+	if status != models.TransactionStatusCompleted && status != models.TransactionStatusPartiallyRefunded {
+		return nil, fmt.Errorf("%w: transaction %d is %s", ErrReversalNotAllowed, id, status)
+	}
+
+	// Refund dibayar tunai dari laci register yang memprosesnya.
+	shiftID, err := lockOpenShift(ctx, tx, req.Register)
+	if errors.Is(err, ErrConflict) {
+		return nil, fmt.Errorf("%w: no open shift on register %q", ErrReversalNotAllowed, req.Register)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := getTransactionDetails(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	items, amount, err := planReversal(details, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bagian yang dulu dibayar dengan poin dikembalikan sebagai poin, bukan uang
+	// dari laci; poin perolehan dibalik sebanding dengan barang yang dikembalikan.
+	pointsAmount, err := pointsPaid(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	pointsAmount, pointsRestored := refundPointsShare(details, amount, pointsAmount, pointsRedeemed)
+	pointsReversed := 0
+	if customerID.Valid {
+		pointsReversed = reversalPoints(details, items)
+	} else {
+		pointsRestored = 0
+	}
+	err = reverseTransactionPoints(ctx, tx, int(customerID.Int64), id, pointsReversed, pointsRestored)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal, err := insertReversal(ctx, tx, models.Reversal{
+		TransactionID:  id,
+		Type:           models.ReversalTypeRefund,
+		ShiftID:        shiftID,
+		Amount:         amount,
+		PointsAmount:   pointsAmount,
+		PointsReversed: pointsReversed,
+		PointsRestored: pointsRestored,
+		Reason:         req.Reason,
+		Operator:       req.Operator,
+		Items:          items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newStatus := statusAfterRefund(details, items)
+	if _, err := tx.ExecContext(ctx, "UPDATE transactions SET status = $1 WHERE id = $2", newStatus, id); err != nil {
+		log.Printf("[reversal-store] Error update transaction status: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[reversal-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[reversal-store] Transaction refunded id=%d reversal_id=%d amount=%d status=%s",
+		id, reversal.ID, reversal.Amount, newStatus)
+	return reversal, nil
+}
+
+// pointsPaid mengembalikan jumlah rupiah transaksi yang dibayar dengan poin.
+func pointsPaid(ctx context.Context, q queryer, transactionID int) (int, error) {
+	var amount int
+	err := q.QueryRowContext(ctx,
+		"SELECT COALESCE(SUM(amount), 0) FROM transaction_payments WHERE transaction_id = $1 AND method = $2",
+		transactionID, models.PaymentMethodPoints).Scan(&amount)
+	if err != nil {
+		log.Printf("[reversal-store] Error get points payment: %v", err)
+		return 0, err
+	}
+	return amount, nil
+}
+
+// insertReversal menyimpan reversal beserta itemnya dan mengembalikan barang ke stok.
+func insertReversal(ctx context.Context, tx *sql.Tx, r models.Reversal) (*models.Reversal, error) {
+	err := tx.QueryRowContext(ctx,
+		`INSERT INTO transaction_reversals (transaction_id, shift_id, type, amount, points_amount, points_reversed, points_restored,
+			reason, operator)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`,
+		r.TransactionID, nullableID(r.ShiftID), r.Type, r.Amount, r.PointsAmount, r.PointsReversed, r.PointsRestored,
+		r.Reason, r.Operator,
+	).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		log.Printf("[reversal-store] Error insert reversal: %v", err)
+		return nil, err
+	}
+
+	for i := range r.Items {
+		item := &r.Items[i]
+		item.ReversalID = r.ID
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO transaction_reversal_items (reversal_id, transaction_detail_id, product_id, quantity, amount)
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			r.ID, item.TransactionDetailID, nullableID(item.ProductID), item.Quantity, item.Amount,
+		).Scan(&item.ID)
+		if err != nil {
+			log.Printf("[reversal-store] Error insert reversal item: %v", err)
+			return nil, err
+		}
+	}
+
+	// Kembalikan barang ke stok lewat ledger dengan referensi reversal, urut ID
+	// produk seperti checkout agar keduanya mengunci baris produk dengan urutan sama.
+	reason := models.StockReasonRefund
+	if r.Type == models.ReversalTypeVoid {
+		reason = models.StockReasonVoid
+	}
+	for _, i := range stockOrder(len(r.Items), func(i int) int { return r.Items[i].ProductID }) {
+		item := r.Items[i]
+		// Produk yang sudah dihapus tidak punya stok untuk dikembalikan.
+		if item.ProductID == 0 {
+			continue
+		}
+
+		_, err := adjustStock(ctx, tx, models.StockMovement{
+			ProductID:   item.ProductID,
+			Delta:       item.Quantity,
+			Reason:      reason,
+			ReferenceID: &r.ID,
+			Operator:    r.Operator,
+			Note:        r.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// getTransactionReversals mengambil semua void/refund untuk satu transaksi beserta itemnya.
+func getTransactionReversals(ctx context.Context, q queryer, transactionID int) ([]models.Reversal, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, transaction_id, shift_id, type, amount, points_amount, points_reversed, points_restored, reason, operator,
+			created_at
+		FROM transaction_reversals
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		log.Printf("[reversal-store] Error get reversals: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reversals []models.Reversal
+	index := make(map[int]int)
+	for rows.Next() {
+		var r models.Reversal
+		var shiftID sql.NullInt64
+		err := rows.Scan(&r.ID, &r.TransactionID, &shiftID, &r.Type, &r.Amount, &r.PointsAmount, &r.PointsReversed, &r.PointsRestored,
+			&r.Reason, &r.Operator, &r.CreatedAt)
+		if err != nil {
+			log.Printf("[reversal-store] Error scanning reversal row: %v", err)
+			continue
+		}
+		r.ShiftID = int(shiftID.Int64)
+		index[r.ID] = len(reversals)
+		reversals = append(reversals, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[reversal-store] Error iterating reversal rows: %v", err)
+		return nil, err
+	}
+
+	if len(reversals) == 0 {
+		return reversals, nil
+	}
+
+	itemRows, err := q.QueryContext(ctx, `
+		SELECT ri.id, ri.reversal_id, ri.transaction_detail_id, ri.product_id, ri.quantity, ri.amount
+		FROM transaction_reversal_items ri
+		JOIN transaction_reversals r ON r.id = ri.reversal_id
+		WHERE r.transaction_id = $1
+		ORDER BY ri.id
+	`, transactionID)
+	if err != nil {
+		log.Printf("[reversal-store] Error get reversal items: %v", err)
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item models.ReversalItem
+		var productID sql.NullInt64
+		if err := itemRows.Scan(&item.ID, &item.ReversalID, &item.TransactionDetailID, &productID, &item.Quantity, &item.Amount); err != nil {
+			log.Printf("[reversal-store] Error scanning reversal item row: %v", err)
+			continue
+		}
+		item.ProductID = int(productID.Int64)
+		if i, ok := index[item.ReversalID]; ok {
+			reversals[i].Items = append(reversals[i].Items, item)
+		}
+	}
+	if err := itemRows.Err(); err != nil {
+		log.Printf("[reversal-store] Error iterating reversal item rows: %v", err)
+		return nil, err
+	}
+
+	return reversals, nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestRefundPointsShare(t *testing.T) {
+	// Dua baris senilai 100.000; 30.000 dibayar dengan 300 poin, sisanya tunai.
+	details := []models.TransactionDetail{
+		{ID: 1, Quantity: 3, Total: 60000},
+		{ID: 2, Quantity: 1, Total: 40000},
+	}
+
+	// Refund pertama: satu barang dari baris 1 (20.000).
+	value, points := refundPointsShare(details, 20000, 30000, 300)
+	if value != 6000 || points != 60 {
+		t.Fatalf("first refund value/points = %d/%d, want 6000/60", value, points)
+	}
+
+	// Refund sisanya: semua poin yang tersisa kembali tanpa selisih pembulatan.
+	details[0].RefundedQuantity = 1
+	value2, points2 := refundPointsShare(details, 80000, 30000, 300)
+	if value+value2 != 30000 || points+points2 != 300 {
+		t.Errorf("total value/points = %d/%d, want 30000/300", value+value2, points+points2)
+	}
+}
+
+func TestShiftReportRefundPaidWithPoints(t *testing.T) {
+	sales := []shiftSale{{TransactionID: 1, Breakdown: []models.PaymentBreakdown{
+		{Method: models.PaymentMethodCash, Amount: 70000, Tendered: 70000},
+		{Method: models.PaymentMethodPoints, Amount: 30000, Tendered: 30000},
+	}}}
+	reversals := []models.Reversal{{TransactionID: 1, Type: models.ReversalTypeRefund, Amount: 100000, PointsAmount: 30000}}
+
+	report := shiftReport(50000, sales, reversals, nil, nil)
+
+	for _, l := range report {
+		switch l.Method {
+		case models.PaymentMethodCash:
+			if l.Refunds != 70000 || l.Expected != 50000 {
+				t.Errorf("cash refunds/expected = %d/%d, want 70000/50000", l.Refunds, l.Expected)
+			}
+		case models.PaymentMethodPoints:
+			if l.Refunds != 30000 || l.Expected != 0 {
+				t.Errorf("points refunds/expected = %d/%d, want 30000/0", l.Refunds, l.Expected)
+			}
+		}
+	}
+}
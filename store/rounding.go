@@ -0,0 +1,81 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+
+	"kasir-api/models"
+)
+
+// validateRoundingPolicy memastikan kebijakan pembulatan lengkap dan konsisten.
+func validateRoundingPolicy(p models.RoundingPolicy) error {
+	if !validPaymentMethods[p.Method] {
+		return fmt.Errorf("%w: unknown payment method %q", ErrInvalidInput, p.Method)
+	}
+	switch p.Mode {
+	case models.RoundingModeNearest, models.RoundingModeUp, models.RoundingModeDown:
+	default:
+		return fmt.Errorf("%w: unknown rounding mode %q, use nearest, up or down", ErrInvalidInput, p.Mode)
+	}
+	if p.Unit <= 0 {
+		return fmt.Errorf("%w: unit must be greater than zero", ErrInvalidInput)
+	}
+	return nil
+}
+
+// roundAmount membulatkan amount (tidak negatif) ke kelipatan p.Unit sesuai p.Mode.
+func roundAmount(amount int, p models.RoundingPolicy) int {
+	remainder := amount % p.Unit
+	if remainder == 0 {
+		return amount
+	}
+	down := amount - remainder
+	switch p.Mode {
+	case models.RoundingModeUp:
+		return down + p.Unit
+	case models.RoundingModeDown:
+		return down
+	default:
+		if remainder*2 >= p.Unit {
+			return down + p.Unit
+		}
+		return down
+	}
+}
+
+// roundingAdjustment menghitung selisih pembulatan untuk total belanja. Baris
+// pembayaran dengan metode tanpa kebijakan (misalnya kartu) membayar nilai
+// persisnya; sisa tagihan dibulatkan memakai kebijakan metode pertama yang
+// punya kebijakan. Hasilnya ditambahkan ke total sebelum pembayaran divalidasi.
+func roundingAdjustment(total int, payments []models.CheckoutPayment, policies map[string]models.RoundingPolicy) int {
+	var policy *models.RoundingPolicy
+	exact := 0
+	for _, p := range payments {
+		if rp, ok := policies[p.Method]; ok {
+			if policy == nil {
+				policy = &rp
+			}
+			continue
+		}
+		exact += p.Amount
+	}
+	if policy == nil {
+		return 0
+	}
+
+	due := total - exact
+	if due <= 0 {
+		return 0
+	}
+	return roundAmount(due, *policy) - due
+}
+
+// sortedPolicyMethods mengembalikan metode pembayaran dari policies urut abjad.
+func sortedPolicyMethods(policies map[string]models.RoundingPolicy) []string {
+	methods := make([]string, 0, len(policies))
+	for method := range policies {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// GetAllRoundingPolicies mengembalikan semua kebijakan pembulatan urut metode.
+func (s *PostgresStore) GetAllRoundingPolicies(ctx context.Context) ([]models.RoundingPolicy, error) {
+	policies, err := getRoundingPolicies(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.RoundingPolicy, 0, len(policies))
+	for _, method := range sortedPolicyMethods(policies) {
+		list = append(list, policies[method])
+	}
+	return list, nil
+}
+
+// SetRoundingPolicy menyimpan kebijakan pembulatan untuk satu metode pembayaran,
+// menggantikan kebijakan sebelumnya jika ada.
+func (s *PostgresStore) SetRoundingPolicy(ctx context.Context, p models.RoundingPolicy) (models.RoundingPolicy, error) {
+	if err := validateRoundingPolicy(p); err != nil {
+		return models.RoundingPolicy{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[rounding-store] Error begin transaction: %v", err)
+		return models.RoundingPolicy{}, err
+	}
+	defer tx.Rollback()
+
+	// Kebijakan lama, jika ada, dicatat sebagai data sebelum perubahan.
+	var before any
+	action := models.AuditActionCreate
+	var old models.RoundingPolicy
+	err = tx.QueryRowContext(ctx,
+		"SELECT method, mode, unit, updated_at FROM rounding_policies WHERE method = $1 FOR UPDATE", p.Method,
+	).Scan(&old.Method, &old.Mode, &old.Unit, &old.UpdatedAt)
+	switch {
+	case err == nil:
+		before, action = old, models.AuditActionUpdate
+	case err != sql.ErrNoRows:
+		log.Printf("[rounding-store] Error lock rounding policy: %v", err)
+		return models.RoundingPolicy{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO rounding_policies (method, mode, unit)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (method) DO UPDATE SET mode = EXCLUDED.mode, unit = EXCLUDED.unit, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, p.Method, p.Mode, p.Unit).Scan(&p.UpdatedAt)
+	if err != nil {
+		log.Printf("[rounding-store] Error SetRoundingPolicy: %v", err)
+		return models.RoundingPolicy{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityRoundingPolicy, action, p.Method, before, p); err != nil {
+		return models.RoundingPolicy{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[rounding-store] Error commit SetRoundingPolicy: %v", err)
+		return models.RoundingPolicy{}, err
+	}
+
+	return p, nil
+}
+
+// DeleteRoundingPolicy menghapus kebijakan pembulatan sehingga metode tersebut tidak dibulatkan lagi.
+func (s *PostgresStore) DeleteRoundingPolicy(ctx context.Context, method string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[rounding-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var before models.RoundingPolicy
+	err = tx.QueryRowContext(ctx,
+		"DELETE FROM rounding_policies WHERE method = $1 RETURNING method, mode, unit, updated_at", method,
+	).Scan(&before.Method, &before.Mode, &before.Unit, &before.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: rounding policy for %s", ErrNotFound, method)
+	}
+	if err != nil {
+		log.Printf("[rounding-store] Error DeleteRoundingPolicy: %v", err)
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityRoundingPolicy, models.AuditActionDelete, method, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[rounding-store] Error commit DeleteRoundingPolicy: %v", err)
+		return err
+	}
+	return nil
+}
+
+// getRoundingPolicies mengambil semua kebijakan pembulatan per metode pembayaran.
+func getRoundingPolicies(ctx context.Context, q queryer) (map[string]models.RoundingPolicy, error) {
+	rows, err := q.QueryContext(ctx, "SELECT method, mode, unit, updated_at FROM rounding_policies")
+	if err != nil {
+		log.Printf("[rounding-store] Error get rounding policies: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make(map[string]models.RoundingPolicy)
+	for rows.Next() {
+		var p models.RoundingPolicy
+		if err := rows.Scan(&p.Method, &p.Mode, &p.Unit, &p.UpdatedAt); err != nil {
+			log.Printf("[rounding-store] Error scanning rounding policy row: %v", err)
+			continue
+		}
+		policies[p.Method] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[rounding-store] Error iterating rounding policy rows: %v", err)
+		return nil, err
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// shiftSale adalah ringkasan pembayaran satu transaksi dalam shift.
+type shiftSale struct {
+	TransactionID int
+	Breakdown     []models.PaymentBreakdown
+}
+
+// prepareOpenShift memvalidasi request buka shift lalu merapikan isinya.
+func prepareOpenShift(req models.OpenShiftRequest) (models.OpenShiftRequest, error) {
+	req.Register = strings.TrimSpace(req.Register)
+	req.Cashier = strings.TrimSpace(req.Cashier)
+	if req.Register == "" {
+		return req, fmt.Errorf("%w: register is required", ErrInvalidInput)
+	}
+	if req.Cashier == "" {
+		return req, fmt.Errorf("%w: cashier is required", ErrInvalidInput)
+	}
+	if req.OpeningFloat < 0 {
+		return req, fmt.Errorf("%w: opening_float must not be negative", ErrInvalidInput)
+	}
+	return req, nil
+}
+
+// shiftCounts memvalidasi request tutup shift dan mengembalikan hasil hitung
+// per metode pembayaran, termasuk tunai.
+func shiftCounts(req models.CloseShiftRequest) (map[string]int, error) {
+	if strings.TrimSpace(req.Operator) == "" {
+		return nil, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+	if req.CountedCash < 0 {
+		return nil, fmt.Errorf("%w: counted_cash must not be negative", ErrInvalidInput)
+	}
+
+	counts := map[string]int{models.PaymentMethodCash: req.CountedCash}
+	for _, c := range req.Counts {
+		if !validPaymentMethods[c.Method] {
+			return nil, fmt.Errorf("%w: unknown payment method %q", ErrInvalidInput, c.Method)
+		}
+		if _, ok := counts[c.Method]; ok {
+			return nil, fmt.Errorf("%w: %s is counted more than once (use counted_cash for cash)", ErrInvalidInput, c.Method)
+		}
+		if c.Amount < 0 {
+			return nil, fmt.Errorf("%w: count for %s must not be negative", ErrInvalidInput, c.Method)
+		}
+		counts[c.Method] = c.Amount
+	}
+	return counts, nil
+}
+
+// prepareCashMovement memvalidasi request pay-in/pay-out lalu merapikan isinya.
+func prepareCashMovement(req models.CashMovementRequest) (models.CashMovementRequest, error) {
+	req.Register = strings.TrimSpace(req.Register)
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.Operator = strings.TrimSpace(req.Operator)
+	if req.Type != models.CashMovementPayIn && req.Type != models.CashMovementPayOut {
+		return req, fmt.Errorf("%w: type must be %s or %s", ErrInvalidInput, models.CashMovementPayIn, models.CashMovementPayOut)
+	}
+	if req.Amount <= 0 {
+		return req, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidInput)
+	}
+	if req.Reason == "" {
+		return req, fmt.Errorf("%w: reason is required", ErrInvalidInput)
+	}
+	if req.Operator == "" {
+		return req, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+	return req, nil
+}
+
+// prepareNoSale memvalidasi request buka laci tanpa penjualan lalu merapikan isinya.
+func prepareNoSale(req models.NoSaleRequest) (models.NoSaleRequest, error) {
+	req.Register = strings.TrimSpace(req.Register)
+	req.Reason = strings.TrimSpace(req.Reason)
+	req.Operator = strings.TrimSpace(req.Operator)
+	if req.Reason == "" {
+		return req, fmt.Errorf("%w: reason is required", ErrInvalidInput)
+	}
+	if req.Operator == "" {
+		return req, fmt.Errorf("%w: operator is required", ErrInvalidInput)
+	}
+	return req, nil
+}
+
+// validateRefundRegister memastikan refund menyebut register yang mengeluarkan uangnya.
+func validateRefundRegister(register string) error {
+	if strings.TrimSpace(register) == "" {
+		return fmt.Errorf("%w: register is required", ErrInvalidReversal)
+	}
+	return nil
+}
+
+// shiftReport menyusun rekonsiliasi per metode pembayaran. Void mengembalikan
+// uang dengan metode yang sama seperti pembayaran aslinya, sedangkan refund
+// dibayar tunai dari laci kecuali bagian yang dikembalikan sebagai poin.
+// Pay-in/pay-out hanya mengubah baris tunai. Baris tunai selalu ada dan tampil
+// lebih dulu; counts berisi hasil hitung saat tutup shift (nil untuk shift yang
+// masih terbuka).
+func shiftReport(openingFloat int, sales []shiftSale, reversals []models.Reversal, movements []models.CashMovement,
+	counts map[string]int) []models.ShiftReportLine {
+	lines := map[string]*models.ShiftReportLine{
+		models.PaymentMethodCash: {Method: models.PaymentMethodCash, Opening: openingFloat},
+	}
+	line := func(method string) *models.ShiftReportLine {
+		if l, ok := lines[method]; ok {
+			return l
+		}
+		lines[method] = &models.ShiftReportLine{Method: method}
+		return lines[method]
+	}
+
+	breakdowns := make(map[int][]models.PaymentBreakdown, len(sales))
+	for _, sale := range sales {
+		breakdowns[sale.TransactionID] = sale.Breakdown
+		for _, b := range sale.Breakdown {
+			line(b.Method).Sales += b.Amount
+		}
+	}
+
+	for _, r := range reversals {
+		breakdown, ok := breakdowns[r.TransactionID]
+		if r.Type != models.ReversalTypeVoid || !ok {
+			line(models.PaymentMethodCash).Refunds += r.Amount - r.PointsAmount
+			if r.PointsAmount > 0 {
+				line(models.PaymentMethodPoints).Refunds += r.PointsAmount
+			}
+			continue
+		}
+		for _, b := range breakdown {
+			line(b.Method).Refunds += b.Amount
+		}
+	}
+
+	cash := line(models.PaymentMethodCash)
+	for _, m := range movements {
+		if m.Type == models.CashMovementPayIn {
+			cash.PayIns += m.Amount
+		} else {
+			cash.PayOuts += m.Amount
+		}
+	}
+
+	for method := range counts {
+		line(method)
+	}
+
+	methods := make([]string, 0, len(lines))
+	for method := range lines {
+		if method != models.PaymentMethodCash {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	methods = append([]string{models.PaymentMethodCash}, methods...)
+
+	report := make([]models.ShiftReportLine, 0, len(methods))
+	for _, method := range methods {
+		l := *lines[method]
+		l.Expected = l.Opening + l.Sales - l.Refunds + l.PayIns - l.PayOuts
+		if counted, ok := counts[method]; ok {
+			difference := counted - l.Expected
+			l.Counted, l.Difference = &counted, &difference
+		}
+		report = append(report, l)
+	}
+	return report
+}
+
+// applyShiftReport mengisi laporan shift beserta ringkasan tunainya. Untuk shift
+// yang masih terbuka, ExpectedCash mengikuti kondisi saat ini.
+func applyShiftReport(shift *models.Shift, report []models.ShiftReportLine) {
+	shift.Report = report
+	for _, l := range report {
+		if l.Method != models.PaymentMethodCash {
+			continue
+		}
+		shift.ExpectedCash = l.Expected
+		if l.Counted != nil {
+			shift.CountedCash, shift.CashDifference = *l.Counted, *l.Difference
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// shiftColumns adalah kolom shifts sesuai urutan scanShift.
+const shiftColumns = "id, register, cashier, status, opening_float, expected_cash, counted_cash, note, opened_at, closed_by, closed_at"
+
+// OpenShift membuka shift baru pada register. Satu register hanya boleh punya satu shift terbuka.
+func (s *PostgresStore) OpenShift(ctx context.Context, req models.OpenShiftRequest) (*models.Shift, error) {
+	req, err := prepareOpenShift(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = s.db.QueryRowContext(ctx,
+		"INSERT INTO shifts (register, cashier, opening_float) VALUES ($1, $2, $3) RETURNING id",
+		req.Register, req.Cashier, req.OpeningFloat).Scan(&id)
+	if isUniqueViolation(err) {
+		return nil, fmt.Errorf("%w: register %q already has an open shift", ErrConflict, req.Register)
+	}
+	if err != nil {
+		log.Printf("[shift-store] Error insert shift: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[shift-store] Shift opened id=%d register=%s cashier=%s", id, req.Register, req.Cashier)
+	return getShift(ctx, s.db, id)
+}
+
+// GetAllShifts mengembalikan shift sesuai filter tanpa laporan, terbaru lebih dulu.
+func (s *PostgresStore) GetAllShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error) {
+	query := "SELECT " + shiftColumns + " FROM shifts"
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Register != "" {
+		args = append(args, filter.Register)
+		conditions = append(conditions, fmt.Sprintf("register = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("[shift-store] Error GetAllShifts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []models.Shift{}
+	for rows.Next() {
+		shift, err := scanShift(rows)
+		if err != nil {
+			log.Printf("[shift-store] Error scanning shift row: %v", err)
+			continue
+		}
+		shifts = append(shifts, shift)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating shift rows: %v", err)
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+// GetShift mengembalikan satu shift beserta rekonsiliasi per metode pembayaran.
+func (s *PostgresStore) GetShift(ctx context.Context, id int) (*models.Shift, error) {
+	return getShift(ctx, s.db, id)
+}
+
+// CloseShift menutup shift dengan hasil hitung laci, lalu membekukan uang tunai
+// yang seharusnya ada. Baris shift dikunci FOR UPDATE sehingga penutupan
+// menunggu checkout yang sedang berjalan pada shift ini selesai.
+func (s *PostgresStore) CloseShift(ctx context.Context, id int, req models.CloseShiftRequest) (*models.Shift, error) {
+	counts, err := shiftCounts(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[shift-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM shifts WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: shift id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[shift-store] Error lock shift: %v", err)
+		return nil, err
+	}
+	if status != models.ShiftStatusOpen {
+		return nil, fmt.Errorf("%w: shift %d is already %s", ErrConflict, id, status)
+	}
+
+	for method, amount := range counts {
+		_, err := tx.ExecContext(ctx, "INSERT INTO shift_counts (shift_id, method, amount) VALUES ($1, $2, $3)",
+			id, method, amount)
+		if err != nil {
+			log.Printf("[shift-store] Error insert shift count: %v", err)
+			return nil, err
+		}
+	}
+
+	shift, err := getShift(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	closedAt := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE shifts SET status = $1, expected_cash = $2, counted_cash = $3, note = $4, closed_by = $5, closed_at = $6
+		WHERE id = $7
+	`, models.ShiftStatusClosed, shift.ExpectedCash, shift.CountedCash, req.Note, req.Operator, closedAt, id)
+	if err != nil {
+		log.Printf("[shift-store] Error close shift: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[shift-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	shift.Status, shift.Note, shift.ClosedBy, shift.ClosedAt = models.ShiftStatusClosed, req.Note, req.Operator, &closedAt
+	log.Printf("[shift-store] Shift closed id=%d expected_cash=%d counted_cash=%d difference=%d",
+		id, shift.ExpectedCash, shift.CountedCash, shift.CashDifference)
+	return shift, nil
+}
+
+// RecordCashMovement mencatat pay-in/pay-out pada shift yang terbuka di register.
+// Shift dikunci FOR SHARE seperti checkout agar tidak ditutup di tengah pencatatan.
+func (s *PostgresStore) RecordCashMovement(ctx context.Context, req models.CashMovementRequest) (*models.CashMovement, error) {
+	req, err := prepareCashMovement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[shift-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shiftID, err := lockOpenShift(ctx, tx, req.Register)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.CashMovement{ShiftID: shiftID, Type: req.Type, Amount: req.Amount, Reason: req.Reason, Operator: req.Operator}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO shift_cash_movements (shift_id, type, amount, reason, operator)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at
+	`, m.ShiftID, m.Type, m.Amount, m.Reason, m.Operator).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		log.Printf("[shift-store] Error insert cash movement: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[shift-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[shift-store] Cash movement recorded id=%d shift_id=%d type=%s amount=%d", m.ID, shiftID, m.Type, m.Amount)
+	return &m, nil
+}
+
+// RecordNoSale mencatat laci yang dibuka tanpa penjualan pada shift yang
+// terbuka di register. Aksi ini butuh persetujuan manajer yang dicatat bersama
+// catatannya.
+func (s *PostgresStore) RecordNoSale(ctx context.Context, req models.NoSaleRequest) (*models.NoSale, error) {
+	req, err := prepareNoSale(req)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := requireApproval(ctx, models.ApprovalActionNoSale, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[shift-store] Error begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shiftID, err := lockOpenShift(ctx, tx, req.Register)
+	if err != nil {
+		return nil, err
+	}
+
+	n := models.NoSale{ShiftID: shiftID, Reason: req.Reason, Operator: req.Operator}
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO shift_no_sales (shift_id, reason, operator) VALUES ($1, $2, $3) RETURNING id, created_at",
+		n.ShiftID, n.Reason, n.Operator).Scan(&n.ID, &n.CreatedAt)
+	if err != nil {
+		log.Printf("[shift-store] Error insert no sale: %v", err)
+		return nil, err
+	}
+	if err := insertApproval(ctx, tx, approval, n.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[shift-store] Error commit transaction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[shift-store] No sale recorded id=%d shift_id=%d operator=%s", n.ID, shiftID, n.Operator)
+	return &n, nil
+}
+
+// getShift mengambil satu shift lalu menyusun laporannya dari transaksi,
+// void/refund, pay-in/pay-out, dan hasil hitung yang tercatat pada shift tersebut.
+func getShift(ctx context.Context, q queryer, id int) (*models.Shift, error) {
+	shift, err := scanShift(q.QueryRowContext(ctx, "SELECT "+shiftColumns+" FROM shifts WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: shift id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[shift-store] Error get shift: %v", err)
+		return nil, err
+	}
+
+	sales, err := getShiftSales(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Void/refund yang uangnya keluar dari laci shift ini.
+	rows, err := q.QueryContext(ctx,
+		"SELECT transaction_id, type, amount, points_amount FROM transaction_reversals WHERE shift_id = $1 ORDER BY id", id)
+	if err != nil {
+		log.Printf("[shift-store] Error get shift reversals: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reversals []models.Reversal
+	for rows.Next() {
+		var r models.Reversal
+		if err := rows.Scan(&r.TransactionID, &r.Type, &r.Amount, &r.PointsAmount); err != nil {
+			log.Printf("[shift-store] Error scanning shift reversal row: %v", err)
+			return nil, err
+		}
+		reversals = append(reversals, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating shift reversal rows: %v", err)
+		return nil, err
+	}
+	rows.Close()
+
+	movements, err := getShiftCashMovements(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	shift.CashMovements = movements
+
+	if shift.NoSales, err = getShiftNoSales(ctx, q, id); err != nil {
+		return nil, err
+	}
+
+	counts, err := getShiftCounts(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyShiftReport(&shift, shiftReport(shift.OpeningFloat, sales, reversals, movements, counts))
+	return &shift, nil
+}
+
+// getShiftSales mengambil ringkasan pembayaran setiap transaksi dalam shift.
+func getShiftSales(ctx context.Context, q queryer, shiftID int) ([]shiftSale, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT t.id, t.change_amount, tp.method, tp.amount
+		FROM transactions t
+		JOIN transaction_payments tp ON tp.transaction_id = t.id
+		WHERE t.shift_id = $1
+		ORDER BY t.id, tp.id
+	`, shiftID)
+	if err != nil {
+		log.Printf("[shift-store] Error get shift sales: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []shiftSale
+	var payments []models.TransactionPayment
+	currentID, currentChange := 0, 0
+	flush := func() {
+		if currentID != 0 {
+			sales = append(sales, shiftSale{TransactionID: currentID, Breakdown: paymentBreakdown(payments, currentChange)})
+		}
+	}
+	for rows.Next() {
+		var id, change int
+		var p models.TransactionPayment
+		if err := rows.Scan(&id, &change, &p.Method, &p.Amount); err != nil {
+			log.Printf("[shift-store] Error scanning shift sale row: %v", err)
+			return nil, err
+		}
+		if id != currentID {
+			flush()
+			currentID, currentChange, payments = id, change, nil
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating shift sale rows: %v", err)
+		return nil, err
+	}
+	flush()
+
+	return sales, nil
+}
+
+// getShiftCashMovements mengambil pay-in/pay-out dalam shift urut waktu pencatatan.
+func getShiftCashMovements(ctx context.Context, q queryer, shiftID int) ([]models.CashMovement, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, shift_id, type, amount, reason, operator, created_at
+		FROM shift_cash_movements WHERE shift_id = $1 ORDER BY id
+	`, shiftID)
+	if err != nil {
+		log.Printf("[shift-store] Error get cash movements: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []models.CashMovement
+	for rows.Next() {
+		var m models.CashMovement
+		if err := rows.Scan(&m.ID, &m.ShiftID, &m.Type, &m.Amount, &m.Reason, &m.Operator, &m.CreatedAt); err != nil {
+			log.Printf("[shift-store] Error scanning cash movement row: %v", err)
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating cash movement rows: %v", err)
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// getShiftNoSales mengambil catatan laci dibuka tanpa penjualan selama shift.
+func getShiftNoSales(ctx context.Context, q queryer, shiftID int) ([]models.NoSale, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT id, shift_id, reason, operator, created_at FROM shift_no_sales WHERE shift_id = $1 ORDER BY id", shiftID)
+	if err != nil {
+		log.Printf("[shift-store] Error get no sales: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var noSales []models.NoSale
+	for rows.Next() {
+		var n models.NoSale
+		if err := rows.Scan(&n.ID, &n.ShiftID, &n.Reason, &n.Operator, &n.CreatedAt); err != nil {
+			log.Printf("[shift-store] Error scanning no sale row: %v", err)
+			return nil, err
+		}
+		noSales = append(noSales, n)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating no sale rows: %v", err)
+		return nil, err
+	}
+
+	return noSales, nil
+}
+
+// getShiftCounts mengambil hasil hitung per metode pembayaran saat shift ditutup.
+func getShiftCounts(ctx context.Context, q queryer, shiftID int) (map[string]int, error) {
+	rows, err := q.QueryContext(ctx, "SELECT method, amount FROM shift_counts WHERE shift_id = $1", shiftID)
+	if err != nil {
+		log.Printf("[shift-store] Error get shift counts: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var method string
+		var amount int
+		if err := rows.Scan(&method, &amount); err != nil {
+			log.Printf("[shift-store] Error scanning shift count row: %v", err)
+			return nil, err
+		}
+		counts[method] = amount
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("[shift-store] Error iterating shift count rows: %v", err)
+		return nil, err
+	}
+
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	return counts, nil
+}
+
+// lockOpenShift mencari shift yang terbuka pada register dan menguncinya FOR
+// SHARE, sehingga shift tidak bisa ditutup sampai database transaction
+// pemanggil selesai. Register tanpa shift terbuka menghasilkan ErrConflict.
+func lockOpenShift(ctx context.Context, tx *sql.Tx, register string) (int, error) {
+	register = strings.TrimSpace(register)
+	if register == "" {
+		return 0, fmt.Errorf("%w: register is required", ErrInvalidInput)
+	}
+
+	var id int
+	err := tx.QueryRowContext(ctx,
+		"SELECT id FROM shifts WHERE register = $1 AND status = $2 FOR SHARE",
+		register, models.ShiftStatusOpen).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: no open shift on register %q", ErrConflict, register)
+	}
+	if err != nil {
+		log.Printf("[shift-store] Error lock open shift: %v", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+// lockShiftForReversal mengunci shift asal transaksi FOR SHARE dan memastikan
+// shift itu masih terbuka sebelum void mengeluarkan uang dari lacinya.
+func lockShiftForReversal(ctx context.Context, tx *sql.Tx, shiftID int) error {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM shifts WHERE id = $1 FOR SHARE", shiftID).Scan(&status)
+	if err != nil {
+		log.Printf("[shift-store] Error lock shift: %v", err)
+		return err
+	}
+	if status != models.ShiftStatusOpen {
+		return fmt.Errorf("%w: shift %d is closed", ErrReversalNotAllowed, shiftID)
+	}
+	return nil
+}
+
+// scanShift membaca satu baris shift.
+func scanShift(row rowScanner) (models.Shift, error) {
+	var shift models.Shift
+	var closedAt sql.NullTime
+	err := row.Scan(&shift.ID, &shift.Register, &shift.Cashier, &shift.Status, &shift.OpeningFloat,
+		&shift.ExpectedCash, &shift.CountedCash, &shift.Note, &shift.OpenedAt, &shift.ClosedBy, &closedAt)
+	if err != nil {
+		return shift, err
+	}
+	if closedAt.Valid {
+		shift.ClosedAt = &closedAt.Time
+	}
+	shift.CashDifference = shift.CountedCash - shift.ExpectedCash
+	return shift, nil
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestShiftReportCashDifference(t *testing.T) {
+	// Laci dibuka 100.000, penjualan tunai 50.000 dan QRIS 30.000, refund tunai
+	// 5.000, pay-in 20.000, pay-out 10.000: tunai yang diharapkan 155.000.
+	sales := []shiftSale{
+		{TransactionID: 1, Breakdown: []models.PaymentBreakdown{{Method: models.PaymentMethodCash, Amount: 50000, Tendered: 60000}}},
+		{TransactionID: 2, Breakdown: []models.PaymentBreakdown{{Method: models.PaymentMethodQRIS, Amount: 30000, Tendered: 30000}}},
+	}
+	reversals := []models.Reversal{{TransactionID: 1, Type: models.ReversalTypeRefund, Amount: 5000}}
+	movements := []models.CashMovement{
+		{Type: models.CashMovementPayIn, Amount: 20000},
+		{Type: models.CashMovementPayOut, Amount: 10000},
+	}
+	const expected = 155000
+
+	tests := []struct {
+		name           string
+		counts         map[string]int
+		wantCounted    int
+		wantDifference int
+	}{
+		{"exact", map[string]int{models.PaymentMethodCash: 155000}, 155000, 0},
+		{"short", map[string]int{models.PaymentMethodCash: 150000}, 150000, -5000},
+		{"over", map[string]int{models.PaymentMethodCash: 157500}, 157500, 2500},
+		{"open shift", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shift models.Shift
+			applyShiftReport(&shift, shiftReport(100000, sales, reversals, movements, tt.counts))
+
+			if shift.ExpectedCash != expected {
+				t.Errorf("expected cash = %d, want %d", shift.ExpectedCash, expected)
+			}
+			if shift.CountedCash != tt.wantCounted || shift.CashDifference != tt.wantDifference {
+				t.Errorf("counted/difference = %d/%d, want %d/%d",
+					shift.CountedCash, shift.CashDifference, tt.wantCounted, tt.wantDifference)
+			}
+			if cash := shift.Report[0]; tt.counts == nil && (cash.Counted != nil || cash.Difference != nil) {
+				t.Errorf("open shift cash line counted/difference = %v/%v, want nil", cash.Counted, cash.Difference)
+			}
+			for _, l := range shift.Report {
+				if l.Method == models.PaymentMethodQRIS && (l.Expected != 30000 || l.Counted != nil) {
+					t.Errorf("qris expected/counted = %d/%v, want 30000/nil", l.Expected, l.Counted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"kasir-api/models"
+)
+
+// testDatabaseEnv berisi connection string PostgreSQL untuk test yang butuh
+// database sungguhan. Test dilewati jika kosong. Setiap test membuat schema
+// sendiri, menjalankan semua migrasi di dalamnya, lalu menghapusnya.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// openTestDB membuka PostgreSQL dari TEST_DATABASE_URL dengan schema baru yang
+// sudah dimigrasi.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	schema := "kasir_test_" + strings.ToLower(rand.Text()[:12])
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer admin.Close()
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Errorf("open database for cleanup: %v", err)
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("open test schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(25)
+
+	files, err := filepath.Glob(filepath.Join("..", "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatalf("list migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		query, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read migration: %v", err)
+		}
+		if _, err := db.Exec(string(query)); err != nil {
+			t.Fatalf("migrate %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// withSearchPath menambah parameter search_path ke connection string dalam
+// format URL maupun key=value.
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + "search_path=" + schema
+	}
+	return dsn + " search_path=" + schema
+}
+
+// checkoutOne menjalankan checkout tunai untuk items di register R1.
+func checkoutOne(ctx context.Context, s *PostgresStore, items ...models.CheckoutItem) error {
+	_, err := s.CreateTransaction(ctx, models.CheckoutRequest{
+		Register: "R1",
+		Items:    items,
+		Payments: []models.CheckoutPayment{{Method: "cash", Amount: 1000000}},
+	})
+	return err
+}
+
+func TestCheckoutLastUnitsConcurrently(t *testing.T) {
+	db := openTestDB(t)
+	s := NewPostgresStore(db)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const initial, buyers = 5, 20
+	p, err := s.Add(ctx, models.Produk{Nama: "Kopi Susu", Harga: 15000, HargaBeli: 9000, Stok: initial})
+	if err != nil {
+		t.Fatalf("add produk: %v", err)
+	}
+	if _, err := s.OpenShift(ctx, models.OpenShiftRequest{Register: "R1", Cashier: "ani", OpeningFloat: 100000}); err != nil {
+		t.Fatalf("open shift: %v", err)
+	}
+
+	errs := make([]error, buyers)
+	var wg sync.WaitGroup
+	for i := range buyers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = checkoutOne(ctx, s, models.CheckoutItem{ProductID: p.ID, Quantity: 1})
+		}()
+	}
+	wg.Wait()
+
+	sold := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			sold++
+		case !errors.Is(err, ErrInsufficientStock):
+			t.Errorf("buyer %d err = %v, want nil or ErrInsufficientStock", i, err)
+		default:
+			// Yang kalah, baik di cek stok maupun saat stok dikurangi, tetap
+			// mendapat error per baris.
+			var itemsErr *CheckoutItemsError
+			if !errors.As(err, &itemsErr) {
+				t.Errorf("buyer %d err = %T, want *CheckoutItemsError", i, err)
+				continue
+			}
+			want := []models.CheckoutLineError{{Line: 1, ProductID: p.ID, Field: "quantity"}}
+			got := make([]models.CheckoutLineError, len(itemsErr.Lines))
+			for j, l := range itemsErr.Lines {
+				got[j] = models.CheckoutLineError{Line: l.Line, ProductID: l.ProductID, Field: l.Field}
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("buyer %d lines = %+v, want %+v", i, itemsErr.Lines, want)
+			}
+		}
+	}
+	if sold != initial {
+		t.Errorf("sold = %d, want %d", sold, initial)
+	}
+
+	var stok, detailQuantity int
+	if err := db.QueryRow("SELECT stok FROM produk WHERE id = $1", p.ID).Scan(&stok); err != nil {
+		t.Fatalf("get stok: %v", err)
+	}
+	if stok != 0 {
+		t.Errorf("stok = %d, want 0", stok)
+	}
+	err = db.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM transaction_details WHERE product_id = $1", p.ID).
+		Scan(&detailQuantity)
+	if err != nil {
+		t.Fatalf("sum sold quantity: %v", err)
+	}
+	if detailQuantity != initial {
+		t.Errorf("sold quantity in transaction_details = %d, want %d", detailQuantity, initial)
+	}
+}
+
+func TestCheckoutOppositeOrderNoDeadlock(t *testing.T) {
+	db := openTestDB(t)
+	s := NewPostgresStore(db)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const workers, rounds = 10, 10
+	var ids [2]int
+	for i, nama := range []string{"Kopi Susu", "Roti Bakar"} {
+		p, err := s.Add(ctx, models.Produk{Nama: nama, Harga: 15000, HargaBeli: 9000, Stok: workers * rounds})
+		if err != nil {
+			t.Fatalf("add produk: %v", err)
+		}
+		ids[i] = p.ID
+	}
+	if _, err := s.OpenShift(ctx, models.OpenShiftRequest{Register: "R1", Cashier: "ani", OpeningFloat: 100000}); err != nil {
+		t.Fatalf("open shift: %v", err)
+	}
+
+	// Separuh worker mengirim keranjang A,B dan separuh lagi B,A.
+	errs := make(chan error, workers*rounds)
+	var wg sync.WaitGroup
+	for w := range workers {
+		first, second := ids[w%2], ids[1-w%2]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				err := checkoutOne(ctx, s,
+					models.CheckoutItem{ProductID: first, Quantity: 1},
+					models.CheckoutItem{ProductID: second, Quantity: 1})
+				if err != nil {
+					errs <- fmt.Errorf("cart %d,%d: %w", first, second, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, id := range ids {
+		var stok int
+		if err := db.QueryRow("SELECT stok FROM produk WHERE id = $1", id).Scan(&stok); err != nil {
+			t.Fatalf("get stok: %v", err)
+		}
+		if stok != 0 {
+			t.Errorf("stok produk %d = %d, want 0", id, stok)
+		}
+	}
+}
+
+func TestOpnameConcurrentSales(t *testing.T) {
+	db := openTestDB(t)
+	s := NewPostgresStore(db)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const initial, workers, rounds, counted = 1000, 8, 25, 900
+	p, err := s.Add(ctx, models.Produk{Nama: "Kopi Susu", Harga: 15000, HargaBeli: 9000, Stok: initial})
+	if err != nil {
+		t.Fatalf("add produk: %v", err)
+	}
+	if _, err := s.OpenShift(ctx, models.OpenShiftRequest{Register: "R1", Cashier: "ani", OpeningFloat: 100000}); err != nil {
+		t.Fatalf("open shift: %v", err)
+	}
+
+	// Penjualan berjalan terus selama sesi dibuka dan barang dihitung.
+	errs := make(chan error, workers*rounds)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				if err := checkoutOne(ctx, s, models.CheckoutItem{ProductID: p.ID, Quantity: 1}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	o, err := s.OpenOpname(ctx, models.OpenOpnameRequest{Operator: "budi"})
+	if err != nil {
+		t.Fatalf("open opname: %v", err)
+	}
+	for range 5 {
+		_, err := s.RecordOpnameCounts(ctx, o.ID, models.OpnameCountRequest{
+			Counter: "ani",
+			Items:   []models.OpnameCountItem{{ProductID: p.ID, Quantity: counted}},
+		})
+		if err != nil {
+			t.Fatalf("record counts: %v", err)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("checkout: %v", err)
+	}
+
+	// stokAt mengembalikan stok sesudah pergerakan ledger id, atau stok awal
+	// jika id 0.
+	stokAt := func(id int) int {
+		t.Helper()
+		if id == 0 {
+			return initial
+		}
+		var stok int
+		if err := db.QueryRow("SELECT stok_after FROM stock_movements WHERE id = $1", id).Scan(&stok); err != nil {
+			t.Fatalf("get stok_after of movement %d: %v", id, err)
+		}
+		return stok
+	}
+
+	var snapshotStok, snapshotPosition, countPosition int
+	err = db.QueryRow(`
+		SELECT i.snapshot_stok, i.snapshot_movement_id, c.last_movement_id
+		FROM stock_opname_items i
+		JOIN stock_opname_counts c ON c.item_id = i.id
+		WHERE i.opname_id = $1 AND i.product_id = $2
+	`, o.ID, p.ID).Scan(&snapshotStok, &snapshotPosition, &countPosition)
+	if err != nil {
+		t.Fatalf("get opname positions: %v", err)
+	}
+	if want := stokAt(snapshotPosition); snapshotStok != want {
+		t.Errorf("snapshot_stok = %d, want stok_after of movement %d = %d", snapshotStok, snapshotPosition, want)
+	}
+
+	got, err := s.GetOpname(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("get opname: %v", err)
+	}
+	if len(got.Lines) != 1 {
+		t.Fatalf("lines = %d, want 1", len(got.Lines))
+	}
+	line := got.Lines[0]
+	if want := initial - workers*rounds; line.SystemStok != want {
+		t.Errorf("system_stok = %d, want %d", line.SystemStok, want)
+	}
+	if want := line.SnapshotStok - line.SoldDuringSession; line.SystemStok != want {
+		t.Errorf("system_stok = %d, want snapshot_stok - sold_during_session = %d", line.SystemStok, want)
+	}
+	if want := counted - stokAt(countPosition); line.Variance != want {
+		t.Errorf("variance = %d, want counted - stok at count position = %d", line.Variance, want)
+	}
+
+	final, err := s.FinalizeOpname(ctx, o.ID, models.CloseOpnameRequest{Operator: "budi"})
+	if err != nil {
+		t.Fatalf("finalize opname: %v", err)
+	}
+	var stok int
+	if err := db.QueryRow("SELECT stok FROM produk WHERE id = $1", p.ID).Scan(&stok); err != nil {
+		t.Fatalf("get stok: %v", err)
+	}
+	if want := final.Lines[0].AdjustedCount; stok != want {
+		t.Errorf("stok after finalize = %d, want adjusted count %d", stok, want)
+	}
+}
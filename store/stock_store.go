@@ -0,0 +1,110 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+
+	"kasir-api/models"
+)
+
+// adjustStock mengubah stok produk sebesar m.Delta dan mencatatnya di ledger
+// stock_movements. Semua perubahan stok di package store harus lewat fungsi ini
+// dan dipanggil di dalam database transaction yang sama dengan dokumen sumbernya.
+// Operator yang kosong diisi pelaku dari context (lihat WithActor).
+func adjustStock(ctx context.Context, q queryer, m models.StockMovement) (models.StockMovement, error) {
+	if m.Delta == 0 {
+		return m, nil
+	}
+	if m.Operator == "" {
+		m.Operator = auditSourceFromContext(ctx).actor
+	}
+
+	// Penjualan hanya boleh mengambil stok yang masih ada. Syarat di WHERE dicek
+	// ulang pada versi baris terbaru setelah lock didapat, jadi dua checkout
+	// bersamaan tidak bisa sama-sama menjual unit terakhir.
+	query := "UPDATE produk SET stok = stok + $1 WHERE id = $2 RETURNING stok, nama"
+	if m.Reason == models.StockReasonSale {
+		query = "UPDATE produk SET stok = stok + $1 WHERE id = $2 AND stok + $1 >= 0 RETURNING stok, nama"
+	}
+	err := q.QueryRowContext(ctx, query, m.Delta, m.ProductID).Scan(&m.StokAfter, &m.ProductName)
+	if err == sql.ErrNoRows && m.Reason == models.StockReasonSale {
+		log.Printf("[stock-store] Sale rejected product_id=%d delta=%d: not found or insufficient stock", m.ProductID, m.Delta)
+		return models.StockMovement{}, fmt.Errorf("%w: product id %d not found or sold out (requested: %d)",
+			ErrInsufficientStock, m.ProductID, -m.Delta)
+	}
+	if err == sql.ErrNoRows {
+		return models.StockMovement{}, fmt.Errorf("%w: product id %d", ErrNotFound, m.ProductID)
+	}
+	if err != nil {
+		log.Printf("[stock-store] Error update stock product_id=%d: %v", m.ProductID, err)
+		return models.StockMovement{}, err
+	}
+
+	err = q.QueryRowContext(ctx,
+		`INSERT INTO stock_movements (product_id, product_name, delta, stok_after, reason, reference_id, operator, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`,
+		m.ProductID, m.ProductName, m.Delta, m.StokAfter, m.Reason, m.ReferenceID, m.Operator, m.Note,
+	).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		log.Printf("[stock-store] Error insert stock movement product_id=%d: %v", m.ProductID, err)
+		return models.StockMovement{}, err
+	}
+
+	return m, nil
+}
+
+// stockOrder mengembalikan indeks 0..n-1 yang diurutkan menurut ID produk.
+// Perubahan stok beberapa produk dalam satu database transaction dijalankan
+// dengan urutan ini agar lock baris produk selalu diambil dengan urutan yang
+// sama dan dua keranjang berisi produk yang sama tidak saling deadlock.
+func stockOrder(n int, productID func(i int) int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(productID(a), productID(b))
+	})
+	return order
+}
+
+// GetStockHistory mengembalikan riwayat pergerakan stok satu produk, terbaru lebih dulu.
+func (s *PostgresStore) GetStockHistory(ctx context.Context, productID int) ([]models.StockMovement, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, product_id, product_name, delta, stok_after, reason, reference_id, operator, note, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY id DESC
+	`, productID)
+	if err != nil {
+		log.Printf("[stock-store] Error GetStockHistory: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []models.StockMovement{}
+	for rows.Next() {
+		var m models.StockMovement
+		var referenceID sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.ProductName, &m.Delta, &m.StokAfter, &m.Reason, &referenceID, &m.Operator, &m.Note, &m.CreatedAt); err != nil {
+			log.Printf("[stock-store] Error scanning stock movement row: %v", err)
+			continue
+		}
+		if referenceID.Valid {
+			id := int(referenceID.Int64)
+			m.ReferenceID = &id
+		}
+		movements = append(movements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[stock-store] Error iterating stock movement rows: %v", err)
+		return nil, err
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,251 @@
+// Package store menyimpan akses data untuk aplikasi kasir.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"kasir-api/models"
+)
+
+// ProdukStore mendefinisikan operasi penyimpanan untuk produk.
+type ProdukStore interface {
+	GetAll(ctx context.Context, nameFilter string) []models.Produk
+	GetByID(ctx context.Context, id int) (models.Produk, bool)
+	Add(ctx context.Context, p models.Produk) (models.Produk, error)
+	Update(ctx context.Context, id int, p models.Produk) (models.Produk, error)
+	Delete(ctx context.Context, id int) error
+	GetStockHistory(ctx context.Context, productID int) ([]models.StockMovement, error)
+}
+
+// KategoriStore mendefinisikan operasi penyimpanan untuk kategori.
+type KategoriStore interface {
+	GetAllKategori(ctx context.Context) []models.Kategori
+	GetKategoriByID(ctx context.Context, id int) (models.Kategori, bool)
+	AddKategori(ctx context.Context, k models.Kategori) (models.Kategori, error)
+	UpdateKategori(ctx context.Context, id int, updated models.Kategori) bool
+	DeleteKategori(ctx context.Context, id int) bool
+}
+
+// TransactionStore mendefinisikan operasi penyimpanan untuk transaksi.
+type TransactionStore interface {
+	CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error)
+	ClaimCheckoutKey(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error)
+	ReleaseCheckoutKey(ctx context.Context, req models.CheckoutRequest) error
+	GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error)
+	GetAllTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error)
+	VoidTransaction(ctx context.Context, id int, req models.VoidRequest) (*models.Reversal, error)
+	RefundTransaction(ctx context.Context, id int, req models.RefundRequest) (*models.Reversal, error)
+}
+
+// ReportStore mendefinisikan query laporan penjualan, laba kotor, dan pajak.
+type ReportStore interface {
+	GetSalesSummary(ctx context.Context, start, end time.Time) (models.SalesSummary, error)
+	GetMarginReport(ctx context.Context, start, end time.Time, groupBy string) (models.MarginReport, error)
+	GetTaxReport(ctx context.Context, start, end time.Time) (models.TaxReport, error)
+}
+
+// PromotionStore mendefinisikan operasi penyimpanan untuk aturan promosi.
+type PromotionStore interface {
+	AddPromotion(ctx context.Context, p models.Promotion) (models.Promotion, error)
+	GetAllPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotionByID(ctx context.Context, id int) (models.Promotion, error)
+	UpdatePromotion(ctx context.Context, id int, p models.Promotion) (models.Promotion, error)
+	DeletePromotion(ctx context.Context, id int) error
+}
+
+// VoucherStore mendefinisikan operasi penyimpanan untuk kode voucher.
+type VoucherStore interface {
+	AddVoucher(ctx context.Context, v models.Voucher) (models.Voucher, error)
+	GetAllVouchers(ctx context.Context) ([]models.Voucher, error)
+	GetVoucherByID(ctx context.Context, id int) (models.Voucher, error)
+	UpdateVoucher(ctx context.Context, id int, v models.Voucher) (models.Voucher, error)
+}
+
+// TaxStore mendefinisikan operasi penyimpanan untuk aturan pajak dan service charge.
+type TaxStore interface {
+	AddTaxRule(ctx context.Context, r models.TaxRule) (models.TaxRule, error)
+	GetAllTaxRules(ctx context.Context) ([]models.TaxRule, error)
+	GetTaxRuleByID(ctx context.Context, id int) (models.TaxRule, error)
+	UpdateTaxRule(ctx context.Context, id int, r models.TaxRule) (models.TaxRule, error)
+	DeleteTaxRule(ctx context.Context, id int) error
+}
+
+// RoundingStore mendefinisikan operasi penyimpanan untuk kebijakan pembulatan per metode pembayaran.
+type RoundingStore interface {
+	GetAllRoundingPolicies(ctx context.Context) ([]models.RoundingPolicy, error)
+	SetRoundingPolicy(ctx context.Context, p models.RoundingPolicy) (models.RoundingPolicy, error)
+	DeleteRoundingPolicy(ctx context.Context, method string) error
+}
+
+// CustomerStore mendefinisikan operasi penyimpanan untuk pelanggan.
+type CustomerStore interface {
+	AddCustomer(ctx context.Context, c models.Customer) (models.Customer, error)
+	GetAllCustomers(ctx context.Context, phone string) ([]models.Customer, error)
+	GetCustomerByID(ctx context.Context, id int) (models.Customer, error)
+	UpdateCustomer(ctx context.Context, id int, c models.Customer) (models.Customer, error)
+	DeleteCustomer(ctx context.Context, id int) error
+	GetCustomerTransactions(ctx context.Context, id int) ([]models.Transaction, error)
+}
+
+// LoyaltyStore mendefinisikan operasi pengaturan program poin dan ledger poin pelanggan.
+type LoyaltyStore interface {
+	GetLoyaltySettings(ctx context.Context) (models.LoyaltySettings, error)
+	UpdateLoyaltySettings(ctx context.Context, ls models.LoyaltySettings) (models.LoyaltySettings, error)
+	GetAllLoyaltyMultipliers(ctx context.Context) ([]models.LoyaltyMultiplier, error)
+	SetLoyaltyMultiplier(ctx context.Context, m models.LoyaltyMultiplier) (models.LoyaltyMultiplier, error)
+	DeleteLoyaltyMultiplier(ctx context.Context, kategoriID int) error
+	GetPointsAccount(ctx context.Context, customerID int) (models.PointsAccount, error)
+}
+
+// ShiftStore mendefinisikan operasi shift kasir dan rekonsiliasi laci.
+type ShiftStore interface {
+	OpenShift(ctx context.Context, req models.OpenShiftRequest) (*models.Shift, error)
+	GetAllShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, error)
+	GetShift(ctx context.Context, id int) (*models.Shift, error)
+	CloseShift(ctx context.Context, id int, req models.CloseShiftRequest) (*models.Shift, error)
+	RecordCashMovement(ctx context.Context, req models.CashMovementRequest) (*models.CashMovement, error)
+	RecordNoSale(ctx context.Context, req models.NoSaleRequest) (*models.NoSale, error)
+}
+
+// UserStore mendefinisikan operasi akun pengguna dan sesi login.
+type UserStore interface {
+	CreateUser(ctx context.Context, req models.CreateUserRequest) (models.User, error)
+	GetAllUsers(ctx context.Context) ([]models.User, error)
+	UpdateUser(ctx context.Context, id int, req models.UpdateUserRequest) (models.User, error)
+	Login(ctx context.Context, req models.LoginRequest, ttl time.Duration) (*models.Session, error)
+	Authenticate(ctx context.Context, token string) (*models.User, error)
+	Logout(ctx context.Context, token string) error
+}
+
+// ApprovalStore mendefinisikan operasi persetujuan manajer untuk aksi kasir.
+type ApprovalStore interface {
+	VerifyManagerPIN(ctx context.Context, username, pin string) (*models.User, error)
+	CreateApprovalToken(ctx context.Context, managerID int, action string, ttl time.Duration) (*models.ApprovalToken, error)
+	RedeemApprovalToken(ctx context.Context, token, action string) (*models.User, error)
+	GetAllApprovals(ctx context.Context, filter ApprovalFilter) ([]models.Approval, error)
+}
+
+// APIKeyStore mendefinisikan operasi kunci API untuk terminal POS dan integrasi.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.APIKey, error)
+	GetAllAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int) (*models.APIKey, error)
+	RotateAPIKey(ctx context.Context, id int) (*models.APIKey, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// AuditStore mendefinisikan query audit log perubahan master data.
+type AuditStore interface {
+	GetAuditLog(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error)
+}
+
+// OpnameStore mendefinisikan operasi sesi stock opname.
+type OpnameStore interface {
+	OpenOpname(ctx context.Context, req models.OpenOpnameRequest) (*models.StockOpname, error)
+	ListOpname(ctx context.Context) ([]models.StockOpname, error)
+	GetOpname(ctx context.Context, id int) (*models.StockOpname, error)
+	RecordOpnameCounts(ctx context.Context, id int, req models.OpnameCountRequest) (*models.StockOpname, error)
+	FinalizeOpname(ctx context.Context, id int, req models.CloseOpnameRequest) (*models.StockOpname, error)
+	CancelOpname(ctx context.Context, id int, req models.CloseOpnameRequest) (*models.StockOpname, error)
+}
+
+// PurchaseStore mendefinisikan operasi supplier dan purchase order.
+type PurchaseStore interface {
+	AddSupplier(ctx context.Context, s models.Supplier) (models.Supplier, error)
+	GetAllSuppliers(ctx context.Context) ([]models.Supplier, error)
+	CreatePurchaseOrder(ctx context.Context, req models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	GetAllPurchaseOrders(ctx context.Context, filter PurchaseOrderFilter) ([]models.PurchaseOrder, error)
+	ApprovePurchaseOrder(ctx context.Context, id int, req models.PurchaseOrderActionRequest) (*models.PurchaseOrder, error)
+	ReceivePurchaseOrder(ctx context.Context, id int, req models.ReceivePurchaseOrderRequest) (*models.PurchaseReceipt, error)
+	CancelPurchaseOrder(ctx context.Context, id int, req models.PurchaseOrderActionRequest) (*models.PurchaseOrder, error)
+}
+
+// TransactionFilter berisi filter opsional untuk daftar transaksi.
+type TransactionFilter struct {
+	PaymentMethod string // Hanya transaksi yang punya pembayaran dengan metode ini.
+	CustomerID    int    // Hanya transaksi milik pelanggan ini.
+	ShiftID       int    // Hanya transaksi yang dibuat pada shift ini.
+}
+
+// ShiftFilter berisi filter opsional untuk daftar shift.
+type ShiftFilter struct {
+	Register string // Hanya shift pada register ini.
+	Status   string // Hanya shift dengan status ini.
+}
+
+// ApprovalFilter berisi filter opsional untuk daftar persetujuan manajer.
+type ApprovalFilter struct {
+	Action   string // Hanya persetujuan untuk aksi ini.
+	EntityID int    // Hanya persetujuan untuk data dengan ID ini.
+}
+
+// AuditFilter berisi filter untuk audit log. Waktu kosong berarti tanpa batas.
+type AuditFilter struct {
+	Entity string    // Hanya perubahan pada entitas ini.
+	Actor  string    // Hanya perubahan oleh pelaku ini.
+	Start  time.Time // Hanya perubahan sejak waktu ini.
+	End    time.Time // Hanya perubahan sebelum waktu ini (eksklusif).
+}
+
+// PurchaseOrderFilter berisi filter opsional untuk daftar purchase order.
+type PurchaseOrderFilter struct {
+	Status      string // Hanya PO dengan status ini.
+	SupplierID  int    // Hanya PO untuk supplier ini.
+	Outstanding bool   // Hanya PO yang sudah disetujui dan belum diterima penuh.
+}
+
+// queryer adalah bagian dari *sql.DB dan *sql.Tx yang dipakai helper query,
+// sehingga helper yang sama bisa jalan di dalam maupun di luar database transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// PostgresStore mengimplementasikan semua store dengan backend PostgreSQL.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore membuat PostgresStore dari koneksi database yang sudah terbuka.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Pastikan kedua backend memenuhi semua interface store.
+var (
+	_ ProdukStore      = (*PostgresStore)(nil)
+	_ KategoriStore    = (*PostgresStore)(nil)
+	_ TransactionStore = (*PostgresStore)(nil)
+	_ ReportStore      = (*PostgresStore)(nil)
+	_ OpnameStore      = (*PostgresStore)(nil)
+	_ PurchaseStore    = (*PostgresStore)(nil)
+	_ PromotionStore   = (*PostgresStore)(nil)
+	_ VoucherStore     = (*PostgresStore)(nil)
+	_ TaxStore         = (*PostgresStore)(nil)
+	_ RoundingStore    = (*PostgresStore)(nil)
+	_ CustomerStore    = (*PostgresStore)(nil)
+	_ LoyaltyStore     = (*PostgresStore)(nil)
+	_ ShiftStore       = (*PostgresStore)(nil)
+	_ UserStore        = (*PostgresStore)(nil)
+	_ ApprovalStore    = (*PostgresStore)(nil)
+	_ APIKeyStore      = (*PostgresStore)(nil)
+	_ AuditStore       = (*PostgresStore)(nil)
+	_ ProdukStore      = (*MemoryStore)(nil)
+	_ KategoriStore    = (*MemoryStore)(nil)
+	_ TransactionStore = (*MemoryStore)(nil)
+	_ PromotionStore   = (*MemoryStore)(nil)
+	_ VoucherStore     = (*MemoryStore)(nil)
+	_ TaxStore         = (*MemoryStore)(nil)
+	_ RoundingStore    = (*MemoryStore)(nil)
+	_ CustomerStore    = (*MemoryStore)(nil)
+	_ LoyaltyStore     = (*MemoryStore)(nil)
+	_ ShiftStore       = (*MemoryStore)(nil)
+	_ UserStore        = (*MemoryStore)(nil)
+	_ ApprovalStore    = (*MemoryStore)(nil)
+	_ APIKeyStore      = (*MemoryStore)(nil)
+	_ AuditStore       = (*MemoryStore)(nil)
+)
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"kasir-api/models"
+)
+
+// appliedTaxLine adalah aturan pajak yang dikenakan pada satu baris checkout
+// sebelum detail transaksi punya ID.
+type appliedTaxLine struct {
+	Line   int
+	Rule   models.TaxRule
+	Base   int
+	Amount int
+}
+
+// validateTaxRule memastikan aturan pajak lengkap dan konsisten.
+func validateTaxRule(r models.TaxRule) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if r.Type != models.TaxTypeTax && r.Type != models.TaxTypeService {
+		return fmt.Errorf("%w: unknown tax type %q, use tax or service", ErrInvalidInput, r.Type)
+	}
+	if r.Rate <= 0 || r.Rate > 100 {
+		return fmt.Errorf("%w: rate must be greater than 0 and at most 100", ErrInvalidInput)
+	}
+	if math.Round(r.Rate*100) != r.Rate*100 {
+		return fmt.Errorf("%w: rate supports at most two decimals", ErrInvalidInput)
+	}
+	if r.ProductID > 0 && r.KategoriID > 0 {
+		return fmt.Errorf("%w: choose either product_id or kategori_id, not both", ErrInvalidInput)
+	}
+	return nil
+}
+
+// taxRuleRank menilai seberapa spesifik aturan untuk detail d: 2 untuk produk,
+// 1 untuk kategori, 0 untuk semua produk, dan -1 jika tidak berlaku.
+func taxRuleRank(r models.TaxRule, d models.TransactionDetail) int {
+	switch {
+	case r.ProductID > 0:
+		if r.ProductID == d.ProductID {
+			return 2
+		}
+		return -1
+	case r.KategoriID > 0:
+		if r.KategoriID == d.KategoriID {
+			return 1
+		}
+		return -1
+	default:
+		return 0
+	}
+}
+
+// applyTaxes menghitung pajak dan service charge untuk setiap detail dari
+// Subtotal setelah semua potongan. Untuk setiap jenis, baris dikenai satu aturan
+// aktif yang paling spesifik; jika setara, aturan dengan ID terkecil menang
+// (rules harus urut ID). Service charge dihitung dari dasar pengenaan, lalu PPN
+// dihitung dari dasar pengenaan ditambah service charge, sesuai praktik
+// restoran di Indonesia. Untuk aturan inklusif, dasar pengenaan diturunkan dari
+// Subtotal dengan urutan yang sama. Tax, ServiceCharge, TaxIncluded, dan Total
+// pada details diisi ulang.
+func applyTaxes(details []models.TransactionDetail, rules []models.TaxRule) []appliedTaxLine {
+	var applied []appliedTaxLine
+
+	for i := range details {
+		d := &details[i]
+		d.Tax, d.ServiceCharge, d.TaxIncluded = 0, 0, 0
+
+		chosen := map[string]*models.TaxRule{}
+		ranks := map[string]int{}
+		for j := range rules {
+			r := &rules[j]
+			if !r.Active {
+				continue
+			}
+			rank := taxRuleRank(*r, *d)
+			if best, ok := ranks[r.Type]; rank < 0 || (ok && rank <= best) {
+				continue
+			}
+			chosen[r.Type], ranks[r.Type] = r, rank
+		}
+		taxRule, service := chosen[models.TaxTypeTax], chosen[models.TaxTypeService]
+
+		// Subtotal = dasar * (1 + service inklusif + PPN inklusif * (1 + service)).
+		serviceRate, taxRate, factor := 0.0, 0.0, 1.0
+		if service != nil {
+			serviceRate = service.Rate / 100
+			if service.Inclusive {
+				factor += serviceRate
+			}
+		}
+		if taxRule != nil {
+			taxRate = taxRule.Rate / 100
+			if taxRule.Inclusive {
+				factor += taxRate * (1 + serviceRate)
+			}
+		}
+		base := int(math.Round(float64(d.Subtotal) / factor))
+
+		d.ServiceCharge = int(math.Round(float64(base) * serviceRate))
+		d.Tax = int(math.Round(float64(base+d.ServiceCharge) * taxRate))
+		if service != nil && service.Inclusive {
+			d.TaxIncluded += d.ServiceCharge
+		}
+		if taxRule != nil && taxRule.Inclusive {
+			d.TaxIncluded += d.Tax
+		}
+
+		// Untuk harga inklusif, dasar pengenaan ditambah bagian inklusif harus
+		// tepat sama dengan Subtotal; selisih pembulatan masuk ke dasar pengenaan.
+		if d.TaxIncluded > 0 {
+			base = d.Subtotal - d.TaxIncluded
+		}
+		if taxRule != nil {
+			applied = append(applied, appliedTaxLine{Line: i, Rule: *taxRule, Base: base + d.ServiceCharge, Amount: d.Tax})
+		}
+		if service != nil {
+			applied = append(applied, appliedTaxLine{Line: i, Rule: *service, Base: base, Amount: d.ServiceCharge})
+		}
+
+		d.Total = d.Subtotal + d.Tax + d.ServiceCharge - d.TaxIncluded
+	}
+
+	return applied
+}
+
+// sumTaxes menjumlahkan pajak, service charge, bagian inklusif, dan grand total dari semua detail.
+func sumTaxes(details []models.TransactionDetail) (tax, service, included, total int) {
+	for _, d := range details {
+		tax += d.Tax
+		service += d.ServiceCharge
+		included += d.TaxIncluded
+		total += d.Total
+	}
+	return tax, service, included, total
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"kasir-api/models"
+)
+
+// taxRuleColumns adalah kolom tax_rules sesuai urutan scanTaxRule.
+const taxRuleColumns = "id, name, type, rate, inclusive, product_id, kategori_id, active, created_at"
+
+// AddTaxRule menambahkan aturan pajak atau service charge baru.
+func (s *PostgresStore) AddTaxRule(ctx context.Context, r models.TaxRule) (models.TaxRule, error) {
+	if err := validateTaxRule(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[tax-store] Error begin transaction: %v", err)
+		return models.TaxRule{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO tax_rules (name, type, rate, inclusive, product_id, kategori_id, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, r.Name, r.Type, r.Rate, r.Inclusive, nullableID(r.ProductID), nullableID(r.KategoriID), r.Active,
+	).Scan(&r.ID, &r.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return models.TaxRule{}, fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if err != nil {
+		log.Printf("[tax-store] Error AddTaxRule: %v", err)
+		return models.TaxRule{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityTaxRule, models.AuditActionCreate, r.ID, nil, r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[tax-store] Error commit AddTaxRule: %v", err)
+		return models.TaxRule{}, err
+	}
+
+	return r, nil
+}
+
+// GetAllTaxRules mengembalikan semua aturan pajak.
+func (s *PostgresStore) GetAllTaxRules(ctx context.Context) ([]models.TaxRule, error) {
+	return getTaxRules(ctx, s.db, false)
+}
+
+// GetTaxRuleByID mengembalikan satu aturan pajak berdasarkan ID.
+func (s *PostgresStore) GetTaxRuleByID(ctx context.Context, id int) (models.TaxRule, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+taxRuleColumns+" FROM tax_rules WHERE id = $1", id)
+	r, err := scanTaxRule(row)
+	if err == sql.ErrNoRows {
+		return models.TaxRule{}, fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[tax-store] Error GetTaxRuleByID: %v", err)
+		return models.TaxRule{}, err
+	}
+	return r, nil
+}
+
+// UpdateTaxRule mengganti aturan pajak berdasarkan ID. Transaksi lama tidak
+// berubah karena nama dan tarif pajak sudah disimpan saat checkout.
+func (s *PostgresStore) UpdateTaxRule(ctx context.Context, id int, r models.TaxRule) (models.TaxRule, error) {
+	if err := validateTaxRule(r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[tax-store] Error begin transaction: %v", err)
+		return models.TaxRule{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanTaxRule(tx.QueryRowContext(ctx, "SELECT "+taxRuleColumns+" FROM tax_rules WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.TaxRule{}, fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[tax-store] Error lock tax rule: %v", err)
+		return models.TaxRule{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE tax_rules
+		SET name = $1, type = $2, rate = $3, inclusive = $4, product_id = $5, kategori_id = $6, active = $7
+		WHERE id = $8
+		RETURNING id, created_at
+	`, r.Name, r.Type, r.Rate, r.Inclusive, nullableID(r.ProductID), nullableID(r.KategoriID), r.Active, id,
+	).Scan(&r.ID, &r.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return models.TaxRule{}, fmt.Errorf("%w: product or kategori not found", ErrInvalidInput)
+	}
+	if err != nil {
+		log.Printf("[tax-store] Error UpdateTaxRule: %v", err)
+		return models.TaxRule{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityTaxRule, models.AuditActionUpdate, id, before, r); err != nil {
+		return models.TaxRule{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[tax-store] Error commit UpdateTaxRule: %v", err)
+		return models.TaxRule{}, err
+	}
+
+	return r, nil
+}
+
+// DeleteTaxRule menghapus aturan pajak berdasarkan ID.
+func (s *PostgresStore) DeleteTaxRule(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[tax-store] Error begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := scanTaxRule(tx.QueryRowContext(ctx, "DELETE FROM tax_rules WHERE id = $1 RETURNING "+taxRuleColumns, id))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: tax rule id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[tax-store] Error DeleteTaxRule: %v", err)
+		return err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityTaxRule, models.AuditActionDelete, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[tax-store] Error commit DeleteTaxRule: %v", err)
+		return err
+	}
+	return nil
+}
+
+// getTaxRules mengambil aturan pajak urut ID, hanya yang aktif jika activeOnly.
+func getTaxRules(ctx context.Context, q queryer, activeOnly bool) ([]models.TaxRule, error) {
+	query := "SELECT " + taxRuleColumns + " FROM tax_rules"
+	if activeOnly {
+		query += " WHERE active"
+	}
+	query += " ORDER BY id"
+
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		log.Printf("[tax-store] Error get tax rules: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.TaxRule{}
+	for rows.Next() {
+		r, err := scanTaxRule(rows)
+		if err != nil {
+			log.Printf("[tax-store] Error scanning tax rule row: %v", err)
+			continue
+		}
+		rules = append(rules, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[tax-store] Error iterating tax rule rows: %v", err)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// scanTaxRule membaca satu baris aturan pajak.
+func scanTaxRule(row rowScanner) (models.TaxRule, error) {
+	var r models.TaxRule
+	var productID, kategoriID sql.NullInt64
+	err := row.Scan(&r.ID, &r.Name, &r.Type, &r.Rate, &r.Inclusive, &productID, &kategoriID, &r.Active, &r.CreatedAt)
+	if err != nil {
+		return models.TaxRule{}, err
+	}
+
+	r.ProductID = int(productID.Int64)
+	r.KategoriID = int(kategoriID.Int64)
+	return r, nil
+}
+
+// insertAppliedTaxes menyimpan pajak yang dikenakan per baris dan mengembalikan
+// catatannya. details harus sudah punya ID.
+func insertAppliedTaxes(ctx context.Context, tx *sql.Tx, transactionID int, details []models.TransactionDetail, applied []appliedTaxLine) ([]models.AppliedTax, error) {
+	taxes := make([]models.AppliedTax, 0, len(applied))
+	for _, a := range applied {
+		at := models.AppliedTax{
+			TransactionID:       transactionID,
+			TransactionDetailID: details[a.Line].ID,
+			TaxRuleID:           a.Rule.ID,
+			Name:                a.Rule.Name,
+			Type:                a.Rule.Type,
+			Rate:                a.Rule.Rate,
+			Inclusive:           a.Rule.Inclusive,
+			Base:                a.Base,
+			Amount:              a.Amount,
+		}
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO transaction_taxes (transaction_id, transaction_detail_id, tax_rule_id, name, type, rate, inclusive, base, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+		`, transactionID, at.TransactionDetailID, at.TaxRuleID, at.Name, at.Type, at.Rate, at.Inclusive, at.Base, at.Amount,
+		).Scan(&at.ID)
+		if err != nil {
+			log.Printf("[tax-store] Error insert applied tax: %v", err)
+			return nil, err
+		}
+		taxes = append(taxes, at)
+	}
+	return taxes, nil
+}
+
+// getAppliedTaxes mengambil pajak yang dikenakan pada satu transaksi.
+func getAppliedTaxes(ctx context.Context, q queryer, transactionID int) ([]models.AppliedTax, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, transaction_id, transaction_detail_id, tax_rule_id, name, type, rate, inclusive, base, amount
+		FROM transaction_taxes
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		log.Printf("[tax-store] Error get applied taxes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var taxes []models.AppliedTax
+	for rows.Next() {
+		var at models.AppliedTax
+		var ruleID sql.NullInt64
+		err := rows.Scan(&at.ID, &at.TransactionID, &at.TransactionDetailID, &ruleID, &at.Name, &at.Type,
+			&at.Rate, &at.Inclusive, &at.Base, &at.Amount)
+		if err != nil {
+			log.Printf("[tax-store] Error scanning applied tax row: %v", err)
+			continue
+		}
+		at.TaxRuleID = int(ruleID.Int64)
+		taxes = append(taxes, at)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[tax-store] Error iterating applied tax rows: %v", err)
+		return nil, err
+	}
+
+	return taxes, nil
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+
+	"kasir-api/models"
+)
+
+func TestApplyTaxesChargesPPNOnService(t *testing.T) {
+	ppn := models.TaxRule{ID: 1, Name: "PPN 11%", Type: models.TaxTypeTax, Rate: 11, Active: true}
+	service := models.TaxRule{ID: 2, Name: "Service 5%", Type: models.TaxTypeService, Rate: 5, Active: true}
+
+	tests := []struct {
+		name                            string
+		ppnInclusive, serviceInclusive  bool
+		subtotal                        int
+		wantTax, wantService, wantTotal int
+		wantTaxBase, wantServiceBase    int
+	}{
+		// Service 5% dari 100.000, PPN 11% dari 105.000.
+		{"exclusive", false, false, 100000, 11550, 5000, 116550, 105000, 100000},
+		// Harga 116.550 sudah termasuk keduanya: dasar pengenaan tetap 100.000.
+		{"inclusive", true, true, 116550, 11550, 5000, 116550, 105000, 100000},
+		// Harga 105.000 sudah termasuk service, PPN ditambahkan di atasnya.
+		{"service inclusive", false, true, 105000, 11550, 5000, 116550, 105000, 100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, s := ppn, service
+			p.Inclusive, s.Inclusive = tt.ppnInclusive, tt.serviceInclusive
+			details := []models.TransactionDetail{{ProductID: 1, Subtotal: tt.subtotal}}
+
+			applied := applyTaxes(details, []models.TaxRule{p, s})
+
+			d := details[0]
+			if d.Tax != tt.wantTax || d.ServiceCharge != tt.wantService || d.Total != tt.wantTotal {
+				t.Errorf("tax/service/total = %d/%d/%d, want %d/%d/%d",
+					d.Tax, d.ServiceCharge, d.Total, tt.wantTax, tt.wantService, tt.wantTotal)
+			}
+			if len(applied) != 2 || applied[0].Base != tt.wantTaxBase || applied[1].Base != tt.wantServiceBase {
+				t.Errorf("applied = %+v, want PPN base %d and service base %d", applied, tt.wantTaxBase, tt.wantServiceBase)
+			}
+		})
+	}
+}
@@ -1,87 +1,256 @@
 package store
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
-	"kasir-api/database"
 	"kasir-api/models"
 )
 
+// transactionColumns adalah kolom header transaksi (alias t) yang dibaca GetTransactionByID dan GetAllTransactions.
+const transactionColumns = `t.id, t.status, t.customer_id, t.shift_id, t.subtotal_amount, t.line_discount, t.cart_discount, t.voucher_code,
+	t.voucher_discount, t.manual_discount, t.tax_amount, t.service_charge, t.tax_included, t.rounding_adjustment, t.total_amount,
+	t.paid_amount, t.change_amount, t.points_earned, t.points_redeemed, t.created_at`
+
 // CreateTransaction membuat transaksi baru beserta detailnya dalam satu database transaction.
-func CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+func (s *PostgresStore) CreateTransaction(ctx context.Context, req models.CheckoutRequest) (*models.Transaction, error) {
+	// Gabungkan baris produk yang sama dan validasi jumlahnya sebelum menyentuh database.
+	items, lines, err := normalizeCheckoutItems(req.Items, req.MaxLineQuantity)
+	if err != nil {
+		log.Printf("[transaction-store] Checkout items rejected err=%v", err)
+		return nil, err
+	}
+	req.Items = items
+
 	// Mulai database transaction.
-	tx, err := database.DB.Begin()
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("[transaction-store] Error begin transaction: %v", err)
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
+	// Checkout hanya boleh pada register yang shift-nya terbuka. Shift dikunci
+	// FOR SHARE lebih dulu agar tidak bisa ditutup selama transaksi ini berjalan.
+	shiftID, err := lockOpenShift(ctx, tx, req.Register)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kunci pelanggan yang disebut sebelum memproses item agar saldo poinnya
+	// tidak berubah oleh checkout lain sampai transaksi ini selesai.
+	var account *pointsAccount
+	if req.CustomerID > 0 {
+		account, err = lockPointsAccount(ctx, tx, req.CustomerID)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: customer id %d not found", ErrInvalidInput, req.CustomerID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	details := make([]models.TransactionDetail, 0)
+	// Produk yang tidak ada atau stoknya kurang dikumpulkan per baris agar kasir
+	// bisa memperbaiki semuanya sekaligus.
+	var itemsErr CheckoutItemsError
+	// Alasan checkout ini butuh persetujuan manajer (ubah harga, potongan manual besar).
+	var approvalReasons []string
 
-	// Proses setiap item: validasi produk, hitung subtotal, kurangi stok.
-	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+	// Proses setiap item: validasi produk, cek stok, hitung subtotal.
+	for i, item := range req.Items {
+		var productPrice, costPrice, stock int
+		var productName, kategoriNama string
+		var kategoriID sql.NullInt64
 
-		// Ambil data produk dan cek stok.
-		err := tx.QueryRow("SELECT nama, harga, stok FROM produk WHERE id = $1", item.ProductID).
-			Scan(&productName, &productPrice, &stock)
+		// Ambil data produk beserta kategorinya dan cek stok.
+		err := tx.QueryRowContext(ctx, `
+			SELECT p.nama, p.harga, p.harga_beli, p.stok, p.kategori_id, COALESCE(k.nama, '')
+			FROM produk p
+			LEFT JOIN kategori k ON k.id = p.kategori_id
+			WHERE p.id = $1
+		`, item.ProductID).Scan(&productName, &productPrice, &costPrice, &stock, &kategoriID, &kategoriNama)
 		if err == sql.ErrNoRows {
 			log.Printf("[transaction-store] Product not found id=%d", item.ProductID)
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			itemsErr.add(ErrInvalidInput, lines[i], item.ProductID, "product_id", "product not found")
+			continue
 		}
 		if err != nil {
 			log.Printf("[transaction-store] Error get product: %v", err)
 			return nil, err
 		}
 
-		// Validasi stok cukup.
+		// Validasi stok cukup. Baris produk yang sama sudah digabung, jadi cukup dicek sekali.
 		if stock < item.Quantity {
 			log.Printf("[transaction-store] Insufficient stock product_id=%d requested=%d available=%d",
 				item.ProductID, item.Quantity, stock)
-			return nil, fmt.Errorf("insufficient stock for product %s (requested: %d, available: %d)",
-				productName, item.Quantity, stock)
+			itemsErr.add(ErrInsufficientStock, lines[i], item.ProductID, "quantity",
+				fmt.Sprintf("insufficient stock for product %s (requested: %d, available: %d)", productName, item.Quantity, stock))
+			continue
 		}
 
-		// Hitung subtotal.
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
-
-		// Kurangi stok produk.
-		_, err = tx.Exec("UPDATE produk SET stok = stok - $1 WHERE id = $2",
-			item.Quantity, item.ProductID)
+		unitPrice, reason, err := itemPrice(item, productPrice)
 		if err != nil {
-			log.Printf("[transaction-store] Error update stock: %v", err)
 			return nil, err
 		}
+		if reason != "" {
+			approvalReasons = append(approvalReasons, reason)
+		}
 
+		// Simpan nama, kategori, dan harga saat ini sebagai snapshot di detail.
 		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			ProductID:    item.ProductID,
+			ProductName:  productName,
+			KategoriID:   int(kategoriID.Int64),
+			KategoriNama: kategoriNama,
+			Quantity:     item.Quantity,
+			UnitPrice:    unitPrice,
+			CostPrice:    costPrice,
 		})
 	}
+	if err := itemsErr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	// Terapkan promosi yang aktif lalu hitung total setelah potongan.
+	promotions, err := getPromotions(ctx, tx, true)
+	if err != nil {
+		return nil, err
+	}
+	applied := applyPromotions(details, promotions, time.Now())
+
+	// Voucher dihitung dari total setelah promosi. Baris voucher dikunci sebelum
+	// stok produk diubah, urutan yang sama dengan void.
+	var voucher *models.Voucher
+	voucherAmount := 0
+	if req.VoucherCode != "" {
+		voucher, voucherAmount, err = applyVoucher(ctx, tx, req.VoucherCode, req.CustomerID, details)
+		if err != nil {
+			log.Printf("[transaction-store] Voucher rejected code=%q err=%v", req.VoucherCode, err)
+			return nil, err
+		}
+	}
+
+	// Potongan manual kasir dihitung terakhir dari total setelah promosi dan voucher.
+	reason, err := applyManualDiscount(details, req)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		approvalReasons = append(approvalReasons, reason)
+	}
+	approval, err := checkoutApproval(ctx, approvalReasons)
+	if err != nil {
+		log.Printf("[transaction-store] Checkout needs approval err=%v", err)
+		return nil, err
+	}
+
+	subtotalAmount, lineDiscount, cartDiscount, _ := sumDetails(details)
+	cartDiscount -= voucherAmount + req.ManualDiscount
+	voucherCode := ""
+	if voucher != nil {
+		voucherCode = voucher.Code
+	}
+
+	// Hitung pajak dan service charge dari subtotal setelah semua potongan.
+	taxRules, err := getTaxRules(ctx, tx, true)
+	if err != nil {
+		return nil, err
+	}
+	appliedTaxes := applyTaxes(details, taxRules)
+	taxAmount, serviceCharge, taxIncluded, totalAmount := sumTaxes(details)
+
+	// Bulatkan grand total sesuai kebijakan metode pembayaran (misalnya tunai ke Rp100).
+	roundingPolicies, err := getRoundingPolicies(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	rounding := roundingAdjustment(totalAmount, req.Payments, roundingPolicies)
+	totalAmount += rounding
+
+	// Validasi pembayaran dan hitung kembalian.
+	paidAmount, changeAmount, err := settlePayments(totalAmount, req.Payments)
+	if err != nil {
+		log.Printf("[transaction-store] Payment rejected total=%d err=%v", totalAmount, err)
+		return nil, err
+	}
+
+	// Hitung poin yang ditukar dan diperoleh. Belanja yang dibayar dengan poin
+	// tidak menghasilkan poin baru.
+	loyalty, err := getLoyaltySettings(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	pointsAmount, pointsRedeemed, err := pointsPayment(req.Payments, req.CustomerID, loyalty)
+	if err != nil {
+		log.Printf("[transaction-store] Points payment rejected customer_id=%d err=%v", req.CustomerID, err)
+		return nil, err
+	}
+	pointsEarned := 0
+	if account != nil {
+		multipliers, err := getLoyaltyMultipliers(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		pointsEarned = earnPoints(details, loyalty, multipliers, pointsAmount)
+	}
 
 	// Insert transaction record dan dapatkan ID.
 	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id",
-		totalAmount).Scan(&transactionID)
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO transactions (customer_id, shift_id, subtotal_amount, line_discount, cart_discount, voucher_code, voucher_discount,
+			manual_discount, tax_amount, service_charge, tax_included, rounding_adjustment, total_amount, paid_amount, change_amount,
+			points_earned, points_redeemed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at`,
+		nullableID(req.CustomerID), shiftID, subtotalAmount, lineDiscount, cartDiscount, voucherCode, voucherAmount,
+		req.ManualDiscount, taxAmount, serviceCharge, taxIncluded, rounding, totalAmount, paidAmount, changeAmount,
+		pointsEarned, pointsRedeemed).Scan(&transactionID, &createdAt)
 	if err != nil {
 		log.Printf("[transaction-store] Error insert transaction: %v", err)
 		return nil, err
 	}
 
-	// Insert transaction details dan dapatkan ID masing-masing detail.
+	// Catat manajer yang menyetujui checkout ini bersama transaksinya.
+	if approval != nil {
+		if err := insertApproval(ctx, tx, *approval, transactionID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Catat penukaran dan perolehan poin di ledger dalam database transaction yang sama.
+	if account != nil {
+		now := time.Now()
+		account.expire(now)
+		if err := account.redeem(transactionID, pointsRedeemed); err != nil {
+			log.Printf("[transaction-store] Points payment rejected customer_id=%d err=%v", req.CustomerID, err)
+			return nil, err
+		}
+		account.credit(models.PointsTypeEarn, transactionID, pointsEarned, pointsExpiry(loyalty, now))
+		if err := savePointsAccount(ctx, tx, account); err != nil {
+			return nil, err
+		}
+	}
+
+	// Insert transaction details.
 	for i := range details {
 		details[i].TransactionID = transactionID
-		err := tx.QueryRow(
-			"INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4) RETURNING id",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal,
+		err := tx.QueryRowContext(ctx,
+			`INSERT INTO transaction_details (transaction_id, product_id, product_name, kategori_id, kategori_nama,
+				quantity, unit_price, cost_price, discount, cart_discount, subtotal, tax, service_charge, tax_included, total,
+				points_earned)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id`,
+			transactionID, details[i].ProductID, details[i].ProductName, nullableID(details[i].KategoriID),
+			details[i].KategoriNama, details[i].Quantity, details[i].UnitPrice, details[i].CostPrice,
+			details[i].Discount, details[i].CartDiscount, details[i].Subtotal,
+			details[i].Tax, details[i].ServiceCharge, details[i].TaxIncluded, details[i].Total,
+			details[i].PointsEarned,
 		).Scan(&details[i].ID)
 		if err != nil {
 			log.Printf("[transaction-store] Error insert transaction detail: %v", err)
@@ -89,29 +258,142 @@ func CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error)
 		}
 	}
 
+	// Kurangi stok lewat ledger dengan referensi transaksi. Cek stok di atas
+	// dibaca tanpa lock, jadi adjustStock mengulang cek itu secara atomik dan
+	// menolak penjualan bila checkout lain sudah mengambil stoknya lebih dulu.
+	// Baris produk dikunci urut ID agar keranjang berisi produk yang sama tidak
+	// saling deadlock; voucher sudah dikunci sebelumnya, urutan yang sama dengan void.
+	// Baris yang kalah balapan dilaporkan per baris seperti cek stok di atas.
+	for _, i := range stockOrder(len(details), func(i int) int { return details[i].ProductID }) {
+		_, err := adjustStock(ctx, tx, models.StockMovement{
+			ProductID:   details[i].ProductID,
+			Delta:       -details[i].Quantity,
+			Reason:      models.StockReasonSale,
+			ReferenceID: &transactionID,
+		})
+		if errors.Is(err, ErrInsufficientStock) {
+			var stock int
+			if err := tx.QueryRowContext(ctx, "SELECT stok FROM produk WHERE id = $1", details[i].ProductID).
+				Scan(&stock); err != nil && err != sql.ErrNoRows {
+				log.Printf("[transaction-store] Error get product stock: %v", err)
+				return nil, err
+			}
+			log.Printf("[transaction-store] Insufficient stock at decrement product_id=%d requested=%d available=%d",
+				details[i].ProductID, details[i].Quantity, stock)
+			itemsErr.add(ErrInsufficientStock, lines[i], details[i].ProductID, "quantity",
+				fmt.Sprintf("insufficient stock for product %s (requested: %d, available: %d)",
+					details[i].ProductName, details[i].Quantity, stock))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := itemsErr.errOrNil(); err != nil {
+		slices.SortFunc(itemsErr.Lines, func(a, b models.CheckoutLineError) int { return a.Line - b.Line })
+		return nil, err
+	}
+
+	// Catat promosi yang diterapkan per baris dan keranjang.
+	appliedPromotions, err := insertAppliedPromotions(ctx, tx, transactionID, details, applied)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catat pajak dan service charge per baris.
+	taxes, err := insertAppliedTaxes(ctx, tx, transactionID, details, appliedTaxes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catat penukaran voucher dalam database transaction yang sama dengan penjualan.
+	if voucher != nil {
+		err := insertVoucherRedemption(ctx, tx, models.VoucherRedemption{
+			VoucherID:     voucher.ID,
+			TransactionID: transactionID,
+			CustomerID:    req.CustomerID,
+			Amount:        voucherAmount,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Insert setiap baris pembayaran.
+	payments := make([]models.TransactionPayment, len(req.Payments))
+	for i, p := range req.Payments {
+		payments[i] = models.TransactionPayment{
+			TransactionID: transactionID,
+			Method:        p.Method,
+			Amount:        p.Amount,
+			Reference:     p.Reference,
+		}
+		err := tx.QueryRowContext(ctx,
+			"INSERT INTO transaction_payments (transaction_id, method, amount, reference) VALUES ($1, $2, $3, $4) RETURNING id",
+			transactionID, p.Method, p.Amount, p.Reference,
+		).Scan(&payments[i].ID)
+		if err != nil {
+			log.Printf("[transaction-store] Error insert transaction payment: %v", err)
+			return nil, err
+		}
+	}
+
+	// Tandai Idempotency-Key selesai bersama transaksinya.
+	if req.IdempotencyKey != "" {
+		if err := completeCheckoutKey(ctx, tx, req, transactionID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit transaction.
 	if err := tx.Commit(); err != nil {
 		log.Printf("[transaction-store] Error commit transaction: %v", err)
 		return nil, err
 	}
 
-	log.Printf("[transaction-store] Transaction created id=%d total=%d items=%d",
-		transactionID, totalAmount, len(details))
+	log.Printf("[transaction-store] Transaction created id=%d shift_id=%d total=%d paid=%d change=%d items=%d payments=%d",
+		transactionID, shiftID, totalAmount, paidAmount, changeAmount, len(details), len(payments))
 
 	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
+		ID:                 transactionID,
+		Status:             models.TransactionStatusCompleted,
+		CustomerID:         req.CustomerID,
+		ShiftID:            shiftID,
+		SubtotalAmount:     subtotalAmount,
+		LineDiscount:       lineDiscount,
+		CartDiscount:       cartDiscount,
+		VoucherCode:        voucherCode,
+		VoucherDiscount:    voucherAmount,
+		ManualDiscount:     req.ManualDiscount,
+		TaxAmount:          taxAmount,
+		ServiceCharge:      serviceCharge,
+		TaxIncluded:        taxIncluded,
+		RoundingAdjustment: rounding,
+		TotalAmount:        totalAmount,
+		PaidAmount:         paidAmount,
+		ChangeAmount:       changeAmount,
+		PointsEarned:       pointsEarned,
+		PointsRedeemed:     pointsRedeemed,
+		CreatedAt:          createdAt,
+		Details:            details,
+		Payments:           payments,
+		PaymentBreakdown:   paymentBreakdown(payments, changeAmount),
+		Promotions:         appliedPromotions,
+		Taxes:              taxes,
 	}, nil
 }
 
 // GetTransactionByID mengembalikan satu transaksi berdasarkan ID beserta detailnya.
-func GetTransactionByID(id int) (*models.Transaction, error) {
+func (s *PostgresStore) GetTransactionByID(ctx context.Context, id int) (*models.Transaction, error) {
 	var transaction models.Transaction
+	var customerID, shiftID sql.NullInt64
 
 	// Ambil data transaksi.
-	err := database.DB.QueryRow("SELECT id, total_amount, created_at FROM transactions WHERE id = $1", id).
-		Scan(&transaction.ID, &transaction.TotalAmount, &transaction.CreatedAt)
+	err := s.db.QueryRowContext(ctx, "SELECT "+transactionColumns+" FROM transactions t WHERE t.id = $1", id).
+		Scan(&transaction.ID, &transaction.Status, &customerID, &shiftID, &transaction.SubtotalAmount, &transaction.LineDiscount, &transaction.CartDiscount,
+			&transaction.VoucherCode, &transaction.VoucherDiscount, &transaction.ManualDiscount, &transaction.TaxAmount, &transaction.ServiceCharge,
+			&transaction.TaxIncluded, &transaction.RoundingAdjustment, &transaction.TotalAmount, &transaction.PaidAmount,
+			&transaction.ChangeAmount, &transaction.PointsEarned, &transaction.PointsRedeemed, &transaction.CreatedAt)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("transaction id %d not found", id)
 	}
@@ -119,15 +401,62 @@ func GetTransactionByID(id int) (*models.Transaction, error) {
 		log.Printf("[transaction-store] Error get transaction: %v", err)
 		return nil, err
 	}
+	transaction.CustomerID = int(customerID.Int64)
+	transaction.ShiftID = int(shiftID.Int64)
+
+	// Ambil detail transaksi beserta jumlah yang sudah dikembalikan.
+	details, err := getTransactionDetails(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Details = details
+
+	// Ambil pembayaran transaksi.
+	payments, err := getTransactionPayments(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Payments = payments
+	transaction.PaymentBreakdown = paymentBreakdown(payments, transaction.ChangeAmount)
+
+	// Ambil void/refund yang terkait transaksi.
+	reversals, err := getTransactionReversals(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Reversals = reversals
 
-	// Ambil detail transaksi dengan join ke produk untuk nama produk.
-	rows, err := database.DB.Query(`
-		SELECT td.id, td.transaction_id, td.product_id, p.nama, td.quantity, td.subtotal
+	// Ambil promosi yang diterapkan pada transaksi.
+	appliedPromotions, err := getAppliedPromotions(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Promotions = appliedPromotions
+
+	// Ambil pajak dan service charge per baris.
+	taxes, err := getAppliedTaxes(ctx, s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Taxes = taxes
+
+	return &transaction, nil
+}
+
+// getTransactionDetails mengambil detail transaksi apa adanya saat dijual (nama,
+// kategori, dan harga dari snapshot), beserta jumlah barang yang sudah
+// dikembalikan lewat void/refund. Tidak ada join ke produk, sehingga perubahan
+// atau penghapusan produk tidak mengubah struk lama.
+func getTransactionDetails(ctx context.Context, q queryer, transactionID int) ([]models.TransactionDetail, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT td.id, td.transaction_id, td.product_id, td.product_name, td.kategori_id, td.kategori_nama,
+			td.quantity, td.unit_price, td.cost_price, td.discount, td.cart_discount, td.subtotal,
+			td.tax, td.service_charge, td.tax_included, td.total, td.points_earned,
+			COALESCE((SELECT SUM(ri.quantity) FROM transaction_reversal_items ri WHERE ri.transaction_detail_id = td.id), 0)
 		FROM transaction_details td
-		JOIN produk p ON td.product_id = p.id
 		WHERE td.transaction_id = $1
 		ORDER BY td.id
-	`, id)
+	`, transactionID)
 	if err != nil {
 		log.Printf("[transaction-store] Error get transaction details: %v", err)
 		return nil, err
@@ -137,10 +466,16 @@ func GetTransactionByID(id int) (*models.Transaction, error) {
 	var details []models.TransactionDetail
 	for rows.Next() {
 		var d models.TransactionDetail
-		if err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Subtotal); err != nil {
+		var productID, kategoriID sql.NullInt64
+		err := rows.Scan(&d.ID, &d.TransactionID, &productID, &d.ProductName, &kategoriID, &d.KategoriNama,
+			&d.Quantity, &d.UnitPrice, &d.CostPrice, &d.Discount, &d.CartDiscount, &d.Subtotal,
+			&d.Tax, &d.ServiceCharge, &d.TaxIncluded, &d.Total, &d.PointsEarned, &d.RefundedQuantity)
+		if err != nil {
 			log.Printf("[transaction-store] Error scanning detail row: %v", err)
 			continue
 		}
+		d.ProductID = int(productID.Int64)
+		d.KategoriID = int(kategoriID.Int64)
 		details = append(details, d)
 	}
 
@@ -149,13 +484,66 @@ func GetTransactionByID(id int) (*models.Transaction, error) {
 		return nil, err
 	}
 
-	transaction.Details = details
-	return &transaction, nil
+	return details, nil
+}
+
+// getTransactionPayments mengambil semua pembayaran untuk satu transaksi.
+func getTransactionPayments(ctx context.Context, q queryer, transactionID int) ([]models.TransactionPayment, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT id, transaction_id, method, amount, reference FROM transaction_payments WHERE transaction_id = $1 ORDER BY id",
+		transactionID)
+	if err != nil {
+		log.Printf("[transaction-store] Error get transaction payments: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.TransactionPayment
+	for rows.Next() {
+		var p models.TransactionPayment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.Reference); err != nil {
+			log.Printf("[transaction-store] Error scanning payment row: %v", err)
+			continue
+		}
+		payments = append(payments, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[transaction-store] Error iterating payment rows: %v", err)
+		return nil, err
+	}
+
+	return payments, nil
 }
 
-// GetAllTransactions mengembalikan semua transaksi (tanpa detail untuk performa).
-func GetAllTransactions() ([]models.Transaction, error) {
-	rows, err := database.DB.Query("SELECT id, total_amount, created_at FROM transactions ORDER BY id DESC")
+// GetAllTransactions mengembalikan semua transaksi sesuai filter (tanpa detail untuk performa).
+func (s *PostgresStore) GetAllTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions t"
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.PaymentMethod != "" {
+		args = append(args, filter.PaymentMethod)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM transaction_payments tp WHERE tp.transaction_id = t.id AND tp.method = $%d)", len(args)))
+	}
+
+	if filter.CustomerID > 0 {
+		args = append(args, filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("t.customer_id = $%d", len(args)))
+	}
+
+	if filter.ShiftID > 0 {
+		args = append(args, filter.ShiftID)
+		conditions = append(conditions, fmt.Sprintf("t.shift_id = $%d", len(args)))
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY t.id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		log.Printf("[transaction-store] Error get all transactions: %v", err)
 		return nil, err
@@ -165,10 +553,17 @@ func GetAllTransactions() ([]models.Transaction, error) {
 	var transactions []models.Transaction
 	for rows.Next() {
 		var t models.Transaction
-		if err := rows.Scan(&t.ID, &t.TotalAmount, &t.CreatedAt); err != nil {
+		var customerID, shiftID sql.NullInt64
+		err := rows.Scan(&t.ID, &t.Status, &customerID, &shiftID, &t.SubtotalAmount, &t.LineDiscount, &t.CartDiscount,
+			&t.VoucherCode, &t.VoucherDiscount, &t.ManualDiscount, &t.TaxAmount, &t.ServiceCharge, &t.TaxIncluded,
+			&t.RoundingAdjustment, &t.TotalAmount, &t.PaidAmount, &t.ChangeAmount, &t.PointsEarned, &t.PointsRedeemed,
+			&t.CreatedAt)
+		if err != nil {
 			log.Printf("[transaction-store] Error scanning transaction row: %v", err)
 			continue
 		}
+		t.CustomerID = int(customerID.Int64)
+		t.ShiftID = int(shiftID.Int64)
 		transactions = append(transactions, t)
 	}
 
@@ -179,3 +574,8 @@ func GetAllTransactions() ([]models.Transaction, error) {
 
 	return transactions, nil
 }
+
+// nullableID mengubah ID bernilai 0 (tidak ada) menjadi NULL untuk kolom foreign key.
+func nullableID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
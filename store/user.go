@@ -0,0 +1,170 @@
+package store
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"kasir-api/models"
+)
+
+// Parameter hash password. Iterasi mengikuti rekomendasi OWASP untuk
+// PBKDF2-HMAC-SHA256; hash lama tetap terbaca karena iterasi ikut disimpan.
+const (
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 600000
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+	minPasswordLength  = 8
+)
+
+// validRoles berisi role pengguna yang dikenal.
+var validRoles = map[string]bool{
+	models.RoleOwner:      true,
+	models.RoleManager:    true,
+	models.RoleCashier:    true,
+	models.RoleStockClerk: true,
+}
+
+// dummyPasswordHash dipakai saat username tidak ditemukan agar waktu respons
+// login sama dengan password yang salah. Dihitung sekali saat pertama dipakai.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	encoded, _ := hashPassword("kasir-api-dummy-password")
+	return encoded
+})
+
+// prepareUser memvalidasi request pembuatan pengguna lalu merapikan username.
+func prepareUser(req models.CreateUserRequest) (models.CreateUserRequest, error) {
+	req.Username = normalizeUsername(req.Username)
+	if req.Username == "" {
+		return req, fmt.Errorf("%w: username is required", ErrInvalidInput)
+	}
+	if !validRoles[req.Role] {
+		return req, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, req.Role)
+	}
+	if err := validatePassword(req.Password); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// validateUserUpdate memvalidasi perubahan akun pengguna.
+func validateUserUpdate(req models.UpdateUserRequest) error {
+	if req.Role != nil && !validRoles[*req.Role] {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, *req.Role)
+	}
+	if req.PIN != nil && *req.PIN != "" {
+		if err := validatePIN(*req.PIN); err != nil {
+			return err
+		}
+	}
+	if req.Password != nil {
+		return validatePassword(*req.Password)
+	}
+	return nil
+}
+
+// validateUserPIN memastikan PIN hanya dipasang pada pengguna yang boleh
+// menyetujui aksi kasir. u adalah pengguna setelah perubahan role diterapkan.
+func validateUserPIN(u models.User, req models.UpdateUserRequest) error {
+	if req.PIN != nil && *req.PIN != "" && !approverRoles[u.Role] {
+		return fmt.Errorf("%w: only owner or manager can have an approval pin", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validatePassword memastikan password cukup panjang.
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidInput, minPasswordLength)
+	}
+	return nil
+}
+
+// normalizeUsername merapikan username agar login tidak peka huruf besar/kecil.
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// hashPassword membuat hash PBKDF2 dengan salt acak dalam format
+// "pbkdf2-sha256$iterasi$salt$hash" (salt dan hash dalam base64).
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s$%d$%s$%s", passwordScheme, passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifyPassword membandingkan password dengan hash tersimpan dalam waktu konstan.
+func verifyPassword(encoded, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// newSessionToken membuat token sesi acak beserta hash yang disimpan di database.
+func newSessionToken() (token, tokenHash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+// hashToken mengembalikan hash SHA-256 (hex) dari token. Token acak 256 bit
+// tidak perlu di-salt, sehingga hash bisa dipakai langsung untuk lookup.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokesOwner melaporkan apakah perubahan mencabut hak owner aktif dari u.
+func revokesOwner(u models.User, req models.UpdateUserRequest) bool {
+	if u.Role != models.RoleOwner || !u.Active {
+		return false
+	}
+	return (req.Role != nil && *req.Role != models.RoleOwner) || (req.Active != nil && !*req.Active)
+}
+
+// applyUserUpdate menerapkan perubahan role dan status aktif ke u.
+func applyUserUpdate(u models.User, req models.UpdateUserRequest) models.User {
+	if req.Role != nil {
+		u.Role = *req.Role
+	}
+	if req.Active != nil {
+		u.Active = *req.Active
+	}
+	return u
+}
@@ -0,0 +1,258 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"kasir-api/models"
+)
+
+// userColumns adalah kolom users sesuai urutan scanUser (tanpa hash password).
+const userColumns = "id, username, role, active, created_at, updated_at"
+
+// CreateUser membuat pengguna baru dengan password yang di-hash.
+func (s *PostgresStore) CreateUser(ctx context.Context, req models.CreateUserRequest) (models.User, error) {
+	req, err := prepareUser(req)
+	if err != nil {
+		return models.User{}, err
+	}
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		log.Printf("[user-store] Error hash password: %v", err)
+		return models.User{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[user-store] Error begin transaction: %v", err)
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
+	u, err := scanUser(tx.QueryRowContext(ctx,
+		"INSERT INTO users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING "+userColumns,
+		req.Username, passwordHash, req.Role))
+	if isUniqueViolation(err) {
+		return models.User{}, fmt.Errorf("%w: username %q already exists", ErrConflict, req.Username)
+	}
+	if err != nil {
+		log.Printf("[user-store] Error CreateUser: %v", err)
+		return models.User{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityUser, models.AuditActionCreate, u.ID, nil, u); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[user-store] Error commit transaction: %v", err)
+		return models.User{}, err
+	}
+
+	log.Printf("[user-store] User created id=%d username=%s role=%s", u.ID, u.Username, u.Role)
+	return u, nil
+}
+
+// GetAllUsers mengembalikan semua pengguna urut ID.
+func (s *PostgresStore) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY id")
+	if err != nil {
+		log.Printf("[user-store] Error GetAllUsers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			log.Printf("[user-store] Error scanning user row: %v", err)
+			continue
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[user-store] Error iterating user rows: %v", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UpdateUser mengubah role, status aktif, password, atau PIN pengguna. Owner aktif
+// terakhir tidak bisa diturunkan atau dinonaktifkan. Sesi pengguna dicabut saat
+// akun dinonaktifkan atau password diganti.
+func (s *PostgresStore) UpdateUser(ctx context.Context, id int, req models.UpdateUserRequest) (models.User, error) {
+	if err := validateUserUpdate(req); err != nil {
+		return models.User{}, err
+	}
+	passwordHash := ""
+	if req.Password != nil {
+		var err error
+		if passwordHash, err = hashPassword(*req.Password); err != nil {
+			log.Printf("[user-store] Error hash password: %v", err)
+			return models.User{}, err
+		}
+	}
+	pinHash, err := hashPIN(req.PIN)
+	if err != nil {
+		log.Printf("[user-store] Error hash pin: %v", err)
+		return models.User{}, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[user-store] Error begin transaction: %v", err)
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
+	// Kunci semua owner aktif lebih dulu agar dua perubahan bersamaan tidak
+	// menghabiskan owner terakhir.
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE role = $1 AND active ORDER BY id FOR UPDATE",
+		models.RoleOwner)
+	if err != nil {
+		log.Printf("[user-store] Error lock owners: %v", err)
+		return models.User{}, err
+	}
+	owners := 0
+	for rows.Next() {
+		owners++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("[user-store] Error iterating owner rows: %v", err)
+		return models.User{}, err
+	}
+
+	u, err := scanUser(tx.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.User{}, fmt.Errorf("%w: user id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[user-store] Error get user: %v", err)
+		return models.User{}, err
+	}
+	if revokesOwner(u, req) && owners <= 1 {
+		return models.User{}, fmt.Errorf("%w: user %d is the last active owner", ErrConflict, id)
+	}
+
+	before := u
+	u = applyUserUpdate(u, req)
+	if err := validateUserPIN(u, req); err != nil {
+		return models.User{}, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE users SET role = $1, active = $2, password_hash = COALESCE(NULLIF($3, ''), password_hash),
+			pin_hash = CASE WHEN $4 THEN NULLIF($5, '') ELSE pin_hash END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6
+		RETURNING updated_at
+	`, u.Role, u.Active, passwordHash, req.PIN != nil, pinHash, id).Scan(&u.UpdatedAt)
+	if err != nil {
+		log.Printf("[user-store] Error UpdateUser: %v", err)
+		return models.User{}, err
+	}
+
+	if !u.Active || req.Password != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id = $1", id); err != nil {
+			log.Printf("[user-store] Error revoke sessions: %v", err)
+			return models.User{}, err
+		}
+	}
+
+	// Hash password dan PIN tidak pernah masuk audit log.
+	if err := insertAudit(ctx, tx, models.AuditEntityUser, models.AuditActionUpdate, id, before, u); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[user-store] Error commit transaction: %v", err)
+		return models.User{}, err
+	}
+
+	log.Printf("[user-store] User updated id=%d role=%s active=%t", id, u.Role, u.Active)
+	return u, nil
+}
+
+// Login memeriksa username dan password lalu membuat sesi baru yang berlaku
+// selama ttl. Username yang tidak ada dan password yang salah menghasilkan
+// error yang sama.
+func (s *PostgresStore) Login(ctx context.Context, req models.LoginRequest, ttl time.Duration) (*models.Session, error) {
+	var u models.User
+	var passwordHash string
+	err := s.db.QueryRowContext(ctx, "SELECT "+userColumns+", password_hash FROM users WHERE username = $1",
+		normalizeUsername(req.Username)).
+		Scan(&u.ID, &u.Username, &u.Role, &u.Active, &u.CreatedAt, &u.UpdatedAt, &passwordHash)
+	if err == sql.ErrNoRows {
+		verifyPassword(dummyPasswordHash(), req.Password)
+		return nil, fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
+	}
+	if err != nil {
+		log.Printf("[user-store] Error get user for login: %v", err)
+		return nil, err
+	}
+	if !verifyPassword(passwordHash, req.Password) || !u.Active {
+		return nil, fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
+	}
+
+	token, tokenHash, err := newSessionToken()
+	if err != nil {
+		log.Printf("[user-store] Error generate session token: %v", err)
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	// Bersihkan sesi kedaluwarsa milik pengguna ini sekalian membuat sesi baru.
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id = $1 AND expires_at <= CURRENT_TIMESTAMP", u.ID); err != nil {
+		log.Printf("[user-store] Error delete expired sessions: %v", err)
+		return nil, err
+	}
+	_, err = s.db.ExecContext(ctx, "INSERT INTO user_sessions (token_hash, user_id, expires_at) VALUES ($1, $2, $3)",
+		tokenHash, u.ID, expiresAt)
+	if err != nil {
+		log.Printf("[user-store] Error insert session: %v", err)
+		return nil, err
+	}
+
+	log.Printf("[user-store] User logged in id=%d username=%s", u.ID, u.Username)
+	return &models.Session{Token: token, ExpiresAt: expiresAt, User: u}, nil
+}
+
+// Authenticate mengembalikan pengguna aktif pemilik token sesi yang belum kedaluwarsa.
+func (s *PostgresStore) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	u, err := scanUser(s.db.QueryRowContext(ctx, `
+		SELECT u.id, u.username, u.role, u.active, u.created_at, u.updated_at
+		FROM user_sessions us
+		JOIN users u ON u.id = us.user_id
+		WHERE us.token_hash = $1 AND us.expires_at > CURRENT_TIMESTAMP AND u.active
+	`, hashToken(token)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: invalid or expired session", ErrUnauthorized)
+	}
+	if err != nil {
+		log.Printf("[user-store] Error Authenticate: %v", err)
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Logout menghapus sesi milik token. Token yang sudah tidak berlaku diabaikan.
+func (s *PostgresStore) Logout(ctx context.Context, token string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM user_sessions WHERE token_hash = $1", hashToken(token)); err != nil {
+		log.Printf("[user-store] Error Logout: %v", err)
+		return err
+	}
+	return nil
+}
+
+// scanUser membaca satu baris pengguna.
+func scanUser(row rowScanner) (models.User, error) {
+	var u models.User
+	err := row.Scan(&u.ID, &u.Username, &u.Role, &u.Active, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"kasir-api/models"
+)
+
+// normalizeVoucherCode menyeragamkan kode voucher agar pencarian tidak peka huruf besar/kecil.
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validateVoucher memastikan data voucher lengkap dan konsisten.
+func validateVoucher(v models.Voucher) error {
+	if normalizeVoucherCode(v.Code) == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidInput)
+	}
+	switch v.DiscountType {
+	case models.DiscountTypePercentage:
+		if v.Value <= 0 || v.Value > 100 {
+			return fmt.Errorf("%w: percentage value must be between 1 and 100", ErrInvalidInput)
+		}
+	case models.DiscountTypeFixed:
+		if v.Value <= 0 {
+			return fmt.Errorf("%w: fixed value must be greater than zero", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown discount_type %q", ErrInvalidInput, v.DiscountType)
+	}
+	if v.MaxDiscount < 0 || v.MinSpend < 0 || v.UsageLimit < 0 || v.PerCustomerLimit < 0 {
+		return fmt.Errorf("%w: max_discount, min_spend and limits cannot be negative", ErrInvalidInput)
+	}
+	if v.StartAt != nil && v.EndAt != nil && !v.EndAt.After(*v.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidInput)
+	}
+	return nil
+}
+
+// voucherDiscount memeriksa apakah voucher bisa ditukar pada waktu now untuk
+// belanja sebesar base, lalu mengembalikan besar potongannya. customerUsed
+// adalah jumlah penukaran yang masih berlaku oleh customerID (0 jika tanpa pelanggan).
+func voucherDiscount(v models.Voucher, now time.Time, base, customerID, customerUsed int) (int, error) {
+	if !v.Active {
+		return 0, fmt.Errorf("%w: voucher %s is not active", ErrInvalidVoucher, v.Code)
+	}
+	if v.StartAt != nil && now.Before(*v.StartAt) {
+		return 0, fmt.Errorf("%w: voucher %s is not valid yet", ErrInvalidVoucher, v.Code)
+	}
+	if v.EndAt != nil && !now.Before(*v.EndAt) {
+		return 0, fmt.Errorf("%w: voucher %s has expired", ErrInvalidVoucher, v.Code)
+	}
+	if base < v.MinSpend {
+		return 0, fmt.Errorf("%w: voucher %s requires minimum spend %d (total: %d)", ErrInvalidVoucher, v.Code, v.MinSpend, base)
+	}
+	if v.UsageLimit > 0 && v.UsedCount >= v.UsageLimit {
+		return 0, fmt.Errorf("%w: voucher %s has been fully redeemed", ErrConflict, v.Code)
+	}
+	if v.PerCustomerLimit > 0 {
+		if customerID == 0 {
+			return 0, fmt.Errorf("%w: voucher %s requires a customer", ErrInvalidVoucher, v.Code)
+		}
+		if customerUsed >= v.PerCustomerLimit {
+			return 0, fmt.Errorf("%w: customer %d has already used voucher %s %d time(s)",
+				ErrConflict, customerID, v.Code, customerUsed)
+		}
+	}
+
+	amount := v.Value
+	if v.DiscountType == models.DiscountTypePercentage {
+		amount = base * v.Value / 100
+		if v.MaxDiscount > 0 {
+			amount = min(amount, v.MaxDiscount)
+		}
+	}
+	return min(amount, base), nil
+}
@@ -0,0 +1,253 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"kasir-api/models"
+)
+
+// voucherColumns adalah kolom vouchers sesuai urutan scanVoucher.
+const voucherColumns = `id, code, description, discount_type, value, max_discount, min_spend, start_at, end_at,
+	usage_limit, per_customer_limit, used_count, active, created_at`
+
+// AddVoucher menambahkan voucher baru. Kode disimpan dalam huruf besar.
+func (s *PostgresStore) AddVoucher(ctx context.Context, v models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(v); err != nil {
+		return models.Voucher{}, err
+	}
+	v.Code = normalizeVoucherCode(v.Code)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[voucher-store] Error begin transaction: %v", err)
+		return models.Voucher{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO vouchers (code, description, discount_type, value, max_discount, min_spend, start_at, end_at,
+			usage_limit, per_customer_limit, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, used_count, created_at
+	`, v.Code, v.Description, v.DiscountType, v.Value, v.MaxDiscount, v.MinSpend, v.StartAt, v.EndAt,
+		v.UsageLimit, v.PerCustomerLimit, v.Active,
+	).Scan(&v.ID, &v.UsedCount, &v.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.Voucher{}, fmt.Errorf("%w: voucher code %s already exists", ErrConflict, v.Code)
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error AddVoucher: %v", err)
+		return models.Voucher{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityVoucher, models.AuditActionCreate, v.ID, nil, v); err != nil {
+		return models.Voucher{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[voucher-store] Error commit AddVoucher: %v", err)
+		return models.Voucher{}, err
+	}
+
+	return v, nil
+}
+
+// GetAllVouchers mengembalikan semua voucher urut ID.
+func (s *PostgresStore) GetAllVouchers(ctx context.Context) ([]models.Voucher, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+voucherColumns+" FROM vouchers ORDER BY id")
+	if err != nil {
+		log.Printf("[voucher-store] Error GetAllVouchers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := []models.Voucher{}
+	for rows.Next() {
+		v, err := scanVoucher(rows)
+		if err != nil {
+			log.Printf("[voucher-store] Error scanning voucher row: %v", err)
+			continue
+		}
+		vouchers = append(vouchers, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Printf("[voucher-store] Error iterating voucher rows: %v", err)
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// GetVoucherByID mengembalikan satu voucher berdasarkan ID.
+func (s *PostgresStore) GetVoucherByID(ctx context.Context, id int) (models.Voucher, error) {
+	row := s.db.QueryRowContext(ctx, "SELECT "+voucherColumns+" FROM vouchers WHERE id = $1", id)
+	v, err := scanVoucher(row)
+	if err == sql.ErrNoRows {
+		return models.Voucher{}, fmt.Errorf("%w: voucher id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error GetVoucherByID: %v", err)
+		return models.Voucher{}, err
+	}
+	return v, nil
+}
+
+// UpdateVoucher mengganti aturan voucher berdasarkan ID. used_count tidak ikut
+// diubah karena hanya dikelola oleh checkout dan void.
+func (s *PostgresStore) UpdateVoucher(ctx context.Context, id int, v models.Voucher) (models.Voucher, error) {
+	if err := validateVoucher(v); err != nil {
+		return models.Voucher{}, err
+	}
+	v.Code = normalizeVoucherCode(v.Code)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[voucher-store] Error begin transaction: %v", err)
+		return models.Voucher{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := scanVoucher(tx.QueryRowContext(ctx, "SELECT "+voucherColumns+" FROM vouchers WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return models.Voucher{}, fmt.Errorf("%w: voucher id %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error lock voucher: %v", err)
+		return models.Voucher{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE vouchers
+		SET code = $1, description = $2, discount_type = $3, value = $4, max_discount = $5, min_spend = $6,
+			start_at = $7, end_at = $8, usage_limit = $9, per_customer_limit = $10, active = $11
+		WHERE id = $12
+		RETURNING id, used_count, created_at
+	`, v.Code, v.Description, v.DiscountType, v.Value, v.MaxDiscount, v.MinSpend, v.StartAt, v.EndAt,
+		v.UsageLimit, v.PerCustomerLimit, v.Active, id,
+	).Scan(&v.ID, &v.UsedCount, &v.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.Voucher{}, fmt.Errorf("%w: voucher code %s already exists", ErrConflict, v.Code)
+	}
+	if isCheckViolation(err) {
+		return models.Voucher{}, fmt.Errorf("%w: usage_limit is below the current used_count", ErrInvalidInput)
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error UpdateVoucher: %v", err)
+		return models.Voucher{}, err
+	}
+
+	if err := insertAudit(ctx, tx, models.AuditEntityVoucher, models.AuditActionUpdate, id, before, v); err != nil {
+		return models.Voucher{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[voucher-store] Error commit UpdateVoucher: %v", err)
+		return models.Voucher{}, err
+	}
+
+	return v, nil
+}
+
+// scanVoucher membaca satu baris voucher.
+func scanVoucher(row rowScanner) (models.Voucher, error) {
+	var v models.Voucher
+	var startAt, endAt sql.NullTime
+	err := row.Scan(&v.ID, &v.Code, &v.Description, &v.DiscountType, &v.Value, &v.MaxDiscount, &v.MinSpend,
+		&startAt, &endAt, &v.UsageLimit, &v.PerCustomerLimit, &v.UsedCount, &v.Active, &v.CreatedAt)
+	if err != nil {
+		return models.Voucher{}, err
+	}
+
+	if startAt.Valid {
+		v.StartAt = &startAt.Time
+	}
+	if endAt.Valid {
+		v.EndAt = &endAt.Time
+	}
+	return v, nil
+}
+
+// applyVoucher mengunci voucher berdasarkan kode, memeriksa apakah bisa ditukar,
+// lalu membagi potongannya ke details. Baris voucher tetap terkunci sampai
+// checkout selesai sehingga checkout bersamaan tidak bisa melewati batas penukaran.
+func applyVoucher(ctx context.Context, tx *sql.Tx, code string, customerID int, details []models.TransactionDetail) (*models.Voucher, int, error) {
+	code = normalizeVoucherCode(code)
+	row := tx.QueryRowContext(ctx, "SELECT "+voucherColumns+" FROM vouchers WHERE code = $1 FOR UPDATE", code)
+	v, err := scanVoucher(row)
+	if err == sql.ErrNoRows {
+		return nil, 0, fmt.Errorf("%w: voucher %s not found", ErrInvalidVoucher, code)
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error lock voucher: %v", err)
+		return nil, 0, err
+	}
+
+	customerUsed := 0
+	if customerID > 0 {
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM voucher_redemptions
+			WHERE voucher_id = $1 AND customer_id = $2 AND status = $3
+		`, v.ID, customerID, models.VoucherRedemptionRedeemed).Scan(&customerUsed)
+		if err != nil {
+			log.Printf("[voucher-store] Error count customer redemptions: %v", err)
+			return nil, 0, err
+		}
+	}
+
+	_, _, _, base := sumDetails(details)
+	amount, err := voucherDiscount(v, time.Now(), base, customerID, customerUsed)
+	if err != nil {
+		return nil, 0, err
+	}
+	allocateCartDiscount(details, amount)
+	return &v, amount, nil
+}
+
+// insertVoucherRedemption mencatat penukaran voucher untuk transaksi dan
+// menambah used_count voucher yang sudah dikunci applyVoucher.
+func insertVoucherRedemption(ctx context.Context, tx *sql.Tx, r models.VoucherRedemption) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO voucher_redemptions (voucher_id, transaction_id, customer_id, amount)
+		VALUES ($1, $2, $3, $4)
+	`, r.VoucherID, r.TransactionID, nullableID(r.CustomerID), r.Amount)
+	if err != nil {
+		log.Printf("[voucher-store] Error insert voucher redemption: %v", err)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE vouchers SET used_count = used_count + 1 WHERE id = $1", r.VoucherID); err != nil {
+		log.Printf("[voucher-store] Error increment voucher usage: %v", err)
+		return err
+	}
+	return nil
+}
+
+// releaseVoucherRedemption melepas penukaran voucher milik transaksi yang di-void
+// sehingga kuota voucher bisa dipakai lagi. Tidak melakukan apa-apa jika
+// transaksi tidak memakai voucher.
+func releaseVoucherRedemption(ctx context.Context, tx *sql.Tx, transactionID int) error {
+	var voucherID int
+	err := tx.QueryRowContext(ctx, `
+		UPDATE voucher_redemptions SET status = $1, released_at = CURRENT_TIMESTAMP
+		WHERE transaction_id = $2 AND status = $3
+		RETURNING voucher_id
+	`, models.VoucherRedemptionReleased, transactionID, models.VoucherRedemptionRedeemed).Scan(&voucherID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		log.Printf("[voucher-store] Error release voucher redemption: %v", err)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE vouchers SET used_count = used_count - 1 WHERE id = $1", voucherID); err != nil {
+		log.Printf("[voucher-store] Error decrement voucher usage: %v", err)
+		return err
+	}
+	return nil
+}